@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/gorilla/handlers"
 	"log"
 	"net/http"
+	"os"
 	"serviceNest/config"
+	"serviceNest/interfaces"
 	"serviceNest/repository"
 	"serviceNest/routers"
 	"serviceNest/service"
+	"serviceNest/util"
 )
 
 //var userService *service.UserService
@@ -17,34 +22,122 @@ import (
 //var providerService *service.ServiceProviderService
 //var adminService *service.AdminService
 
-func runApp(client *sql.DB) {
+// runApp starts the background scheduler and the HTTP server and blocks until ctx is cancelled
+func runApp(ctx context.Context, client *sql.DB) {
 	// initialize all repository
 	userRepo := repository.NewUserRepository(client)
 	householderRepo := repository.NewHouseholderRepository(client)
 	requestRepo := repository.NewServiceRequestRepository(client)
 	providerRepo := repository.NewServiceProviderRepository(client)
 	serviceRepo := repository.NewServiceRepository(client)
-	otpRepo := repository.NewOtpRepository()
+	requestEventRepo := repository.NewServiceRequestEventRepository(client)
+	ratingRepo := repository.NewRatingRepository(client)
+	serviceSearcher := repository.NewMySQLServiceSearcher(client)
+	availabilityRepo := repository.NewAvailabilityRepository(client)
+	seriesRepo := repository.NewBookingSeriesRepository(client)
+	quoteRepo := repository.NewQuoteRepository(client)
+	paymentRepo := repository.NewPaymentRepository(client)
+	invoiceRepo := repository.NewInvoiceRepository(client)
+	ledgerRepo := repository.NewLedgerRepository(client)
+	cancellationRepo := repository.NewCancellationRepository(client)
+	disputeRepo := repository.NewDisputeRepository(client)
+	sessionRepo := repository.NewSessionRepository(client)
+	otpStore := repository.NewMySQLOtpStore(client)
+	if os.Getenv(config.OTP_STORE_ENV) == "memory" {
+		otpStore = repository.NewMemoryOtpStore()
+	}
+	if !util.OTPSecretConfigured() {
+		log.Fatalf("%s must be set to hash OTPs", config.OTP_SECRET_ENV)
+	}
+	throttleRepo := repository.NewAuthThrottleRepository(client)
+	geocoder, err := repository.NewPincodeGeocoder(config.PINCODE_FILENAME)
+	if err != nil {
+		log.Printf("could not load pincode table, pincode geocoding disabled: %v", err)
+		geocoder = repository.NewPincodeGeocoderFromEntries(nil)
+	}
 
 	// initialize all services
-	userService := service.NewUserService(userRepo, otpRepo)
-	householderService := service.NewHouseholderService(householderRepo, providerRepo, serviceRepo, requestRepo)
-	providerService := service.NewServiceProviderService(providerRepo, requestRepo, serviceRepo)
-	adminService := service.NewAdminService(serviceRepo, requestRepo, userRepo, providerRepo)
+	userService := service.NewUserService(userRepo, otpStore, geocoder, sessionRepo)
+	householderService := service.NewHouseholderService(householderRepo, providerRepo, serviceRepo, requestRepo, requestEventRepo, ratingRepo, serviceSearcher, availabilityRepo, seriesRepo, quoteRepo, cancellationRepo)
+	providerService := service.NewServiceProviderService(providerRepo, requestRepo, serviceRepo, requestEventRepo, availabilityRepo, quoteRepo, cancellationRepo)
+	adminService := service.NewAdminService(serviceRepo, requestRepo, userRepo, providerRepo, ratingRepo, cancellationRepo, sessionRepo)
+
+	requestExpiryService := service.NewRequestExpiryService(requestRepo, providerRepo, requestEventRepo, quoteRepo)
+	// No real payment provider is integrated yet. The in-process fake gateway must be asked for explicitly
+	// and needs the webhook secret, or anyone could sign webhooks; without it payments stay disabled.
+	var paymentService interfaces.PaymentService
+	if os.Getenv(config.PAYMENT_GATEWAY_ENV) == config.FAKE_PAYMENT_GATEWAY {
+		webhookSecret := os.Getenv(config.PAYMENT_WEBHOOK_SECRET_ENV)
+		if webhookSecret == "" {
+			log.Fatalf("%s must be set to use the %s payment gateway", config.PAYMENT_WEBHOOK_SECRET_ENV, config.FAKE_PAYMENT_GATEWAY)
+		}
+		paymentGateway := repository.NewFakePaymentGateway(webhookSecret)
+		paymentService = service.NewPaymentService(paymentRepo, requestRepo, requestEventRepo, ledgerRepo, paymentGateway)
+	} else {
+		log.Printf("%s is not set to %q, payments are disabled", config.PAYMENT_GATEWAY_ENV, config.FAKE_PAYMENT_GATEWAY)
+	}
+	invoiceService := service.NewInvoiceService(invoiceRepo, requestRepo, quoteRepo, paymentRepo)
+	ledgerService := service.NewLedgerService(ledgerRepo)
+	disputeService := service.NewDisputeService(disputeRepo, requestRepo, requestEventRepo, paymentRepo, paymentService)
+	authService := service.NewAuthService(sessionRepo, userRepo)
+	throttleService := service.NewAuthThrottleService(throttleRepo)
+
+	// Background jobs run until the app shuts down
+	jobs := []service.Job{
+		{Name: "expire stale requests", Interval: config.EXPIRY_SWEEP_INTERVAL, Run: requestExpiryService.ExpireStaleRequests},
+		{Name: "generate booking series occurrences", Interval: config.SERIES_GENERATION_INTERVAL, Run: householderService.GenerateSeriesOccurrences},
+		{Name: "post earnings of completed jobs", Interval: config.LEDGER_POSTING_INTERVAL, Run: ledgerService.PostCompletedJobs},
+		{Name: "sweep expired OTPs", Interval: config.OTP_SWEEP_INTERVAL, Run: userService.PurgeExpiredOtps},
+	}
+	if paymentService != nil {
+		jobs = append(jobs, service.Job{Name: "capture payments of confirmed jobs", Interval: config.PAYMENT_CAPTURE_INTERVAL, Run: paymentService.CapturePendingPayments})
+		jobs = append(jobs, service.Job{Name: "settle payments of cancelled requests", Interval: config.PAYMENT_CAPTURE_INTERVAL, Run: paymentService.SettleCancelledPayments})
+	}
+	// Tokens are signed with the keyset when one is configured, and with the HS256 secret otherwise
+	if keySetFile := os.Getenv(config.JWT_KEYSET_FILE_ENV); keySetFile != "" {
+		if err := util.ReloadKeySet(keySetFile); err != nil {
+			log.Fatalf("could not load signing keyset: %v", err)
+		}
+		jobs = append(jobs, service.Job{Name: "reload signing keyset", Interval: config.JWT_KEYSET_RELOAD_INTERVAL, Run: func() error {
+			return util.ReloadKeySet(keySetFile)
+		}})
+	} else {
+		log.Printf("%s is not set, signing tokens with the HS256 secret", config.JWT_KEYSET_FILE_ENV)
+	}
+	scheduler := service.NewScheduler(jobs...)
+	scheduler.Start()
+	defer scheduler.Stop()
 
-	router := routers.SetupRouter(userService, householderService, providerService, adminService)
+	router := routers.SetupRouter(userService, householderService, providerService, adminService, paymentService, invoiceService, ledgerService, disputeService, authService, throttleService)
 
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "Health Good")
 
 	})
+	server := &http.Server{
+		Addr: config.PORT,
+		Handler: handlers.CORS(
+			handlers.AllowedOrigins([]string{"*"}),
+			handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
+			handlers.AllowedMethods([]string{"GET", "POST", "PUT", "PATCH", "OPTIONS", "DELETE"}),
+		)(router),
+	}
+
+	// Stop accepting requests once the app is asked to shut down and let in-flight ones finish
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.SHUTDOWN_TIMEOUT)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("server shutdown failed: %v", err)
+		}
+	}()
+
 	log.Println("Sever Starting on Port 8080...")
-	err := http.ListenAndServe(config.PORT, handlers.CORS(
-		handlers.AllowedOrigins([]string{"*"}),
-		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
-		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "PATCH", "OPTIONS", "DELETE"}),
-	)(router))
-	if err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal(err)
 	}
+	<-shutdownDone
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/joho/godotenv"
 	"log"
@@ -33,16 +34,12 @@ func main() {
 	}
 
 	// Handle interrupt signals for graceful shutdown
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		fmt.Println("\nDisconnecting from MySql...")
-		client.Close()
-		os.Exit(1)
-	}()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	logger.Info("Start the application..", nil)
-	runApp(client)
+	runApp(ctx, client)
+	fmt.Println("\nDisconnecting from MySql...")
 	//if err := runApp(); err != nil {
 	//	log.Fatal(err)
 	//}
@@ -1,6 +1,187 @@
 package config
 
+import "time"
+
 const FILENAME = "service_category.json"
 
 const PORT = ":8080"
 const SECRET = "SERVICE_NEST_KEY"
+
+// ACCESS_TOKEN_TTL is how long an access token is accepted; clients renew it with their refresh token
+const ACCESS_TOKEN_TTL = 15 * time.Minute
+
+// REFRESH_TOKEN_TTL is how long a refresh token can be exchanged; every exchange starts a new one, so
+// sessions last as long as they are used at least this often
+const REFRESH_TOKEN_TTL = 30 * 24 * time.Hour
+
+// ACCOUNT_LOCKOUT_THRESHOLD is how many failed logins, security answers or OTPs an account may have before
+// it is locked out; every failure after that doubles the lockout
+const ACCOUNT_LOCKOUT_THRESHOLD = 5
+
+// IP_LOCKOUT_THRESHOLD is the same for an IP address, which may be shared by many users
+const IP_LOCKOUT_THRESHOLD = 20
+
+// LOCKOUT_BASE_DURATION is the first lockout once the threshold is reached
+const LOCKOUT_BASE_DURATION = 30 * time.Second
+
+// LOCKOUT_MAX_DURATION caps the exponential backoff
+const LOCKOUT_MAX_DURATION = time.Hour
+
+// FAILED_ATTEMPT_RESET_WINDOW forgets the failures of an account or IP address after this long without one
+const FAILED_ATTEMPT_RESET_WINDOW = 24 * time.Hour
+
+// MAX_OTP_ATTEMPTS is how many wrong guesses invalidate an OTP
+const MAX_OTP_ATTEMPTS = 5
+
+// OTP_LENGTH is the number of digits in an OTP
+const OTP_LENGTH = 6
+
+// OTP_TTL is how long an OTP can be used after it was sent
+const OTP_TTL = 5 * time.Minute
+
+// OTP_SWEEP_INTERVAL is how often expired OTPs are deleted
+const OTP_SWEEP_INTERVAL = 10 * time.Minute
+
+// OTP_SECRET_ENV names the environment variable holding the server-side key OTPs are hashed with, so a
+// leaked OTP table cannot be brute-forced over the small code space
+const OTP_SECRET_ENV = "OTP_SECRET"
+
+// OTP_STORE_ENV names the environment variable choosing where OTPs are kept: "mysql", the default, or
+// "memory" for a single instance without the otps table
+const OTP_STORE_ENV = "OTP_STORE"
+
+// TRUST_PROXY_HEADERS_ENV names the environment variable that, set to "true", takes client IP addresses
+// from X-Forwarded-For; only enable it behind a proxy that sets the header
+const TRUST_PROXY_HEADERS_ENV = "TRUST_PROXY_HEADERS"
+
+// TRUSTED_PROXY_HOPS_ENV names the environment variable holding how many trusted proxies append to
+// X-Forwarded-For in front of the app, 1 if unset. The client address is that many entries from the
+// right; entries further left are set by the client and cannot be trusted.
+const TRUSTED_PROXY_HOPS_ENV = "TRUSTED_PROXY_HOPS"
+
+// JWT_KEYSET_FILE_ENV names the environment variable holding the path of the signing keyset file. Without
+// it tokens are signed with the HS256 secret.
+const JWT_KEYSET_FILE_ENV = "JWT_KEYSET_FILE"
+
+// JWT_KEYSET_RELOAD_INTERVAL is how often the keyset file is read again, so keys rotate without a restart
+const JWT_KEYSET_RELOAD_INTERVAL = 5 * time.Minute
+
+// JWKS_MAX_AGE is how long verifiers may cache the published public keys
+const JWKS_MAX_AGE = 5 * time.Minute
+
+// REVIEW_EDIT_WINDOW is how long after posting a householder may still edit or delete a review
+const REVIEW_EDIT_WINDOW = 7 * 24 * time.Hour
+
+// PINCODE_FILENAME is the offline pincode centroid table used for geocoding
+const PINCODE_FILENAME = "pincode_centroids.json"
+
+// DEFAULT_SEARCH_RADIUS_KM is used when neither the householder nor the provider limits the search radius
+const DEFAULT_SEARCH_RADIUS_KM = 25.0
+
+// MAX_SEARCH_RADIUS_KM is the largest radius a householder may ask to search within
+const MAX_SEARCH_RADIUS_KM = 500.0
+
+// SEARCH_CANDIDATE_LIMIT caps how many FULLTEXT matches are re-ranked per search
+const SEARCH_CANDIDATE_LIMIT = 200
+
+// DEFAULT_SLOT_MINUTES is the slot length used until a provider publishes their own
+const DEFAULT_SLOT_MINUTES = 60
+
+// DEFAULT_JOB_MINUTES is the job duration assumed for services without an estimated duration
+const DEFAULT_JOB_MINUTES = 60
+
+// TRAVEL_BUFFER is the gap a provider needs between two jobs; shorter gaps are reported as warnings
+const TRAVEL_BUFFER = 30 * time.Minute
+
+// DEFAULT_TIMEZONE is used to read and render times for users without a preferred timezone
+const DEFAULT_TIMEZONE = "Asia/Kolkata"
+
+// SERIES_GENERATION_HORIZON is how far ahead occurrences of a booking series are materialised as requests
+const SERIES_GENERATION_HORIZON = 14 * 24 * time.Hour
+
+// SERIES_GENERATION_INTERVAL is how often the booking series generator runs
+const SERIES_GENERATION_INTERVAL = time.Hour
+
+// SERIES_UPCOMING_PREVIEW is how many upcoming occurrences are listed when viewing a booking series
+const SERIES_UPCOMING_PREVIEW = 5
+
+// CANCELLATION_NOTICE is how long ahead a booking can be cancelled free of charge where no cancellation
+// policy says otherwise
+const CANCELLATION_NOTICE = 4 * time.Hour
+
+// DEFAULT_LATE_CANCELLATION_FEE_BASIS_POINTS is the share of the price charged for cancelling inside the
+// free window where no cancellation policy says otherwise (5000 is 50%)
+const DEFAULT_LATE_CANCELLATION_FEE_BASIS_POINTS = 5000
+
+// DEFAULT_NO_SHOW_FEE_BASIS_POINTS is the share of the price charged for cancelling once the booking was
+// due to start where no cancellation policy says otherwise
+const DEFAULT_NO_SHOW_FEE_BASIS_POINTS = 10000
+
+// DEFAULT_PROVIDER_LATE_CANCELLATION_PENALTY and DEFAULT_PROVIDER_NO_SHOW_PENALTY are the reliability
+// points a provider loses for cancelling inside the free window or after the scheduled time
+const DEFAULT_PROVIDER_LATE_CANCELLATION_PENALTY = 3
+const DEFAULT_PROVIDER_NO_SHOW_PENALTY = 5
+
+// PROVIDER_CANCELLATION_PENALTY is the least a provider loses for cancelling an approved booking
+const PROVIDER_CANCELLATION_PENALTY = 1
+
+// MAX_CANCELLATION_FREE_WINDOW_HOURS caps the free cancellation window a policy may set
+const MAX_CANCELLATION_FREE_WINDOW_HOURS = 30 * 24
+
+// REQUEST_EXPIRY_LEAD is how long before its scheduled time a request that has not been approved expires
+const REQUEST_EXPIRY_LEAD = time.Hour
+
+// EXPIRY_SWEEP_INTERVAL is how often stale requests are expired and unapproved quotes declined
+const EXPIRY_SWEEP_INTERVAL = 5 * time.Minute
+
+// SHUTDOWN_TIMEOUT bounds how long in-flight HTTP requests may take to finish on shutdown
+const SHUTDOWN_TIMEOUT = 10 * time.Second
+
+// DEFAULT_QUOTE_VALIDITY is how long a quote stays open when no validity is given
+const DEFAULT_QUOTE_VALIDITY = 48 * time.Hour
+
+// PAYMENT_GATEWAY_ENV names the environment variable choosing the payment gateway. No real provider is
+// integrated yet, so payments are disabled unless it is set to FAKE_PAYMENT_GATEWAY for development.
+const PAYMENT_GATEWAY_ENV = "PAYMENT_GATEWAY"
+
+// FAKE_PAYMENT_GATEWAY selects the in-process gateway that charges nothing; for development and tests only
+const FAKE_PAYMENT_GATEWAY = "fake"
+
+// PAYMENT_WEBHOOK_SECRET_ENV names the environment variable holding the secret payment webhooks are signed with
+const PAYMENT_WEBHOOK_SECRET_ENV = "PAYMENT_WEBHOOK_SECRET"
+
+// PAYMENT_SIGNATURE_HEADER carries the signature of a payment webhook payload
+const PAYMENT_SIGNATURE_HEADER = "X-Payment-Signature"
+
+// PAYMENT_CAPTURE_INTERVAL is how often authorized payments of confirmed jobs are captured
+const PAYMENT_CAPTURE_INTERVAL = 5 * time.Minute
+
+// INVOICE_TAX_LABEL names the tax broken out on invoices
+const INVOICE_TAX_LABEL = "GST"
+
+// INVOICE_TAX_RATE_BASIS_POINTS is the tax rate included in every price, in basis points (1800 is 18%)
+const INVOICE_TAX_RATE_BASIS_POINTS = 1800
+
+// PLATFORM_COMMISSION_BASIS_POINTS is the platform's share of every job price, in basis points (1000 is 10%)
+const PLATFORM_COMMISSION_BASIS_POINTS = 1000
+
+// MAX_DISPUTE_PENALTY_POINTS caps the reliability points an admin can take off a provider in one dispute
+const MAX_DISPUTE_PENALTY_POINTS = 10
+
+// LEDGER_POSTING_INTERVAL is how often the earnings of completed jobs are posted to the ledger
+const LEDGER_POSTING_INTERVAL = 5 * time.Minute
+
+// LEDGER_POSTING_BATCH caps how many completed jobs are posted per run
+const LEDGER_POSTING_BATCH = 500
+
+// PAYOUT_LOCK_NAME names the database lock held while a payout batch is created, and
+// PAYOUT_LOCK_TIMEOUT_SECONDS is how long a second batch waits for it
+const PAYOUT_LOCK_NAME = "servicenest_payout_batch"
+const PAYOUT_LOCK_TIMEOUT_SECONDS = 10
+
+// PROVIDER_LOCK_PREFIX and REQUEST_LOCK_PREFIX name the database locks that serialize changes to one
+// provider's schedule and to one request's quotes; RECORD_LOCK_TIMEOUT_SECONDS is how long a caller
+// waits for either
+const PROVIDER_LOCK_PREFIX = "servicenest_provider_"
+const REQUEST_LOCK_PREFIX = "servicenest_request_"
+const RECORD_LOCK_TIMEOUT_SECONDS = 10
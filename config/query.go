@@ -155,9 +155,10 @@ func SelectJsonDataQuery(withStatus bool) string {
                     'name', service_provider_details.name,
                     'contact', service_provider_details.contact,
                     'address', service_provider_details.address,
-                    'price', service_provider_details.price,
+                    'price', JSON_OBJECT('amount_minor', service_provider_details.price_minor, 'currency', service_provider_details.currency),
                     'rating', service_provider_details.rating,
-                    'approve', service_provider_details.approve
+                    'approve', service_provider_details.approve,
+                    'declined', service_provider_details.declined
                 )
                 SEPARATOR ', '
             ),
@@ -204,9 +205,10 @@ func SelectJsonDataQueryWithApprove(sortOrder string) string {
 						'name', service_provider_details.name,
 						'contact', service_provider_details.contact,
 						'address', service_provider_details.address,
-						'price', service_provider_details.price,
+						'price', JSON_OBJECT('amount_minor', service_provider_details.price_minor, 'currency', service_provider_details.currency),
 						'rating', service_provider_details.rating,
-						'approve', service_provider_details.approve
+						'approve', service_provider_details.approve,
+						'declined', service_provider_details.declined
 					)
 					SEPARATOR ', '
 				),
@@ -240,7 +242,8 @@ func ViewPendingRequestByProvider(serviceID string) string {
         FROM service_requests sr
         LEFT JOIN service_provider_details spd 
         ON sr.id = spd.service_request_id AND spd.service_provider_id = ?
-        WHERE spd.service_request_id IS NULL AND (sr.status="pending" OR sr.status="accepted")`
+        WHERE spd.service_request_id IS NULL AND sr.status IN ('Pending', 'Accepted')
+            AND sr.scheduled_time > UTC_TIMESTAMP()`
 
 	// Add a filter for service_id if provided
 	if serviceID != "" {
@@ -323,5 +326,434 @@ func SelectQueryWithLimit(tableName, condition1, condition2 string, columns []st
 }
 
 func CountReviewAddedQuery() string {
-	return `SELECT COUNT(*) FROM reviews WHERE provider_id = ? AND service_id = ? AND householder_id = ?`
+	return `SELECT COUNT(*) FROM reviews WHERE service_request_id = ?`
+}
+
+func LockServiceRequestQuery() string {
+	return `SELECT id FROM service_requests WHERE id = ? FOR UPDATE`
+}
+
+// The rating queries below recompute aggregates straight from the reviews table, either for every
+// row or, when scoped, only for one provider (and service).
+
+func UpdateServiceRatingQuery(scoped bool) string {
+	query := `
+		UPDATE services s
+		SET s.avg_rating = (SELECT COALESCE(AVG(r.rating), 0) FROM reviews r WHERE r.provider_id = s.provider_id AND r.service_id = s.id),
+			s.rating_count = (SELECT COUNT(*) FROM reviews r WHERE r.provider_id = s.provider_id AND r.service_id = s.id)`
+	if scoped {
+		query += ` WHERE s.provider_id = ? AND s.id = ?`
+	}
+	return query
+}
+
+func UpdateProviderServiceRatingQuery(scoped bool) string {
+	query := `
+		UPDATE service_providers_services sps
+		SET sps.avg_rating = (SELECT COALESCE(AVG(r.rating), 0) FROM reviews r WHERE r.provider_id = sps.service_provider_id AND r.service_id = sps.service_id),
+			sps.rating_count = (SELECT COUNT(*) FROM reviews r WHERE r.provider_id = sps.service_provider_id AND r.service_id = sps.service_id)`
+	if scoped {
+		query += ` WHERE sps.service_provider_id = ? AND sps.service_id = ?`
+	}
+	return query
+}
+
+func UpdateProviderDetailRatingQuery(scoped bool) string {
+	query := `
+		UPDATE service_provider_details spd
+		SET spd.rating = (SELECT COALESCE(AVG(r.rating), 0) FROM reviews r WHERE r.provider_id = spd.service_provider_id AND r.service_id = spd.service_id)`
+	if scoped {
+		query += ` WHERE spd.service_provider_id = ? AND spd.service_id = ?`
+	}
+	return query
+}
+
+func UpdateProviderRatingQuery(scoped bool) string {
+	query := `
+		UPDATE service_providers sp
+		SET sp.rating = (SELECT COALESCE(AVG(r.rating), 0) FROM reviews r WHERE r.provider_id = sp.user_id)`
+	if scoped {
+		query += ` WHERE sp.user_id = ?`
+	}
+	return query
+}
+
+func LastReviewDatesQuery() string {
+	return `SELECT provider_id, service_id, MAX(review_date) FROM reviews GROUP BY provider_id, service_id`
+}
+
+func ProviderJobStatsQuery() string {
+	return `
+		SELECT spd.service_provider_id, COUNT(*), COALESCE(SUM(sr.status = ?), 0)
+		FROM service_provider_details spd
+		INNER JOIN service_requests sr ON sr.id = spd.service_request_id
+		WHERE spd.approve = 1
+		GROUP BY spd.service_provider_id`
+}
+
+// ProviderPenaltyPointsQuery sums the reliability penalty points providers collected by cancelling bookings
+// and in resolved disputes
+func ProviderPenaltyPointsQuery() string {
+	return `
+		SELECT provider_id, SUM(points)
+		FROM (
+			SELECT provider_id, penalty_points AS points FROM cancellation_charges
+			UNION ALL
+			SELECT provider_id, penalty_points AS points FROM disputes WHERE status = 'Resolved'
+		) penalties
+		WHERE points > 0
+		GROUP BY provider_id`
+}
+
+func ProvidersByServiceTypeQuery() string {
+	return `
+		SELECT sp.user_id, u.name, u.address, u.contact, u.latitude, u.longitude, sp.rating, sp.availability, sp.is_active, sp.service_radius_km
+		FROM service_providers sp
+		INNER JOIN users u ON u.id = sp.user_id
+		INNER JOIN service_providers_services sps ON sp.user_id = sps.service_provider_id
+		INNER JOIN services s ON sps.service_id = s.id
+		WHERE s.name = ?`
+}
+
+// SearchServicesQuery fetches the most relevant FULLTEXT candidates for a boolean-mode query over
+// service fields and provider names; it takes the query four times followed by the candidate limit
+func SearchServicesQuery() string {
+	return `
+		SELECT s.id, s.name, s.description, s.price_minor, s.currency, s.provider_id, s.category, s.avg_rating, s.rating_count, u.name, u.contact, u.address
+		FROM services s
+		LEFT JOIN users u ON u.id = s.provider_id
+		WHERE MATCH(s.name, s.description, s.category) AGAINST(? IN BOOLEAN MODE)
+			OR MATCH(u.name) AGAINST(? IN BOOLEAN MODE)
+		ORDER BY MATCH(s.name, s.description, s.category) AGAINST(? IN BOOLEAN MODE)
+			+ MATCH(u.name) AGAINST(? IN BOOLEAN MODE) DESC, s.id
+		LIMIT ?`
+}
+
+// ProviderScheduleQuery selects a provider's availability, slot length and the timezone their working
+// hours are in: the schedule's own, else the provider's
+func ProviderScheduleQuery() string {
+	return `
+		SELECT sp.availability, sp.slot_minutes, COALESCE(sp.schedule_timezone, u.timezone, '')
+		FROM service_providers sp
+		LEFT JOIN users u ON u.id = sp.user_id
+		WHERE sp.user_id = ?`
+}
+
+// BookedTimesQuery lists the scheduled start and estimated minutes of every job a provider has been
+// approved for starting within [from, to), skipping the given request so it can be rescheduled against
+// its own slot
+func BookedTimesQuery() string {
+	return `
+		SELECT sr.scheduled_time, COALESCE(s.estimated_minutes, 0)
+		FROM service_requests sr
+		INNER JOIN service_provider_details spd ON spd.service_request_id = sr.id
+		LEFT JOIN services s ON s.id = sr.service_id AND s.provider_id = spd.service_provider_id
+		WHERE spd.service_provider_id = ? AND spd.approve = 1
+			AND sr.status IN ('Approved', 'InProgress')
+			AND sr.scheduled_time >= ? AND sr.scheduled_time < ?
+			AND sr.id <> ?`
+}
+
+func ProviderIDsByServiceQuery() string {
+	return `
+		SELECT s.provider_id
+		FROM services s
+		INNER JOIN service_providers sp ON sp.user_id = s.provider_id
+		WHERE s.id = ? AND sp.is_active = 1`
+}
+
+func UpcomingTimeOffQuery() string {
+	return `SELECT id, start_time, end_time, reason FROM provider_time_off WHERE provider_id = ? AND end_time > ? ORDER BY start_time`
+}
+
+// ProviderCommitmentsQuery lists the jobs a provider is committed to in (from, to): requests they
+// quoted on that are still awaiting approval, and requests they were approved for
+func ProviderCommitmentsQuery() string {
+	return `
+		SELECT sr.id, sr.scheduled_time, COALESCE(s.estimated_minutes, 0)
+		FROM service_requests sr
+		INNER JOIN service_provider_details spd ON spd.service_request_id = sr.id
+		LEFT JOIN services s ON s.id = sr.service_id AND s.provider_id = spd.service_provider_id
+		WHERE spd.service_provider_id = ? AND sr.id <> ?
+			AND (sr.status = 'Accepted' OR (sr.status IN ('Approved', 'InProgress') AND spd.approve = 1))
+			AND sr.scheduled_time > ? AND sr.scheduled_time < ?`
+}
+
+// UpdateSeriesProgressQuery records the generator's progress on a series that is still active, and
+// completes it when asked to
+func UpdateSeriesProgressQuery() string {
+	return `UPDATE booking_series SET generated_count = ?, status = IF(?, 'Completed', status) WHERE id = ? AND status = 'Active'`
+}
+
+// SeriesRequestIDsQuery lists the requests materialised from a booking series that are scheduled at
+// or after the given time, earliest first
+func SeriesRequestIDsQuery() string {
+	return `SELECT id FROM service_requests WHERE series_id = ? AND scheduled_time >= ? ORDER BY scheduled_time`
+}
+
+// StaleRequestIDsQuery lists requests still waiting for approval whose scheduled time is at or before
+// the expiry deadline
+func StaleRequestIDsQuery() string {
+	return `SELECT id FROM service_requests WHERE status IN ('Pending', 'Accepted') AND approve_status = 0 AND scheduled_time <= ?`
+}
+
+// ExpireRequestQuery expires a request only if it still has the status it was listed with and is
+// still unapproved
+func ExpireRequestQuery() string {
+	return `UPDATE service_requests SET status = 'Expired' WHERE id = ? AND status = ? AND status IN ('Pending', 'Accepted') AND approve_status = 0`
+}
+
+// ApproveQuoteQuery approves a quote that is still open at the version the householder saw
+func ApproveQuoteQuery() string {
+	return `UPDATE quotes SET status = 'Approved' WHERE id = ? AND status = 'Open' AND version = ?`
+}
+
+// DeclineUnapprovedQuotesQuery declines every open quote the householder can no longer approve: quotes
+// on requests that expired or were cancelled, and quotes that lost to another provider's approved quote
+func DeclineUnapprovedQuotesQuery() string {
+	return `
+		UPDATE service_provider_details spd
+		INNER JOIN service_requests sr ON sr.id = spd.service_request_id
+		SET spd.declined = 1
+		WHERE spd.approve = 0 AND spd.declined = 0
+			AND (sr.status IN ('Expired', 'Cancelled') OR sr.approve_status = 1)`
+}
+
+// ExpireLapsedQuotesQuery expires open quote versions whose validity has passed
+func ExpireLapsedQuotesQuery() string {
+	return `UPDATE quotes SET status = 'Expired' WHERE status = 'Open' AND valid_until <= ?`
+}
+
+// DeclineOtherOpenQuotesQuery declines the open quotes of every other provider once one quote is approved
+func DeclineOtherOpenQuotesQuery() string {
+	return `UPDATE quotes SET status = 'Declined' WHERE service_request_id = ? AND provider_id <> ? AND status = 'Open'`
+}
+
+// DeclineStaleQuotesQuery declines open quote versions the householder can no longer approve: quotes
+// on requests that expired or were cancelled, and quotes that lost to another provider's approved quote
+func DeclineStaleQuotesQuery() string {
+	return `
+		UPDATE quotes q
+		INNER JOIN service_requests sr ON sr.id = q.service_request_id
+		SET q.status = 'Declined'
+		WHERE q.status = 'Open'
+			AND (sr.status IN ('Expired', 'Cancelled') OR sr.approve_status = 1)`
+}
+
+// CapturablePaymentsQuery lists payments held until completion whose request the householder has
+// confirmed as done, and immediate payments whose capture after authorization did not go through.
+// Payments of cancelled requests are settled by CancelledPaymentsQuery instead.
+func CapturablePaymentsQuery(columns []string) string {
+	prefixed := make([]string, len(columns))
+	for i, column := range columns {
+		prefixed[i] = "p." + column
+	}
+	return fmt.Sprintf(`
+		SELECT %s
+		FROM payments p
+		INNER JOIN service_requests sr ON sr.id = p.service_request_id
+		WHERE p.status = 'Authorized' AND sr.status <> 'Cancelled'
+			AND (p.capture_mode = 'immediate'
+				OR (p.capture_mode = 'on_completion' AND sr.status = 'Completed' AND sr.completion_confirmed = 1))`, strings.Join(prefixed, ", "))
+}
+
+// CancelledPaymentsQuery lists the payments of cancelled requests that still hold or keep more than the
+// householder's cancellation fee, followed by that fee: payments not captured yet, and captured payments
+// not refunded down to the fee
+func CancelledPaymentsQuery(columns []string) string {
+	prefixed := make([]string, len(columns))
+	for i, column := range columns {
+		prefixed[i] = "p." + column
+	}
+	return fmt.Sprintf(`
+		SELECT %s, COALESCE(cc.fee_minor, 0)
+		FROM payments p
+		INNER JOIN service_requests sr ON sr.id = p.service_request_id
+		LEFT JOIN (
+			SELECT service_request_id, SUM(fee_minor) AS fee_minor
+			FROM cancellation_charges
+			WHERE cancelled_by = 'Householder'
+			GROUP BY service_request_id
+		) cc ON cc.service_request_id = p.service_request_id
+		WHERE sr.status = 'Cancelled'
+			AND (p.status IN ('Pending', 'Authorized')
+				OR (p.status IN ('Captured', 'PartiallyRefunded') AND p.refunded_minor < p.amount_minor - COALESCE(cc.fee_minor, 0)))`, strings.Join(prefixed, ", "))
+}
+
+// ReserveRefundQuery adds a refund to a captured payment, provided it stays within what was paid. MySQL
+// assigns left to right, so the status is decided on the new refunded amount.
+func ReserveRefundQuery() string {
+	return `
+		UPDATE payments
+		SET refunded_minor = refunded_minor + ?,
+			status = IF(refunded_minor = amount_minor, 'Refunded', 'PartiallyRefunded'),
+			updated_at = ?
+		WHERE id = ? AND status IN ('Captured', 'PartiallyRefunded') AND refunded_minor + ? <= amount_minor`
+}
+
+// ReleaseRefundQuery takes back a reserved refund the gateway did not pay out
+func ReleaseRefundQuery() string {
+	return `
+		UPDATE payments
+		SET refunded_minor = refunded_minor - ?,
+			status = IF(refunded_minor = 0, 'Captured', 'PartiallyRefunded'),
+			updated_at = ?
+		WHERE id = ? AND refunded_minor >= ?`
+}
+
+// RecordWebhookEventQuery stores a processed payment webhook event; an event already stored inserts nothing
+func RecordWebhookEventQuery() string {
+	return `INSERT IGNORE INTO payment_webhook_events (gateway, event_id, type, intent_id, processed_at) VALUES (?, ?, ?, ?, ?)`
+}
+
+// NextInvoiceSequenceQuery advances a provider's invoice counter, creating it at 1 for their first
+// invoice. The counter row stays locked until the transaction ends, so invoices are numbered one at a
+// time and a rolled back invoice gives its number back.
+func NextInvoiceSequenceQuery() string {
+	return `
+		INSERT INTO invoice_sequences (provider_id, last_number) VALUES (?, 1)
+		ON DUPLICATE KEY UPDATE last_number = last_number + 1`
+}
+
+// UnpostedCompletedJobsQuery lists completed requests, with their approved provider and price, whose
+// earnings have not been posted to the ledger yet, oldest first. Free jobs earn nothing and are left out,
+// so they never take up a batch.
+func UnpostedCompletedJobsQuery() string {
+	return `
+		SELECT sr.id, spd.service_provider_id, sr.service_id, COALESCE(s.name, ''), spd.price_minor, spd.currency,
+			COALESCE(sr.actual_end_time, sr.scheduled_time) AS completed_at
+		FROM service_requests sr
+		INNER JOIN service_provider_details spd ON spd.service_request_id = sr.id AND spd.approve = 1
+		LEFT JOIN services s ON s.id = sr.service_id
+		LEFT JOIN ledger_journals j ON j.kind = 'earning' AND j.reference = sr.id
+		WHERE sr.status = 'Completed' AND spd.price_minor > 0 AND j.id IS NULL
+		ORDER BY completed_at, sr.id
+		LIMIT ?`
+}
+
+// LedgerJournalsQuery selects journals with their entries, one row per entry, filtered by condition
+func LedgerJournalsQuery(condition string) string {
+	return `
+		SELECT j.id, j.kind, j.reference, j.provider_id, COALESCE(j.service_request_id, ''), COALESCE(j.service_id, ''),
+			j.service_name, j.currency, j.occurred_at, e.account, COALESCE(e.provider_id, ''), e.amount_minor
+		FROM ledger_journals j
+		INNER JOIN ledger_entries e ON e.journal_id = j.id
+		WHERE ` + condition + `
+		ORDER BY j.occurred_at, j.id, e.position`
+}
+
+// PayableBalancesQuery sums the provider_payable account per provider and currency; credits are what
+// the platform owes, so the balance is the negated sum of the entries
+func PayableBalancesQuery(forProvider bool) string {
+	condition := ""
+	if forProvider {
+		condition = " AND e.provider_id = ?"
+	}
+	return `
+		SELECT e.provider_id, j.currency, -SUM(e.amount_minor) AS balance
+		FROM ledger_entries e
+		INNER JOIN ledger_journals j ON j.id = e.journal_id
+		WHERE e.account = 'provider_payable'` + condition + `
+		GROUP BY e.provider_id, j.currency
+		HAVING balance <> 0
+		ORDER BY e.provider_id, j.currency`
+}
+
+// InsertLedgerJournalQuery stores a journal unless one with the same kind, reference, provider and currency
+// exists
+func InsertLedgerJournalQuery() string {
+	return `
+		INSERT IGNORE INTO ledger_journals (id, kind, reference, provider_id, service_request_id, service_id, service_name, currency, occurred_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+}
+
+// AcquireLockQuery takes a named lock for the connection, waiting up to the given seconds; it returns 1
+// once the lock is held
+func AcquireLockQuery() string {
+	return `SELECT GET_LOCK(?, ?)`
+}
+
+func ReleaseLockQuery() string {
+	return `SELECT RELEASE_LOCK(?)`
+}
+
+// SaveCancellationPolicyQuery stores a policy, replacing the one already set for the same scope
+func SaveCancellationPolicyQuery() string {
+	return `
+		INSERT INTO cancellation_policies (id, scope, scope_id, free_window_hours, late_fee_basis_points, no_show_fee_basis_points, provider_late_penalty, provider_no_show_penalty, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE free_window_hours = VALUES(free_window_hours), late_fee_basis_points = VALUES(late_fee_basis_points),
+			no_show_fee_basis_points = VALUES(no_show_fee_basis_points), provider_late_penalty = VALUES(provider_late_penalty),
+			provider_no_show_penalty = VALUES(provider_no_show_penalty), updated_at = VALUES(updated_at)`
+}
+
+// DisputesByStatusQuery selects the disputes in any of statusCount statuses, oldest first, as the admin
+// queue works through them
+func DisputesByStatusQuery(columns []string, statusCount, limit, offset int) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", statusCount), ", ")
+	query := fmt.Sprintf("SELECT %s FROM disputes WHERE status IN (%s) ORDER BY created_at, id", strings.Join(columns, ", "), placeholders)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	}
+	return query
+}
+
+// UseRefreshTokenQuery marks a refresh token used unless it already was
+func UseRefreshTokenQuery() string {
+	return `UPDATE refresh_tokens SET used_at = ? WHERE token_hash = ? AND used_at IS NULL`
+}
+
+// RevokeSessionsQuery revokes the sessions matching condition that are not revoked yet
+func RevokeSessionsQuery(condition string) string {
+	return `UPDATE auth_sessions SET revoked_at = ?, revoke_reason = ? WHERE ` + condition + ` = ? AND revoked_at IS NULL`
+}
+
+// RecordAuthFailureQuery counts a failed attempt against an account or IP address, starting the count over
+// when the last failure is older than the reset window
+func RecordAuthFailureQuery() string {
+	return `
+		INSERT INTO auth_throttles (subject, throttle_key, failures, last_failure_at)
+		VALUES (?, ?, 1, ?)
+		ON DUPLICATE KEY UPDATE failures = IF(last_failure_at < ?, 1, failures + 1), last_failure_at = VALUES(last_failure_at)`
+}
+
+// CreditAuthFailureQuery takes back an attempt counted against an account or IP address that turned out
+// not to have failed
+func CreditAuthFailureQuery() string {
+	return `UPDATE auth_throttles SET failures = GREATEST(failures - 1, 0) WHERE subject = ? AND throttle_key = ?`
+}
+
+// ClaimAuthLockQuery locks out an account or IP address unless it is locked out already, so of concurrent
+// attempts past the threshold only the one that set the lockout goes ahead
+func ClaimAuthLockQuery() string {
+	return `UPDATE auth_throttles SET locked_until = ? WHERE subject = ? AND throttle_key = ? AND (locked_until IS NULL OR locked_until <= ?)`
+}
+
+// FailedAuthAttemptsQuery selects the audit log of failed attempts, newest first, optionally for one email
+func FailedAuthAttemptsQuery(columns []string, byEmail bool, limit, offset int) string {
+	query := fmt.Sprintf("SELECT %s FROM auth_failures", strings.Join(columns, ", "))
+	if byEmail {
+		query += " WHERE email = ?"
+	}
+	return query + fmt.Sprintf(" ORDER BY created_at DESC LIMIT %d OFFSET %d", limit, offset)
+}
+
+// SaveOtpQuery stores an OTP, replacing the previous one sent to the same email for the same purpose
+func SaveOtpQuery() string {
+	return `
+		INSERT INTO otps (email, purpose, code_hash, attempts, created_at, expires_at)
+		VALUES (?, ?, ?, 0, ?, ?)
+		ON DUPLICATE KEY UPDATE code_hash = VALUES(code_hash), attempts = 0, created_at = VALUES(created_at),
+			expires_at = VALUES(expires_at)`
+}
+
+// LockOtpQuery reads an OTP and locks it until the transaction ends, so concurrent guesses are counted
+// one after another
+func LockOtpQuery() string {
+	return `SELECT code_hash, attempts, expires_at FROM otps WHERE email = ? AND purpose = ? FOR UPDATE`
+}
+
+// DeleteExpiredOtpsQuery deletes the OTPs that expired by the given time
+func DeleteExpiredOtpsQuery() string {
+	return `DELETE FROM otps WHERE expires_at <= ?`
 }
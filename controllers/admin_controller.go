@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"github.com/gorilla/mux"
 	"net/http"
+	"serviceNest/errs"
 	"serviceNest/interfaces"
 	"serviceNest/logger"
+	"serviceNest/model"
 	"serviceNest/response"
 	"serviceNest/util"
 )
@@ -136,3 +138,74 @@ func (a *AdminController) ViewUserDetail(w http.ResponseWriter, r *http.Request)
 	logger.Info("All services fetched successfully", nil)
 	response.SuccessResponse(w, userDetail, "User fetch successfully", http.StatusOK)
 }
+
+// RecomputeRatings rebuilds every stored rating from the reviews table
+func (a *AdminController) RecomputeRatings(w http.ResponseWriter, r *http.Request) {
+	err := a.adminService.RecomputeRatings()
+	if err != nil {
+		logger.Error("error recomputing ratings", map[string]interface{}{"error": err.Error()})
+		response.ErrorResponse(w, http.StatusInternalServerError, "Error recomputing ratings", 1006)
+		return
+	}
+
+	logger.Info("Ratings recomputed successfully", nil)
+	response.SuccessResponse(w, nil, "Ratings recomputed successfully", http.StatusOK)
+}
+
+// ViewCancellationPolicies lists the cancellation policies set for providers and categories
+func (a *AdminController) ViewCancellationPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := a.adminService.GetCancellationPolicies()
+	if err != nil {
+		logger.Error("error fetching cancellation policies", map[string]interface{}{"error": err.Error()})
+		response.ErrorResponse(w, http.StatusInternalServerError, "Error fetching cancellation policies", 1003)
+		return
+	}
+	response.SuccessResponse(w, policies, "Cancellation policies fetched successfully", http.StatusOK)
+}
+
+// SaveCancellationPolicy sets the cancellation policy of the provider or category named in the body
+func (a *AdminController) SaveCancellationPolicy(w http.ResponseWriter, r *http.Request) {
+	var policy model.CancellationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+
+	saved, err := a.adminService.SaveCancellationPolicy(&policy)
+	if err != nil {
+		logger.Error("error saving cancellation policy", map[string]interface{}{"error": err.Error()})
+		switch err.Error() {
+		case errs.InvalidCancellationPolicy:
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		case errs.ProviderNotFound, errs.CategoryNotFound:
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, "Error saving cancellation policy", 1006)
+		}
+		return
+	}
+
+	logger.Info("Cancellation policy saved", map[string]interface{}{"policyID": saved.ID, "scope": saved.Scope, "scopeID": saved.ScopeID})
+	response.SuccessResponse(w, saved, "Cancellation policy saved successfully", http.StatusOK)
+}
+
+// DeleteCancellationPolicy removes a cancellation policy
+func (a *AdminController) DeleteCancellationPolicy(w http.ResponseWriter, r *http.Request) {
+	policyID, ok := mux.Vars(r)["policy_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing policy Id in params", 2002)
+		return
+	}
+
+	if err := a.adminService.DeleteCancellationPolicy(policyID); err != nil {
+		logger.Error("error deleting cancellation policy", map[string]interface{}{"policyID": policyID, "error": err.Error()})
+		if err.Error() == errs.CancellationPolicyNotFound {
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Error deleting cancellation policy", 1006)
+		return
+	}
+	response.SuccessResponse(w, nil, "Cancellation policy deleted successfully", http.StatusOK)
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	"net/http"
+	"serviceNest/errs"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/response"
+	"serviceNest/util"
+)
+
+// CreateBookingSeries books a recurring service. The first occurrence is given as scheduled_time and
+// later ones follow the recurrence rule in the householder's timezone unless another one is given.
+func (h *HouseholderController) CreateBookingSeries(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ServiceName   string  `json:"service_name" validate:"required"`
+		Category      string  `json:"category" validate:"required"`
+		Description   string  `json:"description" validate:"required"`
+		ScheduledTime string  `json:"scheduled_time" validate:"required"`
+		Frequency     string  `json:"frequency" validate:"required"`
+		Until         *string `json:"until"`
+		Count         int     `json:"count"`
+		Timezone      string  `json:"timezone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		logger.Error("Invalid request body", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	householderID, ok := householderIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	location := util.UserLocation(r)
+	if request.Timezone != "" {
+		if !util.ValidTimezone(request.Timezone) {
+			response.ErrorResponse(w, http.StatusBadRequest, errs.InvalidTimezone, 1001)
+			return
+		}
+		location = util.LoadUserLocation(request.Timezone)
+	}
+	firstTime, err := util.ParseUserTime(request.ScheduledTime, location)
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		return
+	}
+	rule := model.RecurrenceRule{Frequency: model.RecurrenceFrequency(request.Frequency), Count: request.Count}
+	if request.Until != nil {
+		until, err := util.ParseUserTime(*request.Until, location)
+		if err != nil {
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+			return
+		}
+		rule.Until = &until
+	}
+
+	seriesID, err := h.householderService.CreateBookingSeries(householderID, request.ServiceName, request.Category, request.Description, firstTime, location.String(), rule)
+	if err != nil {
+		logger.Error("Error creating booking series", map[string]interface{}{"householderID": householderID, "error": err.Error()})
+		switch err.Error() {
+		case errs.InvalidRecurrenceRule, errs.InvalidTimezone:
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		case errs.NoFreeSlot:
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, "error creating booking series", 1006)
+		}
+		return
+	}
+
+	logger.Info("Booking series created successfully", map[string]interface{}{"seriesID": seriesID})
+	response.SuccessResponse(w, map[string]string{"series_id": seriesID}, "Booking series created successfully", http.StatusCreated)
+}
+
+func (h *HouseholderController) ViewBookingSeries(w http.ResponseWriter, r *http.Request) {
+	householderID, ok := householderIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	seriesList, err := h.householderService.ViewBookingSeries(householderID)
+	if err != nil {
+		logger.Error("Failed to fetch booking series", map[string]interface{}{"householderID": householderID, "error": err.Error()})
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch booking series", 1003)
+		return
+	}
+	if len(seriesList) == 0 {
+		response.SuccessResponse(w, nil, "No booking series found", http.StatusOK)
+		return
+	}
+	response.SuccessResponse(w, seriesList, "Booking series fetched successfully", http.StatusOK)
+}
+
+func (h *HouseholderController) ViewBookingSeriesByID(w http.ResponseWriter, r *http.Request) {
+	seriesID, ok := mux.Vars(r)["series_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing series Id in params", 2002)
+		return
+	}
+	householderID, ok := householderIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	series, err := h.householderService.GetBookingSeries(seriesID, householderID)
+	if err != nil {
+		logger.Error("Failed to fetch booking series", map[string]interface{}{"seriesID": seriesID, "error": err.Error()})
+		seriesErrorResponse(w, err)
+		return
+	}
+	response.SuccessResponse(w, series, "Booking series fetched successfully", http.StatusOK)
+}
+
+// SkipSeriesOccurrence drops one occurrence, given by its start time, from a booking series
+func (h *HouseholderController) SkipSeriesOccurrence(w http.ResponseWriter, r *http.Request) {
+	seriesID, ok := mux.Vars(r)["series_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing series Id in params", 2002)
+		return
+	}
+	var request struct {
+		OccurrenceTime string `json:"occurrence_time" validate:"required"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	householderID, ok := householderIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	start, err := util.ParseUserTime(request.OccurrenceTime, util.UserLocation(r))
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		return
+	}
+
+	if err := h.householderService.SkipSeriesOccurrence(seriesID, householderID, start); err != nil {
+		logger.Error("Error skipping series occurrence", map[string]interface{}{"seriesID": seriesID, "error": err.Error()})
+		seriesErrorResponse(w, err)
+		return
+	}
+	logger.Info("Series occurrence skipped", map[string]interface{}{"seriesID": seriesID})
+	response.SuccessResponse(w, nil, "Occurrence skipped successfully", http.StatusOK)
+}
+
+func (h *HouseholderController) PauseBookingSeries(w http.ResponseWriter, r *http.Request) {
+	h.changeBookingSeries(w, r, h.householderService.PauseBookingSeries, "Booking series paused successfully")
+}
+
+func (h *HouseholderController) ResumeBookingSeries(w http.ResponseWriter, r *http.Request) {
+	h.changeBookingSeries(w, r, h.householderService.ResumeBookingSeries, "Booking series resumed successfully")
+}
+
+func (h *HouseholderController) CancelBookingSeries(w http.ResponseWriter, r *http.Request) {
+	h.changeBookingSeries(w, r, h.householderService.CancelBookingSeries, "Booking series cancelled successfully")
+}
+
+// changeBookingSeries runs a pause, resume or cancel action against the series in the path
+func (h *HouseholderController) changeBookingSeries(w http.ResponseWriter, r *http.Request, action func(seriesID, householderID string) error, message string) {
+	seriesID, ok := mux.Vars(r)["series_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing series Id in params", 2002)
+		return
+	}
+	householderID, ok := householderIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := action(seriesID, householderID); err != nil {
+		logger.Error("Error updating booking series", map[string]interface{}{"seriesID": seriesID, "error": err.Error()})
+		seriesErrorResponse(w, err)
+		return
+	}
+	logger.Info(message, map[string]interface{}{"seriesID": seriesID})
+	response.SuccessResponse(w, nil, message, http.StatusOK)
+}
+
+// householderIDFromRequest resolves whose bookings are acted on: admins name the householder with
+// ?user_id, householders act on their own. It writes the error response when it returns false.
+func householderIDFromRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	role := r.Context().Value("role").(string)
+	switch role {
+	case "Admin":
+		householderID := r.URL.Query().Get("user_id")
+		if householderID == "" {
+			logger.Error("No query param", nil)
+			response.ErrorResponse(w, http.StatusBadRequest, "user ID is required", 2001)
+			return "", false
+		}
+		return householderID, true
+	case "Householder":
+		return r.Context().Value("userID").(string), true
+	default:
+		logger.Error("Invalid role", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid role", 1007)
+		return "", false
+	}
+}
+
+func seriesErrorResponse(w http.ResponseWriter, err error) {
+	var transitionErr *errs.StatusTransitionError
+	if errors.As(err, &transitionErr) {
+		response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+		return
+	}
+	switch err.Error() {
+	case errs.SeriesNotFound:
+		response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+	case errs.SeriesNotBelongToHouseholder:
+		response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+	case errs.NotSeriesOccurrence:
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+	case errs.SeriesNotActive, errs.SeriesNotPaused, errs.SeriesAlreadyCancelled, errs.OccurrenceAlreadySkipped, errs.RecordBusy:
+		response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+	default:
+		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+	}
+}
@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/response"
+	"serviceNest/util"
+)
+
+type DisputeController struct {
+	disputeService interfaces.DisputeService
+}
+
+// NewDisputeController initializes a new DisputeController with the given service
+func NewDisputeController(disputeService interfaces.DisputeService) *DisputeController {
+	return &DisputeController{
+		disputeService: disputeService,
+	}
+}
+
+// OpenDispute lets the householder or approved provider of a request raise a dispute with a reason and
+// optional evidence
+func (d *DisputeController) OpenDispute(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	var request struct {
+		Reason   string `json:"reason"`
+		Evidence string `json:"evidence"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	dispute, err := d.disputeService.OpenDispute(requestID, userID, role, request.Reason, request.Evidence)
+	if err != nil {
+		logger.Error("Error opening dispute", map[string]interface{}{"requestID": requestID, "error": err.Error()})
+		if writeDisputeError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error opening dispute", 1006)
+		return
+	}
+	logger.Info("Dispute opened", map[string]interface{}{"requestID": requestID, "disputeID": dispute.ID})
+	response.SuccessResponse(w, dispute, "Dispute opened successfully", http.StatusCreated)
+}
+
+// ViewRequestDisputes lists the disputes raised on a request
+func (d *DisputeController) ViewRequestDisputes(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	disputes, err := d.disputeService.GetRequestDisputes(requestID, userID, role)
+	if err != nil {
+		logger.Error("Error fetching request disputes", map[string]interface{}{"requestID": requestID, "error": err.Error()})
+		if writeDisputeError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch disputes", 1003)
+		return
+	}
+	if len(disputes) == 0 {
+		response.SuccessResponse(w, nil, "No disputes found", http.StatusOK)
+		return
+	}
+	response.SuccessResponse(w, disputes, "Disputes fetched successfully", http.StatusOK)
+}
+
+// ViewDispute returns a dispute with the notes and evidence its parties added
+func (d *DisputeController) ViewDispute(w http.ResponseWriter, r *http.Request) {
+	disputeID, ok := mux.Vars(r)["dispute_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing dispute Id in params", 2002)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	dispute, err := d.disputeService.GetDispute(disputeID, userID, role)
+	if err != nil {
+		logger.Error("Error fetching dispute", map[string]interface{}{"disputeID": disputeID, "error": err.Error()})
+		if writeDisputeError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch dispute", 1003)
+		return
+	}
+	response.SuccessResponse(w, dispute, "Dispute fetched successfully", http.StatusOK)
+}
+
+// RespondToDispute adds a statement or evidence to an unresolved dispute
+func (d *DisputeController) RespondToDispute(w http.ResponseWriter, r *http.Request) {
+	disputeID, ok := mux.Vars(r)["dispute_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing dispute Id in params", 2002)
+		return
+	}
+	var request struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	note, err := d.disputeService.AddNote(disputeID, userID, role, request.Note)
+	if err != nil {
+		logger.Error("Error responding to dispute", map[string]interface{}{"disputeID": disputeID, "error": err.Error()})
+		if writeDisputeError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error responding to dispute", 1006)
+		return
+	}
+	response.SuccessResponse(w, note, "Response added successfully", http.StatusCreated)
+}
+
+// ViewDisputeQueue lists disputes for admins, oldest first; ?status= narrows it to one status and it
+// defaults to the disputes still waiting for a decision
+func (d *DisputeController) ViewDisputeQueue(w http.ResponseWriter, r *http.Request) {
+	limit, offset := util.GetPaginationParams(r)
+	disputes, err := d.disputeService.GetDisputeQueue(r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		logger.Error("Error fetching dispute queue", map[string]interface{}{"error": err.Error()})
+		if writeDisputeError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch disputes", 1003)
+		return
+	}
+	if len(disputes) == 0 {
+		response.SuccessResponse(w, nil, "No disputes found", http.StatusOK)
+		return
+	}
+	response.SuccessResponse(w, disputes, "Disputes fetched successfully", http.StatusOK)
+}
+
+// ReviewDispute marks an open dispute as under review by the admin
+func (d *DisputeController) ReviewDispute(w http.ResponseWriter, r *http.Request) {
+	disputeID, ok := mux.Vars(r)["dispute_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing dispute Id in params", 2002)
+		return
+	}
+	adminID := r.Context().Value("userID").(string)
+
+	dispute, err := d.disputeService.StartReview(disputeID, adminID)
+	if err != nil {
+		logger.Error("Error reviewing dispute", map[string]interface{}{"disputeID": disputeID, "error": err.Error()})
+		if writeDisputeError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error reviewing dispute", 1006)
+		return
+	}
+	response.SuccessResponse(w, dispute, "Dispute under review", http.StatusOK)
+}
+
+// ResolveDispute settles a dispute with a full or partial refund, a provider penalty or no action
+func (d *DisputeController) ResolveDispute(w http.ResponseWriter, r *http.Request) {
+	disputeID, ok := mux.Vars(r)["dispute_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing dispute Id in params", 2002)
+		return
+	}
+	var resolution model.DisputeResolution
+	if err := json.NewDecoder(r.Body).Decode(&resolution); err != nil {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	adminID := r.Context().Value("userID").(string)
+
+	dispute, err := d.disputeService.ResolveDispute(disputeID, adminID, resolution)
+	if err != nil {
+		logger.Error("Error resolving dispute", map[string]interface{}{"disputeID": disputeID, "error": err.Error()})
+		if writeDisputeError(w, err) || writePaymentError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error resolving dispute", 1006)
+		return
+	}
+	logger.Info("Dispute resolved", map[string]interface{}{"disputeID": disputeID, "outcome": dispute.Outcome})
+	response.SuccessResponse(w, dispute, "Dispute resolved successfully", http.StatusOK)
+}
+
+// writeDisputeError writes the response for errors raised while handling disputes and reports whether
+// err was one of them
+func writeDisputeError(w http.ResponseWriter, err error) bool {
+	switch err.Error() {
+	case errs.InvalidDispute, errs.InvalidDisputeNote, errs.InvalidDisputeResolution, errs.InvalidDisputeStatus:
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+	case errs.ServiceRequestNotFound, errs.DisputeNotFound:
+		response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+	case errs.RequestNotBelongToHouseholder, errs.RequestNotApprovedForProvider, errs.NotDisputeParty:
+		response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+	case errs.RequestNotDisputable, errs.DisputeAlreadyOpen, errs.DisputeAlreadyUnderReview, errs.DisputeAlreadyResolved,
+		errs.NothingToRefund:
+		response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+	default:
+		return false
+	}
+	return true
+}
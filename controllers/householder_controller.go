@@ -2,14 +2,20 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
+	"io"
 	"net/http"
+	"serviceNest/config"
+	"serviceNest/errs"
 	"serviceNest/interfaces"
 	"serviceNest/logger"
 	"serviceNest/model"
 	"serviceNest/response"
 	"serviceNest/util"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,8 +32,14 @@ func NewHouseholderController(householderService interfaces.HouseholderService)
 func (h *HouseholderController) GetAvailableServices(w http.ResponseWriter, r *http.Request) {
 	category := r.URL.Query().Get("category")
 	limit, offset := util.GetPaginationParams(r)
+	sortBy := util.GetFilterParam(r, "sort")
+	if !util.IsValidServiceSort(sortBy) {
+		logger.Error("Invalid sort mode", map[string]interface{}{"sort": sortBy})
+		response.ErrorResponse(w, http.StatusBadRequest, "sort must be one of relevance, rating, price or popularity", 1001)
+		return
+	}
 	if category == "" {
-		services, err := h.householderService.GetAvailableServices(limit, offset)
+		services, err := h.householderService.GetAvailableServices(limit, offset, sortBy)
 		if err != nil {
 			logger.Error("error fetching all service", nil)
 			response.ErrorResponse(w, http.StatusInternalServerError, "internal server error", 1006)
@@ -35,7 +47,7 @@ func (h *HouseholderController) GetAvailableServices(w http.ResponseWriter, r *h
 		}
 		response.SuccessResponse(w, services, "Available services", http.StatusOK)
 	} else {
-		services, err := h.householderService.GetServicesByCategory(category)
+		services, err := h.householderService.GetServicesByCategory(category, sortBy)
 		if err != nil {
 			logger.Error(err.Error(), nil)
 			response.ErrorResponse(w, http.StatusInternalServerError, "error fetching services", 1006)
@@ -46,6 +58,28 @@ func (h *HouseholderController) GetAvailableServices(w http.ResponseWriter, r *h
 	}
 }
 
+// SearchServices handles free-text service search with typo tolerance and highlighted snippets
+func (h *HouseholderController) SearchServices(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "q is required", 2001)
+		return
+	}
+	limit, offset := util.GetPaginationParams(r)
+
+	results, err := h.householderService.SearchServices(query, limit, offset)
+	if err != nil {
+		logger.Error("error searching services", map[string]interface{}{"query": query, "error": err.Error()})
+		response.ErrorResponse(w, http.StatusInternalServerError, "error searching services", 1006)
+		return
+	}
+	if len(results) == 0 {
+		response.SuccessResponse(w, nil, "No services matched the search", http.StatusOK)
+		return
+	}
+	response.SuccessResponse(w, results, "Search results", http.StatusOK)
+}
+
 func (h *HouseholderController) RequestService(w http.ResponseWriter, r *http.Request) {
 	var request struct {
 		ServiceName   string `json:"service_name" validate:"required"`
@@ -86,10 +120,10 @@ func (h *HouseholderController) RequestService(w http.ResponseWriter, r *http.Re
 	}
 	// Example of retrieving the householder ID from the context
 
-	scheduleTime, err := time.Parse("2006-01-02 15:04", request.ScheduledTime)
+	scheduleTime, err := util.ParseUserTime(request.ScheduledTime, util.UserLocation(r))
 	if err != nil {
 		logger.Error("Invalid request body", nil)
-		response.ErrorResponse(w, http.StatusBadRequest, "Invalid time format", 1001)
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
 		return
 	}
 
@@ -97,6 +131,10 @@ func (h *HouseholderController) RequestService(w http.ResponseWriter, r *http.Re
 	requestID, err := h.householderService.RequestService(householderID, request.ServiceName, request.Category, request.Description, &scheduleTime)
 	if err != nil {
 		logger.Error(err.Error(), nil)
+		if err.Error() == errs.NoFreeSlot {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
 		response.ErrorResponse(w, http.StatusInternalServerError, "error requesting service", 1006)
 		return
 	}
@@ -133,14 +171,28 @@ func (h *HouseholderController) CancelServiceRequest(w http.ResponseWriter, r *h
 		response.ErrorResponse(w, http.StatusBadRequest, "Invalid role", 1007)
 		return
 	}
-	err := h.householderService.CancelServiceRequest(requestID, householderID)
+	// The cancellation reason is optional, so an empty body is accepted
+	var cancelRequest struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&cancelRequest); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	terms, err := h.householderService.CancelServiceRequest(requestID, householderID, cancelRequest.Reason)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Error cancelling request %v", err), nil)
+		var transitionErr *errs.StatusTransitionError
+		if errors.As(err, &transitionErr) || err.Error() == errs.RequestAlreadyCancelled {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
 		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
 		return
 	}
-	logger.Info("Request cancelled successfully", nil)
-	response.SuccessResponse(w, nil, "Request cancelled successfully", http.StatusOK)
+	logger.Info("Request cancelled successfully", map[string]interface{}{"requestID": requestID})
+	response.SuccessResponse(w, terms, "Request cancelled successfully", http.StatusOK)
 	//color.Green("Service request %s has been successfully canceled.", requestID)
 }
 
@@ -162,10 +214,10 @@ func (h *HouseholderController) RescheduleServiceRequest(w http.ResponseWriter,
 		return
 	}
 
-	newTime, err := time.Parse("2006-01-02 15:04", request.ScheduledTime)
+	newTime, err := util.ParseUserTime(request.ScheduledTime, util.UserLocation(r))
 	if err != nil {
 		logger.Error("Invalid request body", nil)
-		response.ErrorResponse(w, http.StatusBadRequest, "Invalid time format", 1001)
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
 		return
 	}
 	role := r.Context().Value("role").(string)
@@ -187,6 +239,10 @@ func (h *HouseholderController) RescheduleServiceRequest(w http.ResponseWriter,
 	err = h.householderService.RescheduleServiceRequest(request.ID, newTime, householderID)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Error rescheduling service %v", err), nil)
+		if err.Error() == errs.NoFreeSlot {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
 		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1008)
 		//color.Red("Error rescheduling service_test request: %v", err)
 		return
@@ -195,6 +251,114 @@ func (h *HouseholderController) RescheduleServiceRequest(w http.ResponseWriter,
 	response.SuccessResponse(w, nil, "service request has been successfully rescheduled", http.StatusOK)
 	//color.Green("Service request %s has been successfully rescheduled.", requestID)
 }
+
+// GetProviderSlots lists a provider's slots for the day given as ?date=YYYY-MM-DD
+func (h *HouseholderController) GetProviderSlots(w http.ResponseWriter, r *http.Request) {
+	providerID := mux.Vars(r)["provider_id"]
+	if providerID == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing provider Id in params", 2002)
+		return
+	}
+	// The date is a calendar day in the provider's schedule timezone
+	date, err := time.Parse("2006-01-02", r.URL.Query().Get("date"))
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "date must be given as YYYY-MM-DD", 1001)
+		return
+	}
+
+	slots, err := h.householderService.GetProviderSlots(providerID, date)
+	if err != nil {
+		logger.Error("Failed to fetch provider slots", map[string]interface{}{"providerID": providerID, "error": err.Error()})
+		if err.Error() == errs.ProviderNotFound {
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch provider slots", 1003)
+		return
+	}
+	response.SuccessResponse(w, slots, "Provider slots fetched successfully", http.StatusOK)
+}
+
+// SearchProviders lists providers offering a service type near the householder, nearest first
+func (h *HouseholderController) SearchProviders(w http.ResponseWriter, r *http.Request) {
+	role := r.Context().Value("role").(string)
+	var householderID string
+	if role == "Admin" {
+		householderID = r.URL.Query().Get("user_id")
+		if householderID == "" {
+			logger.Error("No query param", nil)
+			response.ErrorResponse(w, http.StatusBadRequest, "user ID is required", 2001)
+			return
+		}
+	} else if role == "Householder" {
+		householderID = r.Context().Value("userID").(string)
+	} else {
+		logger.Error("Invalid role", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid role", 1007)
+		return
+	}
+
+	serviceType := r.URL.Query().Get("type")
+	if serviceType == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "type is required", 2001)
+		return
+	}
+	var radiusKm float64
+	if radius := r.URL.Query().Get("radius_km"); radius != "" {
+		// ParseFloat accepts "NaN" and "Inf", and NaN fails every comparison, so only a range check rejects it
+		parsed, err := strconv.ParseFloat(radius, 64)
+		if err != nil || !(parsed > 0 && parsed <= config.MAX_SEARCH_RADIUS_KM) {
+			response.ErrorResponse(w, http.StatusBadRequest,
+				fmt.Sprintf("radius_km must be a number greater than 0 and at most %g", config.MAX_SEARCH_RADIUS_KM), 1001)
+			return
+		}
+		radiusKm = parsed
+	}
+
+	providers, err := h.householderService.SearchService(householderID, serviceType, radiusKm)
+	if err != nil {
+		logger.Error("Failed to search providers", map[string]interface{}{
+			"householderID": householderID,
+			"error":         err.Error(),
+		})
+		if err.Error() == errs.HouseholderLocationUnknown {
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to search providers", 1003)
+		return
+	}
+	if len(providers) == 0 {
+		response.SuccessResponse(w, nil, "No providers found nearby", http.StatusOK)
+		return
+	}
+
+	type responseStruct struct {
+		ID              string   `json:"provider_id"`
+		Name            string   `json:"name"`
+		Contact         string   `json:"contact"`
+		Address         string   `json:"address"`
+		Rating          float64  `json:"rating"`
+		Availability    bool     `json:"availability"`
+		ServiceRadiusKm float64  `json:"service_radius_km"`
+		DistanceKm      *float64 `json:"distance_km"`
+	}
+	var responseData []responseStruct
+	for _, provider := range providers {
+		responseData = append(responseData, responseStruct{
+			ID:              provider.ID,
+			Name:            provider.Name,
+			Contact:         provider.Contact,
+			Address:         provider.Address,
+			Rating:          provider.Rating,
+			Availability:    provider.Availability,
+			ServiceRadiusKm: provider.ServiceRadiusKm,
+			DistanceKm:      provider.DistanceKm,
+		})
+	}
+	response.SuccessResponse(w, responseData, "Providers fetched successfully", http.StatusOK)
+}
+
 func (h *HouseholderController) ViewBookingHistory(w http.ResponseWriter, r *http.Request) {
 	role := r.Context().Value("role").(string)
 	var householderID string
@@ -241,7 +405,7 @@ func (h *HouseholderController) ViewBookingHistory(w http.ResponseWriter, r *htt
 		ServiceID       string                         `json:"service_id" `
 		RequestedTime   time.Time                      `json:"requested_time"`
 		ScheduledTime   time.Time                      `json:"scheduled_time"`
-		Status          string                         `json:"status"` // Pending, Accepted, Approved, Cancelled
+		Status          model.RequestStatus            `json:"status"` // Pending, Accepted, Approved, Cancelled
 		ApproveStatus   bool                           `json:"approve_status" bson:"approveStatus"`
 		ProviderDetails []model.ServiceProviderDetails `json:"provider_details,omitempty" bson:"providerDetails,omitempty"`
 	}
@@ -255,7 +419,7 @@ func (h *HouseholderController) ViewBookingHistory(w http.ResponseWriter, r *htt
 			ScheduledTime: request.ScheduledTime,
 			Status:        request.Status,
 		}
-		if request.Status == "Accepted" && request.ProviderDetails != nil && !request.ApproveStatus {
+		if request.Status == model.StatusAccepted && request.ProviderDetails != nil && !request.ApproveStatus {
 			for _, provider := range request.ProviderDetails {
 				currRequest.ProviderDetails = append(currRequest.ProviderDetails, model.ServiceProviderDetails{
 					ServiceProviderID: provider.ServiceProviderID,
@@ -323,7 +487,7 @@ func (h *HouseholderController) ViewApprovedRequest(w http.ResponseWriter, r *ht
 		ServiceID       string                         `json:"service_id" `
 		RequestedTime   time.Time                      `json:"requested_time"`
 		ScheduledTime   time.Time                      `json:"scheduled_time"`
-		Status          string                         `json:"status"`
+		Status          model.RequestStatus            `json:"status"`
 		ProviderDetails []model.ServiceProviderDetails `json:"provider_details,omitempty" bson:"providerDetails,omitempty"`
 	}
 	responseBody := make([]responseStruct, 0)
@@ -360,10 +524,13 @@ func (h *HouseholderController) ViewApprovedRequest(w http.ResponseWriter, r *ht
 
 }
 
+// ApproveRequest approves a provider's quote. quote_version pins the version the householder saw;
+// without it the provider's latest quote is approved.
 func (h *HouseholderController) ApproveRequest(w http.ResponseWriter, r *http.Request) {
 	var request struct {
-		RequestID  string `json:"request_id" validate:"required"`
-		ProviderID string `json:"provider_id" validate:"required"`
+		RequestID    string `json:"request_id" validate:"required"`
+		ProviderID   string `json:"provider_id" validate:"required"`
+		QuoteVersion int    `json:"quote_version"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -396,12 +563,31 @@ func (h *HouseholderController) ApproveRequest(w http.ResponseWriter, r *http.Re
 	}
 
 	// Call the approval function
-	if err = h.householderService.ApproveServiceRequest(request.RequestID, request.ProviderID, householderID); err != nil {
+	warnings, err := h.householderService.ApproveServiceRequest(request.RequestID, request.ProviderID, householderID, request.QuoteVersion)
+	if err != nil {
 		logger.Error(err.Error(), nil)
+		if writeQuoteError(w, err) {
+			return
+		}
+		var transitionErr *errs.StatusTransitionError
+		if errors.As(err, &transitionErr) {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
+		var conflictErr *errs.ScheduleConflictError
+		if errors.As(err, &conflictErr) {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), response.ErrCodeScheduleConflict)
+			return
+		}
 		response.ErrorResponse(w, http.StatusInternalServerError, "Internal server error", 1006)
 		return
 	}
 
+	if len(warnings) > 0 {
+		logger.Info("Request approved with schedule warnings", map[string]interface{}{"requestID": request.RequestID})
+		response.SuccessResponse(w, map[string]interface{}{"warnings": warnings}, "Request approved, but the provider has little travel time around it", http.StatusOK)
+		return
+	}
 	logger.Info("Request approve successfully", nil)
 	response.SuccessResponse(w, nil, "Request approve successfully", http.StatusOK)
 
@@ -409,8 +595,7 @@ func (h *HouseholderController) ApproveRequest(w http.ResponseWriter, r *http.Re
 
 func (h *HouseholderController) LeaveReview(w http.ResponseWriter, r *http.Request) {
 	var reviewRequest struct {
-		ServiceID  string  `json:"service_id" validate:"required"`
-		ProviderID string  `json:"provider_id" validate:"required"`
+		RequestID  string  `json:"request_id" validate:"required"`
 		ReviewText string  `json:"review_text" validate:"required"`
 		Rating     float64 `json:"rating" validate:"required"`
 	}
@@ -437,9 +622,20 @@ func (h *HouseholderController) LeaveReview(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Call the householder service to add the review
-	err = h.householderService.AddReview(reviewRequest.ProviderID, userID, reviewRequest.ServiceID, reviewRequest.ReviewText, reviewRequest.Rating)
+	err = h.householderService.AddReview(reviewRequest.RequestID, userID, reviewRequest.ReviewText, reviewRequest.Rating)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Error adding review %v", err), nil)
+		switch err.Error() {
+		case errs.ReviewRequiresConfirmedCompletion, errs.RequestNotBelongToHouseholder:
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+			return
+		case errs.ReviewAlreadyExists:
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		case errs.ServiceRequestNotFound:
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
 		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
 		return
 	}
@@ -462,3 +658,177 @@ func (h *HouseholderController) GetAllServiceCategories(w http.ResponseWriter, r
 	logger.Info("Categories retrieved successfully", nil)
 	response.SuccessResponse(w, categories, "Categories retrieved successfully", http.StatusOK)
 }
+
+func (h *HouseholderController) ViewServiceRequestHistory(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		logger.Error("Missing request Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	events, err := h.householderService.GetServiceRequestHistory(requestID, userID, role)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error fetching request history %v", err), nil)
+		if err.Error() == errs.ServiceRequestNotFound {
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
+		if err.Error() == errs.RequestNotBelongToHouseholder || err.Error() == errs.RequestNotInvolveProvider {
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch request history", 1006)
+		return
+	}
+
+	logger.Info("Request history fetched successfully", map[string]interface{}{"requestID": requestID})
+	response.SuccessResponse(w, events, "Request history fetched successfully", http.StatusOK)
+}
+
+// ViewCancellationTerms shows what cancelling the request would cost right now, before the user commits
+func (h *HouseholderController) ViewCancellationTerms(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		logger.Error("Missing request Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	terms, err := h.householderService.GetCancellationTerms(requestID, userID, role)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error fetching cancellation terms %v", err), nil)
+		switch err.Error() {
+		case errs.ServiceRequestNotFound:
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+		case errs.RequestNotBelongToHouseholder, errs.RequestNotInvolveProvider, errs.RequestNotApprovedForProvider:
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+		case errs.RequestNotCancellable:
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch cancellation terms", 1006)
+		}
+		return
+	}
+	response.SuccessResponse(w, terms, "Cancellation terms fetched successfully", http.StatusOK)
+}
+
+func (h *HouseholderController) ConfirmServiceCompletion(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		logger.Error("Missing request Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	role := r.Context().Value("role").(string)
+	var householderID string
+	if role == "Admin" {
+		householderID = r.URL.Query().Get("user_id")
+		if householderID == "" {
+			logger.Error("No query param", nil)
+			response.ErrorResponse(w, http.StatusBadRequest, "user ID is required", 2001)
+			return
+		}
+	} else if role == "Householder" {
+		householderID = r.Context().Value("userID").(string)
+	} else {
+		logger.Error("Invalid role", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid role", 1007)
+		return
+	}
+
+	err := h.householderService.ConfirmServiceCompletion(requestID, householderID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error confirming completion %v", err), nil)
+		switch err.Error() {
+		case errs.RequestNotBelongToHouseholder:
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+		case errs.RequestNotCompleted, errs.CompletionAlreadyConfirmed:
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+		}
+		return
+	}
+
+	logger.Info("Completion confirmed successfully", map[string]interface{}{"requestID": requestID})
+	response.SuccessResponse(w, nil, "Completion confirmed successfully", http.StatusOK)
+}
+
+func (h *HouseholderController) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := mux.Vars(r)["review_id"]
+	if !ok {
+		logger.Error("Missing review Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing review Id in params", 2002)
+		return
+	}
+	var reviewRequest struct {
+		ReviewText string  `json:"review_text" validate:"required"`
+		Rating     float64 `json:"rating" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&reviewRequest); err != nil {
+		logger.Error(err.Error(), nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Error decoding review request", 1001)
+		return
+	}
+	err := validate.Struct(reviewRequest)
+	if err != nil {
+		logger.Error("Invalid request body", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	if reviewRequest.Rating < 1 || reviewRequest.Rating > 5 {
+		response.ErrorResponse(w, http.StatusBadRequest, "Rating should be between 1 and 5", 1001)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+
+	err = h.householderService.UpdateReview(reviewID, userID, reviewRequest.ReviewText, reviewRequest.Rating)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error updating review %v", err), nil)
+		writeReviewError(w, err)
+		return
+	}
+
+	logger.Info("Review updated successfully", map[string]interface{}{"reviewID": reviewID})
+	response.SuccessResponse(w, nil, "Review updated successfully", http.StatusOK)
+}
+
+func (h *HouseholderController) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := mux.Vars(r)["review_id"]
+	if !ok {
+		logger.Error("Missing review Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing review Id in params", 2002)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+
+	err := h.householderService.DeleteReview(reviewID, userID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error deleting review %v", err), nil)
+		writeReviewError(w, err)
+		return
+	}
+
+	logger.Info("Review deleted successfully", map[string]interface{}{"reviewID": reviewID})
+	response.SuccessResponse(w, nil, "Review deleted successfully", http.StatusOK)
+}
+
+// writeReviewError maps review ownership and lifecycle errors to HTTP responses
+func writeReviewError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case errs.ReviewNotFound:
+		response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+	case errs.ReviewNotBelongToHouseholder, errs.ReviewNotBelongToProvider:
+		response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+	case errs.ReviewEditWindowExpired, errs.ReviewAlreadyReplied:
+		response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+	default:
+		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+	}
+}
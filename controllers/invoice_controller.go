@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/response"
+	"serviceNest/util"
+	"strings"
+)
+
+type InvoiceController struct {
+	invoiceService interfaces.InvoiceService
+}
+
+// NewInvoiceController initializes a new InvoiceController with the given service
+func NewInvoiceController(invoiceService interfaces.InvoiceService) *InvoiceController {
+	return &InvoiceController{
+		invoiceService: invoiceService,
+	}
+}
+
+// ViewInvoice returns the invoice of a completed booking as JSON, or as a printable HTML document when
+// asked for with ?format=html or an Accept header of text/html
+func (i *InvoiceController) ViewInvoice(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	invoice, err := i.invoiceService.GetInvoice(requestID, userID, role)
+	if err != nil {
+		logger.Error("Error fetching invoice", map[string]interface{}{"requestID": requestID, "error": err.Error()})
+		switch err.Error() {
+		case errs.ServiceRequestNotFound, errs.NoApprovedProviderForRequest:
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+		case errs.RequestNotBelongToHouseholder, errs.RequestNotInvolveProvider:
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+		case errs.InvoiceNotAvailable:
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch invoice", 1006)
+		}
+		return
+	}
+
+	if r.URL.Query().Get("format") != "html" && !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		response.SuccessResponse(w, invoice, "Invoice fetched successfully", http.StatusOK)
+		return
+	}
+	document, err := util.RenderInvoiceHTML(invoice, util.UserLocation(r))
+	if err != nil {
+		logger.Error("Error rendering invoice", map[string]interface{}{"requestID": requestID, "error": err.Error()})
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to render invoice", 1006)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", invoice.Number+".html"))
+	w.WriteHeader(http.StatusOK)
+	w.Write(document)
+}
@@ -0,0 +1,21 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"serviceNest/config"
+	"serviceNest/logger"
+	"serviceNest/util"
+)
+
+// JWKSHandler handles GET /.well-known/jwks.json, publishing the public keys access tokens are signed
+// with so other services can verify them. It writes a bare JWK Set, as JWKS clients expect, instead of
+// the usual response envelope.
+func JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(config.JWKS_MAX_AGE.Seconds())))
+	if err := json.NewEncoder(w).Encode(util.CurrentJWKS()); err != nil {
+		logger.Error("Error writing JWKS", map[string]interface{}{"error": err.Error()})
+	}
+}
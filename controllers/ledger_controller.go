@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/response"
+	"serviceNest/util"
+	"time"
+)
+
+type LedgerController struct {
+	ledgerService interfaces.LedgerService
+}
+
+// NewLedgerController initializes a new LedgerController with the given service
+func NewLedgerController(ledgerService interfaces.LedgerService) *LedgerController {
+	return &LedgerController{
+		ledgerService: ledgerService,
+	}
+}
+
+// ViewEarnings summarises the provider's earnings between ?from= and ?to= (YYYY-MM-DD, both inclusive,
+// in the provider's timezone), per service and per ?period= of day, week or month. It defaults to the
+// current month so far.
+func (l *LedgerController) ViewEarnings(w http.ResponseWriter, r *http.Request) {
+	location := util.UserLocation(r)
+	now := time.Now().In(location)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.ParseInLocation("2006-01-02", value, location); err != nil {
+			response.ErrorResponse(w, http.StatusBadRequest, errs.InvalidDateRange, 1001)
+			return
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.ParseInLocation("2006-01-02", value, location); err != nil {
+			response.ErrorResponse(w, http.StatusBadRequest, errs.InvalidDateRange, 1001)
+			return
+		}
+	}
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = "month"
+	}
+
+	providerID := r.Context().Value("userID").(string)
+	summary, err := l.ledgerService.GetProviderEarnings(providerID, from, to.AddDate(0, 0, 1), period, location)
+	if err != nil {
+		logger.Error("Error fetching earnings", map[string]interface{}{"providerID": providerID, "error": err.Error()})
+		if err.Error() == errs.InvalidEarningsPeriod || err.Error() == errs.InvalidDateRange {
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch earnings", 1003)
+		return
+	}
+	response.SuccessResponse(w, summary, "Earnings fetched successfully", http.StatusOK)
+}
+
+// CreatePayoutBatch pays out every provider's balance and returns the batch as a CSV file for the bank
+func (l *LedgerController) CreatePayoutBatch(w http.ResponseWriter, r *http.Request) {
+	batch, err := l.ledgerService.CreatePayoutBatch()
+	if err != nil {
+		logger.Error("Error creating payout batch", map[string]interface{}{"error": err.Error()})
+		if err.Error() == errs.NothingToPayOut || err.Error() == errs.PayoutInProgress {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error creating payout batch", 1006)
+		return
+	}
+	logger.Info("Payout batch created", map[string]interface{}{"batchID": batch.ID, "payouts": len(batch.Payouts)})
+	writePayoutBatchCSV(w, batch, http.StatusCreated)
+}
+
+// DownloadPayoutBatch returns the CSV file of an earlier payout batch
+func (l *LedgerController) DownloadPayoutBatch(w http.ResponseWriter, r *http.Request) {
+	batchID, ok := mux.Vars(r)["batch_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing batch Id in params", 2002)
+		return
+	}
+	batch, err := l.ledgerService.GetPayoutBatch(batchID)
+	if err != nil {
+		logger.Error("Error fetching payout batch", map[string]interface{}{"batchID": batchID, "error": err.Error()})
+		if err.Error() == errs.PayoutBatchNotFound {
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error fetching payout batch", 1003)
+		return
+	}
+	writePayoutBatchCSV(w, batch, http.StatusOK)
+}
+
+func writePayoutBatchCSV(w http.ResponseWriter, batch *model.PayoutBatch, status int) {
+	document, err := util.PayoutBatchCSV(batch)
+	if err != nil {
+		response.ErrorResponse(w, http.StatusInternalServerError, "error rendering payout batch", 1006)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "payout-"+batch.ID+".csv"))
+	w.WriteHeader(status)
+	w.Write(document)
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"io"
+	"net/http"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/response"
+)
+
+type PaymentController struct {
+	paymentService interfaces.PaymentService
+}
+
+// NewPaymentController initializes a new PaymentController with the given service
+func NewPaymentController(paymentService interfaces.PaymentService) *PaymentController {
+	return &PaymentController{
+		paymentService: paymentService,
+	}
+}
+
+// PayForRequest starts the householder's payment for an approved request. capture_mode "immediate"
+// charges as soon as the payment is authorized; "on_completion" charges once the job is confirmed done.
+func (p *PaymentController) PayForRequest(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	var request struct {
+		CaptureMode string `json:"capture_mode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	if request.CaptureMode == "" {
+		request.CaptureMode = string(model.CaptureImmediate)
+	}
+	householderID, ok := householderIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	payment, err := p.paymentService.CreatePayment(requestID, householderID, model.CaptureMode(request.CaptureMode))
+	if err != nil {
+		logger.Error("Error creating payment", map[string]interface{}{"requestID": requestID, "error": err.Error()})
+		if writePaymentError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error creating payment", 1006)
+		return
+	}
+	logger.Info("Payment created", map[string]interface{}{"requestID": requestID, "paymentID": payment.ID})
+	response.SuccessResponse(w, payment, "Payment created successfully", http.StatusCreated)
+}
+
+// ViewRequestPayments lists the payments made on a request
+func (p *PaymentController) ViewRequestPayments(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	payments, err := p.paymentService.GetRequestPayments(requestID, userID, role)
+	if err != nil {
+		logger.Error("Error fetching request payments", map[string]interface{}{"requestID": requestID, "error": err.Error()})
+		if writePaymentError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch request payments", 1006)
+		return
+	}
+	if len(payments) == 0 {
+		response.SuccessResponse(w, nil, "No payments found", http.StatusOK)
+		return
+	}
+	response.SuccessResponse(w, payments, "Request payments fetched successfully", http.StatusOK)
+}
+
+// PaymentWebhook receives the payment gateway's callbacks. It is not behind JWT authentication; the
+// payload signature proves it comes from the gateway.
+func (p *PaymentController) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid body", 1001)
+		return
+	}
+	err = p.paymentService.HandleWebhook(payload, r.Header.Get(config.PAYMENT_SIGNATURE_HEADER))
+	if err != nil {
+		logger.Error("Error handling payment webhook", map[string]interface{}{"error": err.Error()})
+		switch err.Error() {
+		case errs.InvalidWebhookSignature:
+			response.ErrorResponse(w, http.StatusUnauthorized, err.Error(), 1002)
+		case errs.InvalidWebhookEvent:
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		case errs.PaymentNotFound:
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, "error handling payment webhook", 1006)
+		}
+		return
+	}
+	response.SuccessResponse(w, nil, "Webhook processed", http.StatusOK)
+}
+
+// RefundPayment lets an admin refund part or, without an amount, the rest of a captured payment
+func (p *PaymentController) RefundPayment(w http.ResponseWriter, r *http.Request) {
+	paymentID, ok := mux.Vars(r)["payment_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing payment Id in params", 2002)
+		return
+	}
+	var request struct {
+		Amount *model.Money `json:"amount"`
+		Reason string       `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	adminID := r.Context().Value("userID").(string)
+
+	payment, err := p.paymentService.RefundPayment(paymentID, adminID, request.Amount, request.Reason)
+	if err != nil {
+		logger.Error("Error refunding payment", map[string]interface{}{"paymentID": paymentID, "error": err.Error()})
+		if writePaymentError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error refunding payment", 1006)
+		return
+	}
+	logger.Info("Payment refunded", map[string]interface{}{"paymentID": paymentID, "refunded": payment.Refunded.String()})
+	response.SuccessResponse(w, payment, "Payment refunded successfully", http.StatusOK)
+}
+
+// writePaymentError writes the response for errors raised while paying for requests and reports whether
+// err was one of them
+func writePaymentError(w http.ResponseWriter, err error) bool {
+	switch err.Error() {
+	case errs.InvalidCaptureMode, errs.InvalidAmount, errs.InvalidCurrency, errs.CurrencyMismatch, errs.InvalidPrice:
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+	case errs.ServiceRequestNotFound, errs.PaymentNotFound, errs.PaymentIntentNotFound:
+		response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+	case errs.RequestNotBelongToHouseholder:
+		response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+	case errs.RequestNotPayable, errs.PaymentAlreadyExists, errs.PaymentNotCapturable, errs.PaymentNotRefundable,
+		errs.RefundExceedsPayment, errs.NoApprovedProviderForRequest:
+		response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+	case errs.PaymentsDisabled:
+		response.ErrorResponse(w, http.StatusServiceUnavailable, err.Error(), 1006)
+	default:
+		return false
+	}
+	return true
+}
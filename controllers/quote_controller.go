@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"serviceNest/errs"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/response"
+	"serviceNest/util"
+	"time"
+)
+
+// quoteInput is the quote part of a request body. Amounts are decimals in major units of the quote's
+// currency, given either as JSON numbers or strings, e.g. "499.50".
+type quoteInput struct {
+	LineItems []struct {
+		Kind        string      `json:"kind"`
+		Description string      `json:"description"`
+		Amount      json.Number `json:"amount"`
+	} `json:"line_items"`
+	Currency   string  `json:"currency"`
+	ValidUntil *string `json:"valid_until"`
+	Note       string  `json:"note"`
+}
+
+// toQuote converts the input into a quote, reading valid_until in the user's location
+func (in quoteInput) toQuote(location *time.Location) (*model.Quote, error) {
+	currency := in.Currency
+	if currency == "" {
+		currency = model.DefaultCurrency
+	}
+	quote := &model.Quote{Note: in.Note}
+	for _, item := range in.LineItems {
+		amount, err := model.ParseMoney(item.Amount.String(), currency)
+		if err != nil {
+			return nil, err
+		}
+		quote.LineItems = append(quote.LineItems, model.QuoteLineItem{
+			Kind:        model.QuoteLineKind(item.Kind),
+			Description: item.Description,
+			Amount:      amount,
+		})
+	}
+	if in.ValidUntil != nil {
+		validUntil, err := util.ParseUserTime(*in.ValidUntil, location)
+		if err != nil {
+			return nil, err
+		}
+		quote.ValidUntil = validUntil
+	}
+	return quote, nil
+}
+
+// ViewRequestQuotes returns the quote negotiation history of a request
+func (h *HouseholderController) ViewRequestQuotes(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		logger.Error("Missing request Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	userID := r.Context().Value("userID").(string)
+	role := r.Context().Value("role").(string)
+
+	quotes, err := h.householderService.GetRequestQuotes(requestID, userID, role)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error fetching request quotes %v", err), nil)
+		if err.Error() == errs.ServiceRequestNotFound {
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
+		if err.Error() == errs.RequestNotBelongToHouseholder || err.Error() == errs.RequestNotInvolveProvider {
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch request quotes", 1006)
+		return
+	}
+	if len(quotes) == 0 {
+		response.SuccessResponse(w, nil, "No quotes found", http.StatusOK)
+		return
+	}
+	response.SuccessResponse(w, quotes, "Request quotes fetched successfully", http.StatusOK)
+}
+
+// CounterQuote sends the householder's counter-offer to a provider's latest quote
+func (h *HouseholderController) CounterQuote(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	var request struct {
+		ProviderID string `json:"provider_id" validate:"required"`
+		quoteInput
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	householderID, ok := householderIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+	counter, err := request.toQuote(util.UserLocation(r))
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		return
+	}
+
+	quote, err := h.householderService.CounterQuote(requestID, request.ProviderID, householderID, counter)
+	if err != nil {
+		logger.Error("Error sending counter-offer", map[string]interface{}{"requestID": requestID, "error": err.Error()})
+		if writeQuoteError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error sending counter-offer", 1006)
+		return
+	}
+	logger.Info("Counter-offer sent", map[string]interface{}{"requestID": requestID, "version": quote.Version})
+	response.SuccessResponse(w, quote, "Counter-offer sent successfully", http.StatusCreated)
+}
+
+// ReviseQuote sends a new version of the provider's quote. Sending no line items accepts the
+// householder's counter-offer.
+func (s *ServiceProviderController) ReviseQuote(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	var request quoteInput
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid body", 1001)
+		return
+	}
+	revision, err := request.toQuote(util.UserLocation(r))
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		return
+	}
+
+	providerID := r.Context().Value("userID").(string)
+	quote, err := s.serviceProviderService.ReviseQuote(providerID, requestID, revision)
+	if err != nil {
+		logger.Error("Error revising quote", map[string]interface{}{"requestID": requestID, "error": err.Error()})
+		if writeQuoteError(w, err) {
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "error revising quote", 1006)
+		return
+	}
+	logger.Info("Quote revised", map[string]interface{}{"requestID": requestID, "version": quote.Version})
+	response.SuccessResponse(w, quote, "Quote revised successfully", http.StatusCreated)
+}
+
+// writeQuoteError writes the response for errors raised while negotiating quotes and reports whether
+// err was one of them
+func writeQuoteError(w http.ResponseWriter, err error) bool {
+	var transitionErr *errs.StatusTransitionError
+	if errors.As(err, &transitionErr) {
+		response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+		return true
+	}
+	switch err.Error() {
+	case errs.InvalidQuote, errs.InvalidAmount, errs.InvalidCurrency, errs.CurrencyMismatch, errs.InvalidQuoteValidity:
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+	case errs.ServiceRequestNotFound, errs.QuoteNotFound:
+		response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+	case errs.RequestNotBelongToHouseholder:
+		response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+	case errs.QuoteAlreadySubmitted, errs.QuoteNotOpen, errs.QuoteAwaitingProvider, errs.QuoteVersionNotLatest,
+		errs.QuoteExpired, errs.QuoteNegotiationClosed, errs.RequestAlreadyApproved, errs.RecordBusy:
+		response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+	default:
+		return false
+	}
+	return true
+}
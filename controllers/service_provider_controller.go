@@ -2,9 +2,12 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
+	"io"
 	"net/http"
+	"serviceNest/errs"
 	"serviceNest/interfaces"
 	"serviceNest/logger"
 	"serviceNest/model"
@@ -25,10 +28,12 @@ func NewServiceProviderController(serviceProviderService interfaces.ServiceProvi
 
 func (s *ServiceProviderController) AddService(w http.ResponseWriter, r *http.Request) {
 	var request struct {
-		Name        string  `json:"name" validate:"required"`
-		Description string  `json:"description" validate:"required"`
-		Price       float64 `json:"price" validate:"required"`
-		Category    string  `json:"category" validate:"required"`
+		Name        string      `json:"name" validate:"required"`
+		Description string      `json:"description" validate:"required"`
+		Price       model.Money `json:"price"`
+		Category    string      `json:"category" validate:"required"`
+		// EstimatedMinutes is optional; jobs without it are assumed to take the default duration
+		EstimatedMinutes int `json:"estimated_minutes" validate:"omitempty,min=1,max=1440"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -42,14 +47,19 @@ func (s *ServiceProviderController) AddService(w http.ResponseWriter, r *http.Re
 		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
 		return
 	}
+	if request.Price.Amount <= 0 {
+		response.ErrorResponse(w, http.StatusBadRequest, errs.InvalidPrice, 1001)
+		return
+	}
 	providerID := r.Context().Value("userID").(string)
 
 	newService := &model.Service{
-		Name:        request.Name,
-		Description: request.Description,
-		Price:       request.Price,
-		Category:    request.Category,
-		ProviderID:  providerID,
+		Name:             request.Name,
+		Description:      request.Description,
+		Price:            request.Price,
+		Category:         request.Category,
+		ProviderID:       providerID,
+		EstimatedMinutes: request.EstimatedMinutes,
 	}
 
 	serviceId, err := s.serviceProviderService.AddService(providerID, *newService)
@@ -91,10 +101,11 @@ func (h *ServiceProviderController) UpdateService(w http.ResponseWriter, r *http
 	vars := mux.Vars(r)
 	serviceID := vars["service_id"]
 	var request struct {
-		Name        string  `json:"name" validate:"required"`
-		Description string  `json:"description" validate:"required"`
-		Price       float64 `json:"price" validate:"required"`
-		Category    string  `json:"category" validate:"required"`
+		Name             string      `json:"name" validate:"required"`
+		Description      string      `json:"description" validate:"required"`
+		Price            model.Money `json:"price"`
+		Category         string      `json:"category" validate:"required"`
+		EstimatedMinutes int         `json:"estimated_minutes" validate:"omitempty,min=1,max=1440"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -108,15 +119,20 @@ func (h *ServiceProviderController) UpdateService(w http.ResponseWriter, r *http
 		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
 		return
 	}
+	if request.Price.Amount <= 0 {
+		response.ErrorResponse(w, http.StatusBadRequest, errs.InvalidPrice, 1001)
+		return
+	}
 	providerID := r.Context().Value("userID").(string)
 
 	updatedService := &model.Service{
-		ID:          serviceID,
-		Name:        request.Name,
-		Description: request.Description,
-		Price:       request.Price,
-		Category:    request.Category,
-		ProviderID:  providerID,
+		ID:               serviceID,
+		Name:             request.Name,
+		Description:      request.Description,
+		Price:            request.Price,
+		Category:         request.Category,
+		ProviderID:       providerID,
+		EstimatedMinutes: request.EstimatedMinutes,
 	}
 
 	err = h.serviceProviderService.UpdateService(providerID, serviceID, *updatedService)
@@ -170,7 +186,7 @@ func (s *ServiceProviderController) ViewServiceRequest(w http.ResponseWriter, r
 	// Filter and display only pending requests
 
 	for _, request := range serviceRequests {
-		if request.ApproveStatus == false && request.Status != "Cancelled" {
+		if request.ApproveStatus == false && request.Status != model.StatusCancelled {
 			responseBody = append(responseBody, responseStruct{
 				ID:                 request.ID,
 				ServiceName:        request.ServiceName,
@@ -194,10 +210,13 @@ func (s *ServiceProviderController) ViewServiceRequest(w http.ResponseWriter, r
 
 }
 
+// AcceptServiceRequest records the provider's first quote on a request. Clients that still send a
+// single price instead of line_items have it quoted as labour.
 func (s *ServiceProviderController) AcceptServiceRequest(w http.ResponseWriter, r *http.Request) {
 	var request struct {
-		ID             string `json:"request_id" validate:"required"`
-		EstimatedPrice string `json:"price" validate:"required"`
+		ID             string       `json:"request_id" validate:"required"`
+		EstimatedPrice *model.Money `json:"price"`
+		quoteInput
 	}
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
@@ -212,8 +231,21 @@ func (s *ServiceProviderController) AcceptServiceRequest(w http.ResponseWriter,
 		return
 	}
 
+	quote, err := request.toQuote(util.UserLocation(r))
+	if err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		return
+	}
+	if len(quote.LineItems) == 0 {
+		if request.EstimatedPrice == nil {
+			response.ErrorResponse(w, http.StatusBadRequest, "line_items or price is required", 1001)
+			return
+		}
+		quote.LineItems = []model.QuoteLineItem{{Kind: model.LineLabour, Amount: *request.EstimatedPrice}}
+	}
+
 	providerID := r.Context().Value("userID").(string)
-	err = s.serviceProviderService.AcceptServiceRequest(providerID, request.ID, request.EstimatedPrice)
+	warnings, err := s.serviceProviderService.AcceptServiceRequest(providerID, request.ID, quote)
 
 	if err != nil {
 		logger.Error(err.Error(), nil)
@@ -221,11 +253,29 @@ func (s *ServiceProviderController) AcceptServiceRequest(w http.ResponseWriter,
 			response.SuccessResponse(w, nil, "provider not found", 200)
 			return
 		}
+		if writeQuoteError(w, err) {
+			return
+		}
+		var transitionErr *errs.StatusTransitionError
+		if errors.As(err, &transitionErr) {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
+		var conflictErr *errs.ScheduleConflictError
+		if errors.As(err, &conflictErr) {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), response.ErrCodeScheduleConflict)
+			return
+		}
 		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1008)
 		//http.Error(w, "Error accepting service request", http.StatusInternalServerError)
 		return
 	}
 
+	if len(warnings) > 0 {
+		logger.Info("Request accepted with schedule warnings", map[string]interface{}{"requestID": request.ID})
+		response.SuccessResponse(w, map[string]interface{}{"warnings": warnings}, "Request accepted, but it leaves less than the travel buffer next to other jobs", http.StatusOK)
+		return
+	}
 	logger.Info("Request accept successfully", nil)
 	response.SuccessResponse(w, nil, "Request accept successfully", http.StatusOK)
 
@@ -265,7 +315,7 @@ func (s *ServiceProviderController) ViewApprovedRequests(w http.ResponseWriter,
 		RequestedTime      time.Time                      `json:"requested_time"`
 		ScheduledTime      time.Time                      `json:"scheduled_time"`
 		Contact            string                         `json:"householder_contact"`
-		Status             string                         `json:"status"`
+		Status             model.RequestStatus            `json:"status"`
 		ProviderDetails    []model.ServiceProviderDetails `json:"provider_details,omitempty" bson:"providerDetails,omitempty"`
 	}
 	responseBody := make([]responseStruct, 0)
@@ -310,3 +360,301 @@ func (s *ServiceProviderController) ViewReviews(w http.ResponseWriter, r *http.R
 	// Send reviews as JSON response
 	response.SuccessResponse(w, reviews, "Reviews fetched successfully", http.StatusOK)
 }
+
+func (s *ServiceProviderController) StartServiceRequest(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		logger.Error("Missing request Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	providerID := r.Context().Value("userID").(string)
+
+	err := s.serviceProviderService.StartServiceRequest(providerID, requestID)
+	if err != nil {
+		logger.Error(err.Error(), nil)
+		var transitionErr *errs.StatusTransitionError
+		if errors.As(err, &transitionErr) {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
+		if err.Error() == errs.RequestNotApprovedForProvider {
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1008)
+		return
+	}
+
+	logger.Info("Job started successfully", map[string]interface{}{"requestID": requestID})
+	response.SuccessResponse(w, nil, "Job started successfully", http.StatusOK)
+}
+
+// CancelApprovedRequest lets the approved provider cancel a booking before it starts; the response shows
+// the reliability penalty it cost
+func (s *ServiceProviderController) CancelApprovedRequest(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		logger.Error("Missing request Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	providerID := r.Context().Value("userID").(string)
+
+	// The cancellation reason is optional, so an empty body is accepted
+	var cancelRequest struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&cancelRequest); err != nil && !errors.Is(err, io.EOF) {
+		logger.Error("Invalid input", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+
+	terms, err := s.serviceProviderService.CancelApprovedRequest(providerID, requestID, cancelRequest.Reason)
+	if err != nil {
+		logger.Error(err.Error(), nil)
+		var transitionErr *errs.StatusTransitionError
+		if errors.As(err, &transitionErr) || err.Error() == errs.OnlyApprovedRequestCancelled {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
+		if err.Error() == errs.RequestNotApprovedForProvider {
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+		return
+	}
+
+	logger.Info("Booking cancelled by provider", map[string]interface{}{"requestID": requestID, "penaltyPoints": terms.PenaltyPoints})
+	response.SuccessResponse(w, terms, "Booking cancelled successfully", http.StatusOK)
+}
+
+func (s *ServiceProviderController) CompleteServiceRequest(w http.ResponseWriter, r *http.Request) {
+	requestID, ok := mux.Vars(r)["request_id"]
+	if !ok {
+		logger.Error("Missing request Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing request Id in params", 2002)
+		return
+	}
+	providerID := r.Context().Value("userID").(string)
+
+	err := s.serviceProviderService.CompleteServiceRequest(providerID, requestID)
+	if err != nil {
+		logger.Error(err.Error(), nil)
+		var transitionErr *errs.StatusTransitionError
+		if errors.As(err, &transitionErr) {
+			response.ErrorResponse(w, http.StatusConflict, err.Error(), 1009)
+			return
+		}
+		if err.Error() == errs.RequestNotApprovedForProvider {
+			response.ErrorResponse(w, http.StatusForbidden, err.Error(), 1007)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1008)
+		return
+	}
+
+	logger.Info("Job finished successfully", map[string]interface{}{"requestID": requestID})
+	response.SuccessResponse(w, nil, "Job finished successfully", http.StatusOK)
+}
+
+func (s *ServiceProviderController) ReplyToReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, ok := mux.Vars(r)["review_id"]
+	if !ok {
+		logger.Error("Missing review Id in params", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing review Id in params", 2002)
+		return
+	}
+	var request struct {
+		Reply string `json:"reply" validate:"required"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid body", 1001)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		logger.Error("Invalid request body", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	providerID := r.Context().Value("userID").(string)
+
+	err := s.serviceProviderService.ReplyToReview(providerID, reviewID, request.Reply)
+	if err != nil {
+		logger.Error(err.Error(), nil)
+		writeReviewError(w, err)
+		return
+	}
+
+	logger.Info("Reply added successfully", map[string]interface{}{"reviewID": reviewID})
+	response.SuccessResponse(w, nil, "Reply added successfully", http.StatusOK)
+}
+
+// UpdateServiceRadius sets how far, in kilometres, the provider travels for a job
+func (s *ServiceProviderController) UpdateServiceRadius(w http.ResponseWriter, r *http.Request) {
+	providerID := r.Context().Value("userID").(string)
+
+	var radiusData struct {
+		ServiceRadiusKm float64 `json:"service_radius_km" validate:"required"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&radiusData); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	if err := validate.Struct(radiusData); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "service_radius_km is required", 1001)
+		return
+	}
+
+	err := s.serviceProviderService.UpdateServiceRadius(providerID, radiusData.ServiceRadiusKm)
+	if err != nil {
+		logger.Error("Error updating service radius", map[string]interface{}{"providerID": providerID, "error": err.Error()})
+		switch err.Error() {
+		case errs.InvalidServiceRadius:
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		case errs.ProviderNotFound:
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+		}
+		return
+	}
+	response.SuccessResponse(w, nil, "Service radius updated successfully", http.StatusOK)
+}
+
+// UpdateAvailability switches the provider's availability for new jobs on or off
+func (s *ServiceProviderController) UpdateAvailability(w http.ResponseWriter, r *http.Request) {
+	providerID := r.Context().Value("userID").(string)
+
+	var availabilityData struct {
+		Availability *bool `json:"availability" validate:"required"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&availabilityData); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	if err := validate.Struct(availabilityData); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "availability is required", 1001)
+		return
+	}
+
+	if err := s.serviceProviderService.UpdateAvailability(providerID, *availabilityData.Availability); err != nil {
+		logger.Error("Error updating availability", map[string]interface{}{"providerID": providerID, "error": err.Error()})
+		if err.Error() == errs.ProviderNotFound {
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+		return
+	}
+	response.SuccessResponse(w, nil, "Availability updated successfully", http.StatusOK)
+}
+
+// GetSchedule returns the provider's slot length, weekly working hours and upcoming time off
+func (s *ServiceProviderController) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	providerID := r.Context().Value("userID").(string)
+
+	schedule, err := s.serviceProviderService.GetSchedule(providerID)
+	if err != nil {
+		logger.Error("Error fetching schedule", map[string]interface{}{"providerID": providerID, "error": err.Error()})
+		if err.Error() == errs.ProviderNotFound {
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch schedule", 1003)
+		return
+	}
+	response.SuccessResponse(w, schedule, "Schedule fetched successfully", http.StatusOK)
+}
+
+// UpdateSchedule replaces the provider's slot length and weekly working hours
+func (s *ServiceProviderController) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	providerID := r.Context().Value("userID").(string)
+
+	var scheduleData struct {
+		SlotMinutes  int                  `json:"slot_minutes" validate:"required"`
+		Timezone     string               `json:"timezone"`
+		WorkingHours []model.WorkingHours `json:"working_hours"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&scheduleData); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	if err := validate.Struct(scheduleData); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "slot_minutes is required", 1001)
+		return
+	}
+
+	err := s.serviceProviderService.UpdateSchedule(providerID, scheduleData.SlotMinutes, scheduleData.Timezone, scheduleData.WorkingHours)
+	if err != nil {
+		logger.Error("Error updating schedule", map[string]interface{}{"providerID": providerID, "error": err.Error()})
+		switch err.Error() {
+		case errs.InvalidSlotLength, errs.InvalidWorkingHours, errs.InvalidTimezone:
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+		}
+		return
+	}
+	response.SuccessResponse(w, nil, "Schedule updated successfully", http.StatusOK)
+}
+
+// AddTimeOff blocks the provider's calendar for a period
+func (s *ServiceProviderController) AddTimeOff(w http.ResponseWriter, r *http.Request) {
+	providerID := r.Context().Value("userID").(string)
+
+	var timeOffData struct {
+		Start  string `json:"start" validate:"required"`
+		End    string `json:"end" validate:"required"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&timeOffData); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+	if err := validate.Struct(timeOffData); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "start and end are required", 1001)
+		return
+	}
+	start, errStart := util.ParseUserTime(timeOffData.Start, util.UserLocation(r))
+	end, errEnd := util.ParseUserTime(timeOffData.End, util.UserLocation(r))
+	if errStart != nil || errEnd != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, errs.InvalidTimeFormat, 1001)
+		return
+	}
+
+	timeOffID, err := s.serviceProviderService.AddTimeOff(providerID, start, end, timeOffData.Reason)
+	if err != nil {
+		logger.Error("Error adding time off", map[string]interface{}{"providerID": providerID, "error": err.Error()})
+		if err.Error() == errs.InvalidTimeOffRange {
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+		return
+	}
+	response.SuccessResponse(w, map[string]string{"time_off_id": timeOffID}, "Time off added successfully", http.StatusCreated)
+}
+
+// RemoveTimeOff deletes one of the provider's time off blocks
+func (s *ServiceProviderController) RemoveTimeOff(w http.ResponseWriter, r *http.Request) {
+	providerID := r.Context().Value("userID").(string)
+	timeOffID := mux.Vars(r)["time_off_id"]
+	if timeOffID == "" {
+		response.ErrorResponse(w, http.StatusBadRequest, "Missing time off Id in params", 2002)
+		return
+	}
+
+	if err := s.serviceProviderService.RemoveTimeOff(providerID, timeOffID); err != nil {
+		logger.Error("Error removing time off", map[string]interface{}{"providerID": providerID, "error": err.Error()})
+		if err.Error() == errs.TimeOffNotFound {
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+			return
+		}
+		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+		return
+	}
+	response.SuccessResponse(w, nil, "Time off removed successfully", http.StatusOK)
+}
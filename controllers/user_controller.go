@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-playground/validator"
+	"math"
 	"net/http"
 	"serviceNest/errs"
 	"serviceNest/interfaces"
@@ -11,12 +13,13 @@ import (
 	"serviceNest/model"
 	"serviceNest/response"
 	"serviceNest/util"
+	"strconv"
+	"time"
 )
 
 var CheckPassword = util.CheckPasswordHash
 var HashPassword = util.HashPassword
 var validate *validator.Validate
-var GenerateJWT = util.GenerateJWT
 var ValidatePassword = util.ValidatePassword
 
 func init() {
@@ -25,11 +28,13 @@ func init() {
 }
 
 type UserController struct {
-	userService interfaces.UserService
+	userService     interfaces.UserService
+	authService     interfaces.AuthService
+	throttleService interfaces.AuthThrottleService
 }
 
-func NewUserController(userService interfaces.UserService) *UserController {
-	return &UserController{userService: userService}
+func NewUserController(userService interfaces.UserService, authService interfaces.AuthService, throttleService interfaces.AuthThrottleService) *UserController {
+	return &UserController{userService: userService, authService: authService, throttleService: throttleService}
 }
 
 // LoginUser handles POST /login
@@ -51,12 +56,17 @@ func (u *UserController) LoginUser(w http.ResponseWriter, r *http.Request) {
 		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
 		return
 	}
+	ip := util.ClientIP(r)
+	if u.beginAttempt(w, userInput.Email, ip) {
+		return
+	}
 
 	// Check if user exists and get the user details
 	var user *model.User
 	user, err = u.userService.CheckUserExists(userInput.Email)
 	if err != nil {
 		logger.Error("Invalid email or password", map[string]interface{}{"email": userInput.Email})
+		u.recordFailure(model.AuthActionLogin, userInput.Email, ip, "unknown email")
 		response.ErrorResponse(w, http.StatusUnauthorized, "Invalid email or password", 1005)
 		//http.Error(w, "Invalid email or password", http.StatusUnauthorized)
 		return
@@ -65,32 +75,74 @@ func (u *UserController) LoginUser(w http.ResponseWriter, r *http.Request) {
 	// Verify the password
 	if !CheckPassword(userInput.Password, user.Password) {
 		logger.Error("Invalid password", map[string]interface{}{"email": userInput.Email})
+		u.recordFailure(model.AuthActionLogin, userInput.Email, ip, "wrong password")
 		response.ErrorResponse(w, http.StatusUnauthorized, "Invalid password", 1005)
 		//http.Error(w, "Invalid password", http.StatusUnauthorized)
 		return
 	}
+	u.recordSuccess(userInput.Email, ip)
 	if user.IsActive == false {
 		logger.Error("User is deactivated by admin", map[string]interface{}{"email": userInput.Email})
-		response.ErrorResponse(w, http.StatusUnauthorized, "user Deactivated by admin", 1007)
+		response.ErrorResponse(w, http.StatusUnauthorized, errs.UserDeactivated, 1007)
 		return
 	}
 
-	// Generate JWT token
-	var tokenString string
-	tokenString, err = GenerateJWT(user.ID, user.Role)
+	// Start a session and issue its access and refresh tokens
+	tokens, err := u.authService.StartSession(user)
 	if err != nil {
 		logger.Error("Error generating token", map[string]interface{}{"email": userInput.Email})
 		response.ErrorResponse(w, http.StatusInternalServerError, "Error generating token", 1006)
-		//http.Error(w, "Error generating token", http.StatusInternalServerError)
 		return
 	}
 
-	// Return the token as JSON
+	// Return the tokens as JSON
 	logger.Info("token generated", map[string]interface{}{"email": userInput.Email})
-	response.SuccessResponse(w, map[string]interface{}{"token": tokenString}, "Token generate successfully", http.StatusCreated)
-	//w.Header().Set("Content-Type", "application/json")
-	//json.NewEncoder(w).Encode(map[string]string{"token": tokenString})
+	response.SuccessResponse(w, tokens, "Token generate successfully", http.StatusCreated)
+}
+
+// RefreshToken handles POST /token/refresh, exchanging a refresh token for a new pair of tokens
+func (u *UserController) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		RefreshToken string `json:"refresh_token" validate:"required"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid input", 1001)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		logger.Error("Validation error", nil)
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+
+	tokens, err := u.authService.RefreshSession(request.RefreshToken)
+	if err != nil {
+		logger.Error("Error refreshing token", map[string]interface{}{"error": err.Error()})
+		switch err.Error() {
+		case errs.InvalidRefreshToken, errs.SessionNotFound:
+			response.ErrorResponse(w, http.StatusUnauthorized, errs.InvalidRefreshToken, 1002)
+		case errs.UserDeactivated:
+			response.ErrorResponse(w, http.StatusUnauthorized, err.Error(), 1007)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, "Error refreshing token", 1006)
+		}
+		return
+	}
+	response.SuccessResponse(w, tokens, "Token refreshed successfully", http.StatusCreated)
+}
+
+// Logout handles POST /logout, revoking the session the access token belongs to
+func (u *UserController) Logout(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Context().Value("sessionID").(string)
+	if err := u.authService.EndSession(sessionID); err != nil {
+		logger.Error("Error logging out", map[string]interface{}{"sessionID": sessionID, "error": err.Error()})
+		response.ErrorResponse(w, http.StatusInternalServerError, "Error logging out", 1006)
+		return
+	}
+	logger.Info("user logged out", map[string]interface{}{"userID": r.Context().Value("userID")})
+	response.SuccessResponse(w, nil, "Logged out successfully", http.StatusOK)
 }
+
 func (u *UserController) SignupUser(w http.ResponseWriter, r *http.Request) {
 	var newUser struct {
 		Name           string `json:"name" validate:"required"`
@@ -185,6 +237,7 @@ func (u *UserController) UpdateUserHandler(w http.ResponseWriter, r *http.Reques
 		Password *string `json:"password"`
 		Address  *string `json:"address"`
 		Contact  *string `json:"contact"`
+		Timezone *string `json:"timezone"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
@@ -193,33 +246,74 @@ func (u *UserController) UpdateUserHandler(w http.ResponseWriter, r *http.Reques
 		//http.Error(w, "Invalid input", http.StatusBadRequest)
 		return
 	}
-	err := ValidatePassword(*updateData.Password)
-	if err != nil {
-		logger.Error("Error validating password", map[string]interface{}{"email": *updateData.Email})
-		response.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("%v", err), 1001)
-		//http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
-		return
-	}
-	hashedPassword, err := HashPassword(*updateData.Password)
-	if err != nil {
-		logger.Error("Error hashing password", map[string]interface{}{"email": *updateData.Email})
-		response.ErrorResponse(w, http.StatusInternalServerError, "Error hashing password", 1006)
-		//http.Error(w, "Error hashing password", http.StatusInternalServerError)
-		return
+	// The password is optional; changing it logs the user out of every session
+	var hashedPassword *string
+	if updateData.Password != nil {
+		err := ValidatePassword(*updateData.Password)
+		if err != nil {
+			logger.Error("Error validating password", map[string]interface{}{"userID": userID})
+			response.ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("%v", err), 1001)
+			//http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
+			return
+		}
+		hashed, err := HashPassword(*updateData.Password)
+		if err != nil {
+			logger.Error("Error hashing password", map[string]interface{}{"userID": userID})
+			response.ErrorResponse(w, http.StatusInternalServerError, "Error hashing password", 1006)
+			//http.Error(w, "Error hashing password", http.StatusInternalServerError)
+			return
+		}
+		hashedPassword = &hashed
 	}
 
 	// Call the UserService to update the user profile
-	err = u.userService.UpdateUser(userID, updateData.Email, &hashedPassword, updateData.Address, updateData.Contact)
+	err := u.userService.UpdateUser(userID, updateData.Email, hashedPassword, updateData.Address, updateData.Contact, updateData.Timezone)
 	if err != nil {
+		if err.Error() == errs.InvalidTimezone {
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+			return
+		}
 		response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
 		//http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	logger.Info("user updated sucessfully", map[string]interface{}{"email": *updateData.Email})
+	logger.Info("user updated sucessfully", map[string]interface{}{"userID": userID})
 	response.SuccessResponse(w, nil, "User updated successfully", http.StatusOK)
 
 }
 
+// UpdateLocationHandler sets the user's location from explicit coordinates or a pincode
+func (u *UserController) UpdateLocationHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(string)
+
+	var locationData struct {
+		Pincode   *string  `json:"pincode"`
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&locationData); err != nil {
+		logger.Error("Invalid input", map[string]interface{}{"userID": userID})
+		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
+		return
+	}
+
+	err := u.userService.UpdateLocation(userID, locationData.Pincode, locationData.Latitude, locationData.Longitude)
+	if err != nil {
+		logger.Error("Error updating location", map[string]interface{}{"userID": userID, "error": err.Error()})
+		switch err.Error() {
+		case errs.LocationRequired, errs.InvalidCoordinates, errs.UnknownPincode:
+			response.ErrorResponse(w, http.StatusBadRequest, err.Error(), 1001)
+		case errs.UserNotFound:
+			response.ErrorResponse(w, http.StatusNotFound, err.Error(), 1008)
+		default:
+			response.ErrorResponse(w, http.StatusInternalServerError, err.Error(), 1006)
+		}
+		return
+	}
+	logger.Info("user location updated", map[string]interface{}{"userID": userID})
+	response.SuccessResponse(w, nil, "Location updated successfully", http.StatusOK)
+}
+
 func (u *UserController) ForgetPasswordHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Parse incoming JSON data
@@ -255,6 +349,10 @@ func (u *UserController) ForgetPasswordHandler(w http.ResponseWriter, r *http.Re
 		response.ErrorResponse(w, http.StatusInternalServerError, "Error hashing password", 1006)
 		return
 	}
+	ip := util.ClientIP(r)
+	if u.beginAttempt(w, *updateData.Email, ip) {
+		return
+	}
 
 	// Call the UserService to update the user profile
 
@@ -263,17 +361,21 @@ func (u *UserController) ForgetPasswordHandler(w http.ResponseWriter, r *http.Re
 
 		if err1.Error() == errs.UserNotFound {
 			logger.Error("User not found", nil)
+			u.recordFailure(model.AuthActionSecurityAnswer, *updateData.Email, ip, "unknown email")
 			response.ErrorResponse(w, http.StatusNotFound, "email doesn't exist", 1008)
 			return
 		} else if err1.Error() == errs.IncorrectSecurityAnswer {
 			logger.Error(err1.Error(), nil)
+			u.recordFailure(model.AuthActionSecurityAnswer, *updateData.Email, ip, "wrong security answer")
 			response.ErrorResponse(w, http.StatusUnauthorized, "incorrect security answer", 1007)
 			return
 		}
 		logger.Error(err1.Error(), nil)
+		u.releaseAttempt(*updateData.Email, ip)
 		response.ErrorResponse(w, http.StatusInternalServerError, "Error updating user", 1006)
 		return
 	}
+	u.recordSuccess(*updateData.Email, ip)
 	logger.Info("password updated sucessfully", map[string]interface{}{"email": *updateData.Email})
 	response.SuccessResponse(w, nil, "User password updated successfully", http.StatusOK)
 
@@ -314,6 +416,10 @@ func (u *UserController) VerifyOtpAndUpdatePassword(w http.ResponseWriter, r *ht
 		response.ErrorResponse(w, http.StatusInternalServerError, "Error hashing password", 1006)
 		return
 	}
+	ip := util.ClientIP(r)
+	if u.beginAttempt(w, *updateData.Email, ip) {
+		return
+	}
 
 	// Call the UserService to update the user profile
 
@@ -322,21 +428,26 @@ func (u *UserController) VerifyOtpAndUpdatePassword(w http.ResponseWriter, r *ht
 
 		if err1.Error() == errs.UserNotFound {
 			logger.Error("User not found", nil)
+			u.releaseAttempt(*updateData.Email, ip)
 			response.ErrorResponse(w, http.StatusNotFound, "email doesn't exist", 1008)
 			return
 		} else if err1.Error() == errs.IncorrectSecurityAnswer {
 			logger.Error(err1.Error(), nil)
+			u.releaseAttempt(*updateData.Email, ip)
 			response.ErrorResponse(w, http.StatusUnauthorized, "incorrect security answer", 1007)
 			return
-		} else if err1.Error() == "Invalid Otp" {
+		} else if err1.Error() == errs.InvalidOtp {
 			logger.Error(err1.Error(), nil)
+			u.recordFailure(model.AuthActionOtp, *updateData.Email, ip, "wrong or expired otp")
 			response.ErrorResponse(w, http.StatusUnauthorized, "incorrect otp", 1008)
 			return
 		}
 		logger.Error(err1.Error(), nil)
+		u.releaseAttempt(*updateData.Email, ip)
 		response.ErrorResponse(w, http.StatusInternalServerError, "Error updating user", 1006)
 		return
 	}
+	u.recordSuccess(*updateData.Email, ip)
 	logger.Info("password updated sucessfully", map[string]interface{}{"email": *updateData.Email})
 	response.SuccessResponse(w, nil, "User password updated successfully", http.StatusOK)
 
@@ -346,7 +457,8 @@ func (u *UserController) GenerateOtp(w http.ResponseWriter, r *http.Request) {
 
 	// Parse incoming JSON data
 	var updateData struct {
-		Email *string `json:"email" validate:"required"`
+		Email   *string          `json:"email" validate:"required"`
+		Purpose model.OtpPurpose `json:"purpose"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
@@ -361,10 +473,23 @@ func (u *UserController) GenerateOtp(w http.ResponseWriter, r *http.Request) {
 		response.ErrorResponse(w, http.StatusBadRequest, "Invalid request body", 1001)
 		return
 	}
+	// A locked out account gets no new OTPs until its lockout ends
+	if u.writeLockout(w, *updateData.Email, util.ClientIP(r)) {
+		return
+	}
 
-	err1 := u.userService.GenerateOtp(*updateData.Email)
+	// OTPs are for password resets unless another purpose is asked for
+	if updateData.Purpose == "" {
+		updateData.Purpose = model.OtpPasswordReset
+	}
+
+	err1 := u.userService.GenerateOtp(*updateData.Email, updateData.Purpose)
 	if err1 != nil {
 		logger.Error(err1.Error(), nil)
+		if err1.Error() == errs.InvalidOtpPurpose {
+			response.ErrorResponse(w, http.StatusBadRequest, err1.Error(), 1001)
+			return
+		}
 		response.ErrorResponse(w, http.StatusInternalServerError, err1.Error(), 1006)
 		return
 	}
@@ -372,3 +497,69 @@ func (u *UserController) GenerateOtp(w http.ResponseWriter, r *http.Request) {
 	response.SuccessResponse(w, nil, "Otp Sent successfully", http.StatusOK)
 
 }
+
+// ViewFailedAuthAttempts handles GET /admin/auth-failures, the audit log of failed logins, security
+// answers and OTPs, newest first; ?email= narrows it to one account
+func (u *UserController) ViewFailedAuthAttempts(w http.ResponseWriter, r *http.Request) {
+	limit, offset := util.GetPaginationParams(r)
+	attempts, err := u.throttleService.GetFailedAttempts(r.URL.Query().Get("email"), limit, offset)
+	if err != nil {
+		logger.Error("Error fetching failed attempts", map[string]interface{}{"error": err.Error()})
+		response.ErrorResponse(w, http.StatusInternalServerError, "Failed to fetch failed attempts", 1003)
+		return
+	}
+	if len(attempts) == 0 {
+		response.SuccessResponse(w, nil, "No failed attempts found", http.StatusOK)
+		return
+	}
+	response.SuccessResponse(w, attempts, "Failed attempts fetched successfully", http.StatusOK)
+}
+
+// writeLockout answers 429 with a Retry-After header and reports true while the account or the IP address
+// is locked out after too many failed attempts
+func (u *UserController) writeLockout(w http.ResponseWriter, email, ip string) bool {
+	return u.writeThrottleError(w, email, ip, u.throttleService.CheckLockout(email, ip))
+}
+
+// beginAttempt counts a credential check before it runs, answering like writeLockout when the account or
+// the IP address has no attempts left
+func (u *UserController) beginAttempt(w http.ResponseWriter, email, ip string) bool {
+	return u.writeThrottleError(w, email, ip, u.throttleService.BeginAttempt(email, ip))
+}
+
+func (u *UserController) writeThrottleError(w http.ResponseWriter, email, ip string, err error) bool {
+	if err == nil {
+		return false
+	}
+	var lockout *errs.LockoutError
+	if errors.As(err, &lockout) {
+		logger.Error("Locked out", map[string]interface{}{"email": email, "ip": ip, "until": lockout.Until})
+		retryAfter := int(math.Ceil(time.Until(lockout.Until).Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		response.ErrorResponse(w, http.StatusTooManyRequests, err.Error(), response.ErrCodeLockedOut)
+		return true
+	}
+	logger.Error("Error checking lockout", map[string]interface{}{"email": email, "error": err.Error()})
+	response.ErrorResponse(w, http.StatusInternalServerError, "Error checking failed attempts", 1006)
+	return true
+}
+
+// recordFailure counts a failed credential check; the request has failed anyway, so errors are only logged
+func (u *UserController) recordFailure(action model.AuthAction, email, ip, reason string) {
+	if err := u.throttleService.RecordFailure(action, email, ip, reason); err != nil {
+		logger.Error("Error recording failed attempt", map[string]interface{}{"email": email, "error": err.Error()})
+	}
+}
+
+func (u *UserController) recordSuccess(email, ip string) {
+	if err := u.throttleService.RecordSuccess(email, ip); err != nil {
+		logger.Error("Error clearing failed attempts", map[string]interface{}{"email": email, "error": err.Error()})
+	}
+}
+
+// releaseAttempt takes back an attempt that ended before the credentials were found right or wrong
+func (u *UserController) releaseAttempt(email, ip string) {
+	if err := u.throttleService.ReleaseAttempt(email, ip); err != nil {
+		logger.Error("Error releasing attempt", map[string]interface{}{"email": email, "error": err.Error()})
+	}
+}
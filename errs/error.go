@@ -1,10 +1,17 @@
 package errs
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
 const UserNotFound = "user not found"
 const EmailAlreadyUse = "email already in use"
 const ServiceRequestNotFound = "service request not found"
 const ErrorParsingRequestTime = "error parsing requested_time"
 const ErrorParsingScheduleTime = "error parsing scheduled_time"
+const ErrorParsingActualTime = "error parsing actual start or end time"
 const NoServiceProviderFoundForRequestId = "no service provider found for request id"
 const ServiceNotFound = "service not found"
 const ServiceIdNotExists = "service id not exists"
@@ -23,4 +30,141 @@ const NotUpdateRequest = "not update request"
 const NotRetrieveRequest = "not retrieve request"
 const NoApproveRequestFound = "no approve request found"
 const IncorrectSecurityAnswer = "incorrect security answer"
-const RequestCancellationTooLate = "cancellation not allowed within 4 hours of the scheduled time"
+const RequestNotInvolveProvider = "service request does not involve the provider"
+const RequestNotApprovedForProvider = "service request is not approved for this provider"
+const RequestNotCompleted = "service request has not been completed"
+const CompletionAlreadyConfirmed = "completion is already confirmed"
+const ReviewRequiresConfirmedCompletion = "a review can only be added after a confirmed completed service"
+const NoApprovedProviderForRequest = "no approved provider found for request"
+const ReviewAlreadyExists = "a review already exists for this booking"
+const ReviewNotFound = "review not found"
+const ReviewNotBelongToHouseholder = "review does not belong to the householder"
+const ReviewNotBelongToProvider = "review does not belong to the provider"
+const ReviewEditWindowExpired = "review can no longer be edited or deleted"
+const ReviewAlreadyReplied = "review already has a reply"
+const UnknownPincode = "pincode could not be geocoded"
+const LocationRequired = "either a pincode or both latitude and longitude are required"
+const InvalidCoordinates = "latitude must be within [-90, 90] and longitude within [-180, 180]"
+const HouseholderLocationUnknown = "householder location is not set"
+const InvalidServiceRadius = "service radius must be a positive number of kilometres"
+const InvalidWorkingHours = "working hours must be valid, non-overlapping HH:MM windows on weekdays 0-6"
+const InvalidSlotLength = "slot length must be between 15 and 480 minutes"
+const InvalidTimeOffRange = "time off must end after it starts"
+const TimeOffNotFound = "time off block not found"
+const NoFreeSlot = "scheduled time does not fit a free provider slot"
+const ScheduleConflict = "job overlaps another job of the provider"
+const InvalidTimeFormat = "time must be RFC 3339, e.g. 2024-06-01T10:00:00+05:30"
+const InvalidTimezone = "timezone must be a valid IANA timezone name"
+const InvalidRecurrenceRule = "recurrence must be weekly, biweekly or monthly and end on a date or after a number of occurrences"
+const SeriesNotFound = "booking series not found"
+const SeriesNotBelongToHouseholder = "booking series does not belong to the householder"
+const SeriesNotActive = "booking series is not active"
+const SeriesNotPaused = "booking series is not paused"
+const SeriesAlreadyCancelled = "booking series is already cancelled"
+const NotSeriesOccurrence = "time is not an upcoming occurrence of the booking series"
+const SeriesOccurrenceExists = "occurrence of the booking series already has a request"
+const OccurrenceAlreadySkipped = "occurrence is already skipped"
+const InvalidQuote = "a quote needs line items of kind labour, parts, visit_fee or other and a positive total"
+const InvalidAmount = "amount must be a decimal with no more decimal places than its currency allows"
+const InvalidCurrency = "currency must be a supported ISO 4217 code"
+const CurrencyMismatch = "amounts are in different currencies"
+const InvalidPrice = "price must be a positive amount"
+const InvalidQuoteValidity = "quote must be valid until a time in the future"
+const QuoteNotFound = "quote not found"
+const QuoteAlreadySubmitted = "provider has already quoted on this request; send a revised quote instead"
+const QuoteNotOpen = "quote is no longer open"
+const QuoteAwaitingProvider = "the latest quote is a counter-offer waiting for the provider"
+const QuoteVersionNotLatest = "only the latest quote version can be approved"
+const QuoteExpired = "quote has expired"
+const QuoteNegotiationClosed = "quotes can only be negotiated while the request awaits approval"
+const PaymentNotFound = "payment not found"
+const PaymentAlreadyExists = "request already has a payment in progress or paid"
+const RequestNotPayable = "only approved requests can be paid for"
+const InvalidCaptureMode = "capture mode must be immediate or on_completion"
+const PaymentIntentNotFound = "payment intent not found"
+const PaymentNotCapturable = "payment is not authorized for capture"
+const PaymentNotRefundable = "only captured payments can be refunded"
+const PaymentNotVoidable = "only payments that were not captured can be voided"
+const RefundExceedsPayment = "refund exceeds the amount left to refund"
+const InvalidWebhookSignature = "webhook signature is invalid"
+const InvalidWebhookEvent = "webhook event is malformed"
+const InvoiceNotAvailable = "invoices are issued once the job is completed"
+const InvoiceNotFound = "invoice not found"
+const UnbalancedJournal = "ledger journal debits and credits do not balance"
+const InvalidEarningsPeriod = "period must be day, week or month"
+const InvalidDateRange = "from and to must be dates as YYYY-MM-DD with from not after to"
+const PayoutBatchNotFound = "payout batch not found"
+const NothingToPayOut = "no provider has a balance to pay out"
+const RecordBusy = "another change to this record is in progress, try again"
+const PayoutInProgress = "another payout batch is being created"
+const JournalAlreadyPosted = "ledger journal already posted"
+const InvalidCancellationPolicy = "cancellation policy needs a provider or category scope, a free window of 0 to 720 hours, fees between 0 and 10000 basis points and non-negative penalties"
+const CancellationPolicyNotFound = "cancellation policy not found"
+const CategoryNotFound = "category not found"
+const RequestNotCancellable = "request can no longer be cancelled"
+const OnlyApprovedRequestCancelled = "only approved bookings that have not started can be cancelled by the provider"
+const DisputeNotFound = "dispute not found"
+const InvalidDispute = "a dispute needs a reason"
+const InvalidDisputeNote = "a dispute note must not be empty"
+const RequestNotDisputable = "only requests with an approved provider can be disputed"
+const DisputeAlreadyOpen = "request already has an unresolved dispute"
+const NotDisputeParty = "only the householder, the approved provider and admins can see this dispute"
+const DisputeAlreadyUnderReview = "dispute is already under review"
+const DisputeAlreadyResolved = "dispute is already resolved"
+const InvalidDisputeResolution = "outcome must be full_refund, partial_refund, provider_penalty or dismissed; partial refunds need an amount and provider penalties 1 to 10 penalty points"
+const InvalidDisputeStatus = "status must be Open, UnderReview or Resolved"
+const PaymentsDisabled = "payments are disabled"
+const NothingToRefund = "request has no captured payment to refund"
+const InvalidRefreshToken = "refresh token is invalid, expired or already used"
+const SessionNotFound = "session not found"
+const UserDeactivated = "user Deactivated by admin"
+const InvalidSigningKeySet = "invalid signing keyset"
+const UnknownSigningKey = "token is signed with an unknown key"
+const InvalidOtp = "Invalid Otp"
+const InvalidOtpPurpose = "purpose must be password_reset, email_verification or login"
+const TooManyAttempts = "too many failed attempts"
+const IllegalStatusTransition = "illegal service request status transition"
+
+// StatusTransitionError is returned when a service request is moved to a status
+// that is not reachable from its current one
+type StatusTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", IllegalStatusTransition, e.From, e.To)
+}
+
+// LockoutError is returned while an account or IP address is locked out after too many failed attempts
+type LockoutError struct {
+	Until time.Time
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s, try again after %s", TooManyAttempts, e.Until.UTC().Format(time.RFC3339))
+}
+
+// ScheduleConflictError is returned when a provider would be committed to overlapping jobs
+type ScheduleConflictError struct {
+	RequestIDs []string
+}
+
+func (e *ScheduleConflictError) Error() string {
+	return fmt.Sprintf("%s: %s", ScheduleConflict, strings.Join(e.RequestIDs, ", "))
+}
+
+// RefundNotRecordedError is returned when the gateway refunded a payment but saving what followed from
+// it failed. The money has moved, so the refund must not be retried.
+type RefundNotRecordedError struct {
+	PaymentID string
+	Err       error
+}
+
+func (e *RefundNotRecordedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RefundNotRecordedError) Unwrap() error {
+	return e.Err
+}
@@ -9,4 +9,8 @@ type AdminService interface {
 	ViewReports(limit, offset int) ([]model.ServiceRequest, error)
 	AddService(name, description string) error
 	GetUserByEmail(userEmail string) (*model.User, error)
+	RecomputeRatings() error
+	GetCancellationPolicies() ([]model.CancellationPolicy, error)
+	SaveCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error)
+	DeleteCancellationPolicy(policyID string) error
 }
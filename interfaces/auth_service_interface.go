@@ -0,0 +1,10 @@
+package interfaces
+
+import "serviceNest/model"
+
+type AuthService interface {
+	StartSession(user *model.User) (*model.TokenPair, error)
+	RefreshSession(refreshToken string) (*model.TokenPair, error)
+	EndSession(sessionID string) error
+	IsSessionRevoked(sessionID string) (bool, error)
+}
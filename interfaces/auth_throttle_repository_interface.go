@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+	"time"
+)
+
+type AuthThrottleRepository interface {
+	GetThrottle(subject model.ThrottleSubject, key string) (*model.AuthThrottle, error)
+	RecordFailure(subject model.ThrottleSubject, key string, now, resetBefore time.Time) (*model.AuthThrottle, error)
+	CreditFailure(subject model.ThrottleSubject, key string) error
+	LockUntil(subject model.ThrottleSubject, key string, until time.Time) error
+	ClaimLock(subject model.ThrottleSubject, key string, now, until time.Time) (bool, error)
+	ResetThrottle(subject model.ThrottleSubject, key string) error
+	SaveFailedAttempt(attempt *model.FailedAuthAttempt) error
+	GetFailedAttempts(email string, limit, offset int) ([]model.FailedAuthAttempt, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import "serviceNest/model"
+
+type AuthThrottleService interface {
+	CheckLockout(email, ip string) error
+	BeginAttempt(email, ip string) error
+	RecordFailure(action model.AuthAction, email, ip, reason string) error
+	RecordSuccess(email, ip string) error
+	ReleaseAttempt(email, ip string) error
+	GetFailedAttempts(email string, limit, offset int) ([]model.FailedAuthAttempt, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+	"time"
+)
+
+type AvailabilityRepository interface {
+	GetSchedule(providerID string) (*model.ProviderSchedule, error)
+	SaveSchedule(providerID string, slotMinutes int, timezone string, hours []model.WorkingHours) error
+	AddTimeOff(timeOff *model.TimeOff) error
+	DeleteTimeOff(providerID, timeOffID string) error
+	GetBookedRanges(providerID string, from, to time.Time, excludeRequestID string) ([]model.TimeRange, error)
+	GetProviderIDsByServiceID(serviceID string) ([]string, error)
+	GetEstimatedMinutes(providerID, serviceID string) (int, error)
+	GetCommitments(providerID string, from, to time.Time, excludeRequestID string) ([]model.Booking, error)
+	WithProviderLock(providerID string, fn func() error) error
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+	"time"
+)
+
+type BookingSeriesRepository interface {
+	SaveSeries(series *model.BookingSeries) error
+	GetSeriesByID(seriesID string) (*model.BookingSeries, error)
+	GetSeriesByHouseholderID(householderID string) ([]model.BookingSeries, error)
+	GetSeriesByStatus(status model.SeriesStatus) ([]model.BookingSeries, error)
+	UpdateSeries(series *model.BookingSeries) error
+	UpdateSeriesProgress(seriesID string, generatedCount int, completed bool) (bool, error)
+	AddSkippedOccurrence(seriesID string, occurrence int) error
+	GetSeriesRequestID(seriesID string, occurrence int) (string, error)
+	GetSeriesRequestIDs(seriesID string, from time.Time) ([]string, error)
+}
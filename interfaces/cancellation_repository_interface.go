@@ -0,0 +1,11 @@
+package interfaces
+
+import "serviceNest/model"
+
+type CancellationRepository interface {
+	GetPolicy(scope model.PolicyScope, scopeID string) (*model.CancellationPolicy, error)
+	GetPolicies() ([]model.CancellationPolicy, error)
+	SavePolicy(policy *model.CancellationPolicy) error
+	DeletePolicy(policyID string) error
+	SaveCancellation(request *model.ServiceRequest, fromStatus model.RequestStatus, charge *model.CancellationCharge, event *model.ServiceRequestEvent) (bool, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import "serviceNest/model"
+
+type DisputeRepository interface {
+	SaveDispute(dispute *model.Dispute) error
+	GetDisputeByID(disputeID string) (*model.Dispute, error)
+	GetDisputesByRequestID(requestID string) ([]model.Dispute, error)
+	GetDisputesByStatus(statuses []model.DisputeStatus, limit, offset int) ([]model.Dispute, error)
+	UpdateDispute(dispute *model.Dispute, fromStatus model.DisputeStatus) (bool, error)
+	SaveNote(note *model.DisputeNote) error
+	GetNotes(disputeID string) ([]model.DisputeNote, error)
+}
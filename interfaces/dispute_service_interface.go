@@ -0,0 +1,13 @@
+package interfaces
+
+import "serviceNest/model"
+
+type DisputeService interface {
+	OpenDispute(requestID, userID, role, reason, evidence string) (*model.Dispute, error)
+	GetRequestDisputes(requestID, userID, role string) ([]model.Dispute, error)
+	GetDispute(disputeID, userID, role string) (*model.Dispute, error)
+	AddNote(disputeID, userID, role, note string) (*model.DisputeNote, error)
+	GetDisputeQueue(status string, limit, offset int) ([]model.Dispute, error)
+	StartReview(disputeID, adminID string) (*model.Dispute, error)
+	ResolveDispute(disputeID, adminID string, resolution model.DisputeResolution) (*model.Dispute, error)
+}
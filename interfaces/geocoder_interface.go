@@ -0,0 +1,5 @@
+package interfaces
+
+type Geocoder interface {
+	Geocode(pincode string) (latitude float64, longitude float64, err error)
+}
@@ -7,17 +7,34 @@ import (
 
 type HouseholderService interface {
 	ViewApprovedRequests(householderID string, limit, offset int, sortOrder string) ([]model.ServiceRequest, error)
-	ApproveServiceRequest(requestID string, providerID string, householderID string) error
-	AddReview(providerID, householderID, serviceID, comments string, rating float64) error
+	ApproveServiceRequest(requestID string, providerID string, householderID string, quoteVersion int) ([]model.ScheduleConflict, error)
+	CounterQuote(requestID, providerID, householderID string, counter *model.Quote) (*model.Quote, error)
+	GetRequestQuotes(requestID, userID, role string) ([]model.Quote, error)
+	AddReview(requestID, householderID, comments string, rating float64) error
 	ViewServiceRequestStatus(requestID string) (string, error)
 	RescheduleServiceRequest(requestID string, newTime time.Time, householderID string) error
-	CancelServiceRequest(requestID string, householderID string) error
-	GetAvailableServices(limit, offset int) ([]model.Service, error)
+	CancelServiceRequest(requestID string, householderID string, reason string) (*model.CancellationTerms, error)
+	GetCancellationTerms(requestID, userID, role string) (*model.CancellationTerms, error)
+	GetAvailableServices(limit, offset int, sortBy string) ([]model.Service, error)
 	ViewBookingHistory(householderID string, limit, offset int, status string) ([]model.ServiceRequest, error)
 	RequestService(householderID string, serviceName string, category string, description string, scheduleTime *time.Time) (string, error)
-	GetServicesByCategory(category string) ([]model.Service, error)
-	SearchService(householder *model.Householder, serviceType string) ([]model.ServiceProvider, error)
+	GetServicesByCategory(category string, sortBy string) ([]model.Service, error)
+	SearchServices(query string, limit, offset int) ([]model.ServiceSearchResult, error)
+	GetProviderSlots(providerID string, date time.Time) ([]model.Slot, error)
+	SearchService(householderID string, serviceType string, radiusKm float64) ([]model.ServiceProvider, error)
 	CancelAcceptedRequest(requestID, householderID string) error
 	ViewStatus(householderID string, limit, offset int, status string) ([]model.ServiceRequest, error)
 	GetAllServiceCategory() ([]model.Category, error)
+	GetServiceRequestHistory(requestID, userID, role string) ([]model.ServiceRequestEvent, error)
+	ConfirmServiceCompletion(requestID, householderID string) error
+	UpdateReview(reviewID, householderID, comments string, rating float64) error
+	DeleteReview(reviewID, householderID string) error
+	CreateBookingSeries(householderID, serviceName, category, description string, firstTime time.Time, timezone string, rule model.RecurrenceRule) (string, error)
+	ViewBookingSeries(householderID string) ([]model.BookingSeries, error)
+	GetBookingSeries(seriesID, householderID string) (*model.BookingSeries, error)
+	SkipSeriesOccurrence(seriesID, householderID string, start time.Time) error
+	PauseBookingSeries(seriesID, householderID string) error
+	ResumeBookingSeries(seriesID, householderID string) error
+	CancelBookingSeries(seriesID, householderID string) error
+	GenerateSeriesOccurrences() error
 }
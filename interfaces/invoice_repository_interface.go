@@ -0,0 +1,8 @@
+package interfaces
+
+import "serviceNest/model"
+
+type InvoiceRepository interface {
+	SaveInvoice(invoice *model.Invoice) error
+	GetInvoiceByRequestID(requestID string) (*model.Invoice, error)
+}
@@ -0,0 +1,7 @@
+package interfaces
+
+import "serviceNest/model"
+
+type InvoiceService interface {
+	GetInvoice(requestID, userID, role string) (*model.Invoice, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+	"time"
+)
+
+type LedgerRepository interface {
+	SaveJournal(journal *model.LedgerJournal) (bool, error)
+	SaveJournals(journals []*model.LedgerJournal) error
+	WithPayoutLock(fn func() error) error
+	GetUnpostedCompletedJobs(limit int) ([]model.CompletedJob, error)
+	GetProviderJournals(providerID string, from, to time.Time) ([]model.LedgerJournal, error)
+	GetJournalsByReference(kind model.JournalKind, reference string) ([]model.LedgerJournal, error)
+	GetPayableBalances(providerID string) ([]model.ProviderBalance, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+	"time"
+)
+
+type LedgerService interface {
+	PostCompletedJobs() error
+	GetProviderEarnings(providerID string, from, to time.Time, period string, location *time.Location) (*model.EarningsSummary, error)
+	CreatePayoutBatch() (*model.PayoutBatch, error)
+	GetPayoutBatch(batchID string) (*model.PayoutBatch, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+	"time"
+)
+
+// OtpStore keeps one-time codes between the request that sends them and the one that uses them
+type OtpStore interface {
+	SaveOTP(otp *model.Otp) error
+	ConsumeOTP(email string, purpose model.OtpPurpose, codeHash string, now time.Time) (bool, error)
+	DeleteExpiredOTPs(now time.Time) (int64, error)
+}
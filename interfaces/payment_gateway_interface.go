@@ -0,0 +1,13 @@
+package interfaces
+
+import "serviceNest/model"
+
+// PaymentGateway is the payment provider householders pay through
+type PaymentGateway interface {
+	Name() string
+	CreateIntent(amount model.Money, reference string) (*model.PaymentIntent, error)
+	Capture(intentID string, amount model.Money) error
+	Refund(intentID string, amount model.Money) (refundID string, err error)
+	Void(intentID string) error
+	VerifyWebhook(payload []byte, signature string) (*model.PaymentWebhookEvent, error)
+}
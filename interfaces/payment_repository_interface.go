@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+	"time"
+)
+
+type PaymentRepository interface {
+	SavePayment(payment *model.Payment) error
+	GetPaymentByID(paymentID string) (*model.Payment, error)
+	GetPaymentByIntentID(gateway, intentID string) (*model.Payment, error)
+	GetPaymentsByRequestID(requestID string) ([]model.Payment, error)
+	UpdatePayment(payment *model.Payment, fromStatus model.PaymentStatus) (bool, error)
+	ReserveRefund(paymentID string, amount int64, now time.Time) (bool, error)
+	ReleaseRefund(paymentID string, amount int64, now time.Time) error
+	GetCapturablePayments() ([]model.Payment, error)
+	GetCancelledPayments() ([]model.CancelledPayment, error)
+	ApplyWebhookEvent(gateway string, event *model.PaymentWebhookEvent, payment *model.Payment, fromStatus model.PaymentStatus) (bool, bool, error)
+}
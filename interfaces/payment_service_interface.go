@@ -0,0 +1,12 @@
+package interfaces
+
+import "serviceNest/model"
+
+type PaymentService interface {
+	CreatePayment(requestID, householderID string, mode model.CaptureMode) (*model.Payment, error)
+	GetRequestPayments(requestID, userID, role string) ([]model.Payment, error)
+	HandleWebhook(payload []byte, signature string) error
+	CapturePendingPayments() error
+	SettleCancelledPayments() error
+	RefundPayment(paymentID, adminID string, amount *model.Money, reason string) (*model.Payment, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+	"time"
+)
+
+type QuoteRepository interface {
+	SaveQuote(quote *model.Quote) error
+	WithRequestLock(requestID string, fn func() error) error
+	GetQuoteByID(quoteID string) (*model.Quote, error)
+	GetQuotesByRequestID(requestID string) ([]model.Quote, error)
+	GetLatestQuote(requestID, providerID string) (*model.Quote, error)
+	UpdateQuoteStatus(quoteID string, status model.QuoteStatus) error
+	ApproveQuote(quoteID string, version int) (bool, error)
+	DeclineOpenQuotes(requestID, exceptProviderID string) error
+	ExpireLapsedQuotes(now time.Time) (int64, error)
+	DeclineStaleQuotes() (int64, error)
+}
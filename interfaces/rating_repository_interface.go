@@ -0,0 +1,6 @@
+package interfaces
+
+type RatingRepository interface {
+	RecalculateProviderRating(providerID string, serviceID string) error
+	RecalculateAllRatings() error
+}
@@ -0,0 +1,5 @@
+package interfaces
+
+type RequestExpiryService interface {
+	ExpireStaleRequests() error
+}
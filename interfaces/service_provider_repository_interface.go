@@ -6,15 +6,21 @@ type ServiceProviderRepository interface {
 	UpdateServiceProvider(provider *model.ServiceProvider) error
 	GetProviderByServiceID(serviceID string) (*model.ServiceProvider, error)
 	GetProvidersByServiceType(serviceType string) ([]model.ServiceProvider, error)
+	UpdateServiceRadius(providerID string, radiusKm float64) error
 	GetProviderByID(providerID string) (*model.ServiceProvider, error)
 	SaveServiceProvider(provider model.ServiceProvider) error
 	GetProviderDetailByID(providerID string, serviceId string) (*model.ServiceProviderDetails, error)
 	SaveServiceProviderDetail(provider *model.ServiceProviderDetails, requestID string, serviceID string) error
 	UpdateServiceProviderDetailByRequestID(provider *model.ServiceProviderDetails, requestID string) error
+	UpdateQuotedPrice(providerID, requestID string, price model.Money) error
 	IsProviderApproved(providerID string) (bool, error)
 	AddReview(review model.Review) error
-	UpdateProviderRating(providerID string, serviceId string, rating float64) error
 	GetReviewsByProviderID(providerID string, limit, offset int, serviceID string) ([]model.Review, error)
 	AddServiceToProvider(providerID, serviceID string) error
 	DeleteServicesByProviderID(userID string) error
+	GetReviewByID(reviewID string) (*model.Review, error)
+	UpdateReview(review *model.Review) error
+	DeleteReview(reviewID string) error
+	AddReviewReply(reviewID string, reply model.ReviewReply) error
+	DeclineUnapprovedQuotes() (int64, error)
 }
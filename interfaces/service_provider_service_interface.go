@@ -1,6 +1,9 @@
 package interfaces
 
-import "serviceNest/model"
+import (
+	"serviceNest/model"
+	"time"
+)
 
 type ServiceProviderService interface {
 	GetReviews(providerID string, limit, offset int, serviceID string) ([]model.Review, error)
@@ -8,11 +11,21 @@ type ServiceProviderService interface {
 	GetServiceByID(serviceID string) (*model.Service, error)
 	ViewServices(providerID string) ([]model.Service, error)
 	UpdateAvailability(providerID string, availability bool) error
+	UpdateServiceRadius(providerID string, radiusKm float64) error
+	GetSchedule(providerID string) (*model.ProviderSchedule, error)
+	UpdateSchedule(providerID string, slotMinutes int, timezone string, hours []model.WorkingHours) error
+	AddTimeOff(providerID string, start, end time.Time, reason string) (string, error)
+	RemoveTimeOff(providerID, timeOffID string) error
 	DeclineServiceRequest(providerID, requestID string) error
 	GetServiceRequestByID(requestID string) (*model.ServiceRequest, error)
-	AcceptServiceRequest(providerID, requestID string, estimatedPrice string) error
+	AcceptServiceRequest(providerID, requestID string, quote *model.Quote) ([]model.ScheduleConflict, error)
+	ReviseQuote(providerID, requestID string, revision *model.Quote) (*model.Quote, error)
 	RemoveService(providerID, serviceID string) error
 	GetAllServiceRequests(providerId string, serviceID string, limit, offset int) ([]model.ServiceRequest, error)
 	UpdateService(providerID, serviceID string, updatedService model.Service) error
 	AddService(providerID string, newService model.Service) (string, error)
+	StartServiceRequest(providerID, requestID string) error
+	CompleteServiceRequest(providerID, requestID string) error
+	CancelApprovedRequest(providerID, requestID, reason string) (*model.CancellationTerms, error)
+	ReplyToReview(providerID, reviewID, comments string) error
 }
@@ -15,4 +15,5 @@ type ServiceRepository interface {
 	CategoryExists(categoryName string) (bool, error)
 	GetAllCategory() ([]model.Category, error)
 	AddCategory(category *model.Category) error
+	GetRankingSignals() (*model.RankingSignals, error)
 }
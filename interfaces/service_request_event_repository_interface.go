@@ -0,0 +1,8 @@
+package interfaces
+
+import "serviceNest/model"
+
+type ServiceRequestEventRepository interface {
+	SaveEvent(event *model.ServiceRequestEvent) error
+	GetEventsByRequestID(requestID string) ([]model.ServiceRequestEvent, error)
+}
@@ -1,11 +1,14 @@
 package interfaces
 
-import "serviceNest/model"
+import (
+	"serviceNest/model"
+	"time"
+)
 
 type ServiceRequestRepository interface {
 	//SaveAllServiceRequests(serviceRequests []model.ServiceRequest) error
 	GetAllServiceRequests(limit, offset int) ([]model.ServiceRequest, error)
-	UpdateServiceRequest(updatedRequest *model.ServiceRequest) error
+	UpdateServiceRequest(updatedRequest *model.ServiceRequest, fromStatus model.RequestStatus) error
 	GetServiceRequestsByHouseholderID(householderID string, limit, offset int, status string) ([]model.ServiceRequest, error)
 	GetServiceRequestByID(requestID string) (*model.ServiceRequest, error)
 	SaveServiceRequest(request model.ServiceRequest) error
@@ -13,4 +16,7 @@ type ServiceRequestRepository interface {
 	GetServiceProviderByRequestID(requestID, providerID string) (*model.ServiceRequest, error)
 	GetApproveServiceRequestsByHouseholderID(householderID string, limit, offset int, sortOrder string) ([]model.ServiceRequest, error)
 	GetAllPendingRequestsByProvider(providerId string, serviceID string, limit, offset int) ([]model.ServiceRequest, error)
+	GetApprovedProviderIDByRequestID(requestID string) (string, error)
+	GetStaleRequestIDs(deadline time.Time) ([]string, error)
+	ExpireRequest(requestID string, fromStatus model.RequestStatus) (bool, error)
 }
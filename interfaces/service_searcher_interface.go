@@ -0,0 +1,7 @@
+package interfaces
+
+import "serviceNest/model"
+
+type ServiceSearcher interface {
+	Search(query string, limit, offset int) ([]model.ServiceSearchResult, error)
+}
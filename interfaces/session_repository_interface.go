@@ -0,0 +1,12 @@
+package interfaces
+
+import "serviceNest/model"
+
+type SessionRepository interface {
+	CreateSession(session *model.Session, refreshToken *model.RefreshToken) error
+	GetSession(sessionID string) (*model.Session, error)
+	GetRefreshToken(tokenHash string) (*model.RefreshToken, error)
+	RotateRefreshToken(usedHash string, next *model.RefreshToken) (bool, error)
+	RevokeSession(sessionID, reason string) error
+	RevokeUserSessions(userID, reason string) error
+}
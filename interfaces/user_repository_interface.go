@@ -12,4 +12,5 @@ type UserRepository interface {
 	DeActivateUser(userID string) error
 	GetSecurityAnswerByEmail(userEmail string) (*string, error)
 	UpdatePassword(userEmail, updatedPassword string) error
+	UpdateUserLocation(userID, pincode string, latitude, longitude float64) error
 }
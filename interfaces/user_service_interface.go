@@ -5,9 +5,13 @@ import "serviceNest/model"
 type UserService interface {
 	CreateUser(user *model.User) error
 	CheckUserExists(email string) (*model.User, error)
-	UpdateUser(userID string, newEmail, newPassword, newAddress, newPhone *string) error
+	UpdateUser(userID string, newEmail, newPassword, newAddress, newPhone, newTimezone *string) error
 	ViewProfileByID(userID string) (*model.User, error)
 	ForgetPasword(email string, answer string, updatedPassword string) error
-	GenerateOtp(email string) error
+	GenerateOtp(email string, purpose model.OtpPurpose) error
+	VerifyOtp(email string, purpose model.OtpPurpose, otp string) error
+	PurgeExpiredOtps() error
 	VerifyAndUpdatePassword(email, password string, otp string) error
+	GetUserTimezone(userID string) (string, error)
+	UpdateLocation(userID string, pincode *string, latitude, longitude *float64) error
 }
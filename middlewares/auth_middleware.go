@@ -3,57 +3,81 @@ package middlewares
 import (
 	"context"
 	"github.com/golang-jwt/jwt"
+	"github.com/gorilla/mux"
 	"net/http"
+	"serviceNest/interfaces"
 	"serviceNest/logger"
 	"serviceNest/response"
 	"serviceNest/util"
 	"strings"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+// AuthMiddleware verifies the bearer token and stores the user's ID, role and session in the request
+// context. Tokens of a session that was logged out or revoked are refused even before they expire.
+func AuthMiddleware(authService interfaces.AuthService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		authorizationHeader := r.Header.Get("Authorization")
-		if authorizationHeader == "" {
-			response.ErrorResponse(w, http.StatusUnauthorized, "Missing token", 1002)
-			return
-		}
+			authorizationHeader := r.Header.Get("Authorization")
+			if authorizationHeader == "" {
+				response.ErrorResponse(w, http.StatusUnauthorized, "Missing token", 1002)
+				return
+			}
 
-		// Split Bearer from token
-		tokenString := strings.Split(authorizationHeader, "Bearer ")[1]
+			// Split Bearer from token
+			tokenString := strings.TrimPrefix(authorizationHeader, "Bearer ")
 
-		// Verify JWT token
-		token, err := util.VerifyJWT(tokenString)
-		if err != nil {
-			response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
-			return
-		}
+			// Verify JWT token
+			token, err := util.VerifyJWT(tokenString)
+			if err != nil {
+				response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
+				return
+			}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok || !token.Valid {
-			response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
-			return
-		}
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok || !token.Valid {
+				response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
+				return
+			}
 
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
-			return
-		}
+			userID, ok := claims["user_id"].(string)
+			if !ok {
+				response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
+				return
+			}
 
-		role, ok := claims["role"].(string)
-		if !ok {
-			response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
-			return
-		}
+			role, ok := claims["role"].(string)
+			if !ok {
+				response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
+				return
+			}
 
-		// Store both the userID and role in the request context
-		ctx := context.WithValue(r.Context(), "userID", userID)
-		ctx = context.WithValue(ctx, "role", role)
+			sessionID, ok := claims["sid"].(string)
+			if !ok {
+				response.ErrorResponse(w, http.StatusUnauthorized, "Invalid token", 1002)
+				return
+			}
 
-		// Pass the context with the userID and role to the next handler
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+			revoked, err := authService.IsSessionRevoked(sessionID)
+			if err != nil {
+				logger.Error("could not check session", map[string]interface{}{"sessionID": sessionID, "error": err.Error()})
+				response.ErrorResponse(w, http.StatusInternalServerError, "Error checking session", 1006)
+				return
+			}
+			if revoked {
+				response.ErrorResponse(w, http.StatusUnauthorized, "Session revoked", 1002)
+				return
+			}
+
+			// Store the userID, role and session in the request context
+			ctx := context.WithValue(r.Context(), "userID", userID)
+			ctx = context.WithValue(ctx, "role", role)
+			ctx = context.WithValue(ctx, "sessionID", sessionID)
+
+			// Pass the context with the userID and role to the next handler
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
 }
 
 func HouseHolderAuthMiddleware(next http.Handler) http.Handler {
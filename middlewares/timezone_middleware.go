@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"context"
+	"github.com/gorilla/mux"
+	"net/http"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/response"
+	"serviceNest/util"
+)
+
+// TimezoneMiddleware looks up the authenticated user's preferred timezone, stores it in the request
+// context for parsing zone-less input and renders every time in success responses in that zone.
+// It must run after AuthMiddleware.
+func TimezoneMiddleware(userService interfaces.UserService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			location := util.DefaultLocation()
+			if userID, ok := r.Context().Value("userID").(string); ok {
+				timezone, err := userService.GetUserTimezone(userID)
+				if err != nil {
+					logger.Error("could not load user timezone", map[string]interface{}{"userID": userID, "error": err.Error()})
+				} else {
+					location = util.LoadUserLocation(timezone)
+				}
+			}
+
+			ctx := context.WithValue(r.Context(), "timezone", location)
+			next.ServeHTTP(response.WithLocation(w, location), r.WithContext(ctx))
+		})
+	}
+}
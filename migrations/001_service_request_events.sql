@@ -0,0 +1,16 @@
+-- Status history of service requests: one row per status change, with who made it and why.
+-- Requests created before this table existed have no history.
+
+CREATE TABLE service_request_events (
+    id                 VARCHAR(36)  NOT NULL PRIMARY KEY,
+    service_request_id VARCHAR(36)  NOT NULL,
+    actor_id           VARCHAR(36)  NOT NULL,
+    actor_role         VARCHAR(20)  NOT NULL,
+    -- empty for the event that created the request
+    old_status         VARCHAR(20)  NOT NULL DEFAULT '',
+    new_status         VARCHAR(20)  NOT NULL,
+    reason             TEXT         NULL,
+    created_at         DATETIME     NOT NULL,
+    KEY idx_service_request_events_request (service_request_id, created_at),
+    CONSTRAINT fk_service_request_events_request FOREIGN KEY (service_request_id) REFERENCES service_requests (id)
+);
@@ -0,0 +1,9 @@
+-- Providers check in and out of jobs and householders confirm that a job was done. Requests completed
+-- before confirmation existed count as confirmed.
+
+ALTER TABLE service_requests
+    ADD COLUMN actual_start_time DATETIME NULL AFTER approve_status,
+    ADD COLUMN actual_end_time DATETIME NULL AFTER actual_start_time,
+    ADD COLUMN completion_confirmed TINYINT(1) NOT NULL DEFAULT 0 AFTER actual_end_time;
+
+UPDATE service_requests SET completion_confirmed = 1 WHERE status = 'Completed';
@@ -0,0 +1,6 @@
+-- Reviews belong to the completed request they were written for, one review per request. Reviews
+-- written before that keep a NULL request, which the unique key allows any number of.
+
+ALTER TABLE reviews
+    ADD COLUMN service_request_id VARCHAR(36) NULL AFTER id,
+    ADD UNIQUE KEY uq_reviews_request (service_request_id);
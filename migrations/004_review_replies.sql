@@ -0,0 +1,6 @@
+-- Householders can edit their reviews and providers can reply to them once.
+
+ALTER TABLE reviews
+    ADD COLUMN updated_at DATETIME NULL AFTER review_date,
+    ADD COLUMN reply TEXT NULL AFTER updated_at,
+    ADD COLUMN reply_date DATETIME NULL AFTER reply;
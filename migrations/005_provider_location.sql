@@ -0,0 +1,8 @@
+-- Users can give a pincode, which is geocoded into the latitude and longitude they already have, and
+-- providers can limit how far they travel. NULL radius means the default search radius applies.
+
+ALTER TABLE users
+    ADD COLUMN pincode VARCHAR(16) NULL AFTER contact;
+
+ALTER TABLE service_providers
+    ADD COLUMN service_radius_km DOUBLE NULL AFTER availability;
@@ -0,0 +1,11 @@
+-- FULLTEXT indexes used by the MySQL service searcher to find candidate services by their text or their
+-- provider's name. They use the ngram parser so a misspelt word still shares bigrams with the word it
+-- stands for wherever the typo is; this relies on the default ngram_token_size of 2. Stopwords are
+-- turned off while the indexes are built, otherwise every bigram containing a one-letter stopword
+-- such as "a" would be left out.
+
+SET SESSION innodb_ft_enable_stopword = OFF;
+
+ALTER TABLE services ADD FULLTEXT INDEX ft_services (name, description, category) WITH PARSER ngram;
+
+ALTER TABLE users ADD FULLTEXT INDEX ft_users_name (name) WITH PARSER ngram;
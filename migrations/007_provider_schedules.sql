@@ -0,0 +1,25 @@
+-- Provider calendars: the slot length they book in, their weekly working hours and their time off.
+-- Working hours are "15:04" clock times in the schedule's timezone; weekday 0 is Sunday. Providers
+-- without a slot length use the default one, and schedules without a timezone use the provider's own
+-- timezone or the default one.
+
+ALTER TABLE service_providers
+    ADD COLUMN slot_minutes INT NULL AFTER availability,
+    ADD COLUMN schedule_timezone VARCHAR(64) NULL AFTER slot_minutes;
+
+CREATE TABLE provider_working_hours (
+    provider_id VARCHAR(36) NOT NULL,
+    weekday     TINYINT     NOT NULL,
+    start_time  CHAR(5)     NOT NULL,
+    end_time    CHAR(5)     NOT NULL,
+    KEY idx_provider_working_hours_provider (provider_id, weekday)
+);
+
+CREATE TABLE provider_time_off (
+    id          VARCHAR(36)  NOT NULL PRIMARY KEY,
+    provider_id VARCHAR(36)  NOT NULL,
+    start_time  DATETIME     NOT NULL,
+    end_time    DATETIME     NOT NULL,
+    reason      VARCHAR(255) NOT NULL DEFAULT '',
+    KEY idx_provider_time_off_provider (provider_id, end_time)
+);
@@ -0,0 +1,5 @@
+-- How long a job of the service takes, used to check providers are not booked for overlapping jobs.
+-- 0 means the default job duration.
+
+ALTER TABLE services
+    ADD COLUMN estimated_minutes INT NOT NULL DEFAULT 0 AFTER rating_count;
@@ -0,0 +1,5 @@
+-- Users can pick the IANA timezone their times are read and rendered in. NULL means the default
+-- timezone.
+
+ALTER TABLE users
+    ADD COLUMN timezone VARCHAR(64) NULL AFTER longitude;
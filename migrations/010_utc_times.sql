@@ -0,0 +1,10 @@
+-- Request times and review dates used to be stored as India wall-clock times; they are UTC from now
+-- on. Run this together with the release that starts writing UTC, before any request is created or
+-- rescheduled or any review is left by it, and only once.
+
+UPDATE service_requests
+SET scheduled_time = CONVERT_TZ(scheduled_time, '+05:30', '+00:00'),
+    requested_time = CONVERT_TZ(requested_time, '+05:30', '+00:00');
+
+UPDATE reviews
+SET review_date = CONVERT_TZ(review_date, '+05:30', '+00:00');
@@ -0,0 +1,35 @@
+-- Recurring bookings. Each series materialises one service request per occurrence ahead of time, never
+-- two; occurrences are numbered from 0 and skipped ones are listed in booking_series_skips.
+
+CREATE TABLE booking_series (
+    id               VARCHAR(36)  NOT NULL PRIMARY KEY,
+    householder_id   VARCHAR(36)  NOT NULL,
+    service_id       VARCHAR(36)  NOT NULL,
+    service_name     VARCHAR(255) NOT NULL,
+    description      TEXT         NOT NULL,
+    frequency        VARCHAR(16)  NOT NULL,
+    until            DATETIME     NULL,
+    occurrence_count INT          NOT NULL DEFAULT 0,
+    first_time       DATETIME     NOT NULL,
+    timezone         VARCHAR(64)  NOT NULL,
+    status           VARCHAR(20)  NOT NULL,
+    -- the provider approved on the first occurrence, who is kept for the rest of the series
+    provider_id      VARCHAR(36)  NULL,
+    provider_price   VARCHAR(255) NULL,
+    generated_count  INT          NOT NULL DEFAULT 0,
+    created_at       DATETIME     NOT NULL,
+    KEY idx_booking_series_householder (householder_id, created_at),
+    KEY idx_booking_series_status (status)
+);
+
+CREATE TABLE booking_series_skips (
+    series_id  VARCHAR(36) NOT NULL,
+    occurrence INT         NOT NULL,
+    PRIMARY KEY (series_id, occurrence),
+    CONSTRAINT fk_booking_series_skips_series FOREIGN KEY (series_id) REFERENCES booking_series (id)
+);
+
+ALTER TABLE service_requests
+    ADD COLUMN series_id VARCHAR(36) NULL,
+    ADD COLUMN series_occurrence INT NOT NULL DEFAULT 0 AFTER series_id,
+    ADD UNIQUE KEY uq_service_requests_series (series_id, series_occurrence);
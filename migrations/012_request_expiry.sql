@@ -0,0 +1,8 @@
+-- Requests nobody approved in time expire, and provider quotes the householder can no longer approve
+-- are marked declined. The index serves the expiry job's scan for stale requests.
+
+ALTER TABLE service_provider_details
+    ADD COLUMN declined TINYINT(1) NOT NULL DEFAULT 0 AFTER approve;
+
+ALTER TABLE service_requests
+    ADD KEY idx_service_requests_expiry (status, approve_status, scheduled_time);
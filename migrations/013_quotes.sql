@@ -0,0 +1,34 @@
+-- Versioned quotes. Every offer or counter-offer on a request is a new version of the provider's quote
+-- with its priced line items; amounts are minor units in the quote's currency. A series booked from a
+-- quote remembers which one.
+
+CREATE TABLE quotes (
+    id                 VARCHAR(36) NOT NULL PRIMARY KEY,
+    service_request_id VARCHAR(36) NOT NULL,
+    provider_id        VARCHAR(36) NOT NULL,
+    version            INT         NOT NULL,
+    proposed_by        VARCHAR(20) NOT NULL,
+    currency           CHAR(3)     NOT NULL,
+    total              BIGINT      NOT NULL,
+    valid_until        DATETIME    NOT NULL,
+    status             VARCHAR(20) NOT NULL,
+    note               TEXT        NOT NULL,
+    created_at         DATETIME    NOT NULL,
+    -- two concurrent revisions cannot both become the next version
+    UNIQUE KEY uq_quotes_version (service_request_id, provider_id, version),
+    KEY idx_quotes_status (status, valid_until),
+    CONSTRAINT fk_quotes_request FOREIGN KEY (service_request_id) REFERENCES service_requests (id)
+);
+
+CREATE TABLE quote_line_items (
+    quote_id    VARCHAR(36)  NOT NULL,
+    position    INT          NOT NULL,
+    kind        VARCHAR(16)  NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    amount      BIGINT       NOT NULL,
+    PRIMARY KEY (quote_id, position),
+    CONSTRAINT fk_quote_line_items_quote FOREIGN KEY (quote_id) REFERENCES quotes (id)
+);
+
+ALTER TABLE booking_series
+    ADD COLUMN quote_id VARCHAR(36) NULL AFTER provider_price;
@@ -0,0 +1,36 @@
+-- Prices move from FLOAT/VARCHAR columns to exact minor units plus an ISO 4217 currency code.
+-- Existing prices were all entered in rupees, so they are converted to paise in INR.
+
+ALTER TABLE services
+    ADD COLUMN price_minor BIGINT NOT NULL DEFAULT 0 AFTER description,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR' AFTER price_minor;
+
+UPDATE services SET price_minor = ROUND(price * 100);
+
+ALTER TABLE services DROP COLUMN price;
+
+-- Provider prices on requests were free text; anything that is not a plain decimal is kept as 0 and
+-- listed by the check below so it can be fixed by hand.
+ALTER TABLE service_provider_details
+    ADD COLUMN price_minor BIGINT NOT NULL DEFAULT 0 AFTER address,
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'INR' AFTER price_minor;
+
+UPDATE service_provider_details
+SET price_minor = ROUND(CAST(TRIM(price) AS DECIMAL(17, 2)) * 100)
+WHERE TRIM(price) REGEXP '^[0-9]+(\\.[0-9]+)?$';
+
+SELECT id, service_request_id, price AS unconverted_price
+FROM service_provider_details
+WHERE price IS NOT NULL AND TRIM(price) NOT REGEXP '^[0-9]+(\\.[0-9]+)?$';
+
+ALTER TABLE service_provider_details DROP COLUMN price;
+
+ALTER TABLE booking_series
+    ADD COLUMN provider_price_minor BIGINT NULL AFTER provider_id,
+    ADD COLUMN provider_currency CHAR(3) NULL AFTER provider_price_minor;
+
+UPDATE booking_series
+SET provider_price_minor = ROUND(CAST(TRIM(provider_price) AS DECIMAL(17, 2)) * 100), provider_currency = 'INR'
+WHERE TRIM(provider_price) REGEXP '^[0-9]+(\\.[0-9]+)?$';
+
+ALTER TABLE booking_series DROP COLUMN provider_price;
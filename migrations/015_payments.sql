@@ -0,0 +1,35 @@
+-- Payments householders make for approved requests through a payment gateway. Amounts are minor units
+-- in the payment's currency, like every other price.
+
+CREATE TABLE payments (
+    id                 VARCHAR(36)  NOT NULL PRIMARY KEY,
+    service_request_id VARCHAR(36)  NOT NULL,
+    householder_id     VARCHAR(36)  NOT NULL,
+    provider_id        VARCHAR(36)  NOT NULL,
+    currency           CHAR(3)      NOT NULL,
+    amount_minor       BIGINT       NOT NULL,
+    refunded_minor     BIGINT       NOT NULL DEFAULT 0,
+    status             VARCHAR(20)  NOT NULL,
+    capture_mode       VARCHAR(20)  NOT NULL,
+    gateway            VARCHAR(32)  NOT NULL,
+    intent_id          VARCHAR(128) NOT NULL,
+    created_at         DATETIME     NOT NULL,
+    updated_at         DATETIME     NOT NULL,
+    -- Set while the payment is not Failed, so a request has at most one payment that is live
+    live_request_id    VARCHAR(36)  AS (IF(status = 'Failed', NULL, service_request_id)) STORED,
+    UNIQUE KEY uq_payments_intent (gateway, intent_id),
+    UNIQUE KEY uq_payments_live_request (live_request_id),
+    KEY idx_payments_request (service_request_id),
+    KEY idx_payments_status (status, capture_mode),
+    CONSTRAINT fk_payments_request FOREIGN KEY (service_request_id) REFERENCES service_requests (id)
+);
+
+-- Webhook events already applied, so redelivered callbacks are recognised and ignored
+CREATE TABLE payment_webhook_events (
+    gateway      VARCHAR(32)  NOT NULL,
+    event_id     VARCHAR(128) NOT NULL,
+    type         VARCHAR(64)  NOT NULL,
+    intent_id    VARCHAR(128) NOT NULL,
+    processed_at DATETIME     NOT NULL,
+    PRIMARY KEY (gateway, event_id)
+);
@@ -0,0 +1,48 @@
+-- Invoices for completed requests. Each provider's invoices are numbered 1, 2, 3, ... without gaps:
+-- invoice_sequences holds the last number issued and is advanced in the same transaction as the insert.
+
+CREATE TABLE invoice_sequences (
+    provider_id VARCHAR(36) NOT NULL PRIMARY KEY,
+    last_number BIGINT      NOT NULL
+);
+
+CREATE TABLE invoices (
+    id                  VARCHAR(36)  NOT NULL PRIMARY KEY,
+    number              VARCHAR(32)  NOT NULL,
+    sequence            BIGINT       NOT NULL,
+    service_request_id  VARCHAR(36)  NOT NULL,
+    provider_id         VARCHAR(36)  NOT NULL,
+    householder_id      VARCHAR(36)  NOT NULL,
+    householder_name    VARCHAR(255) NOT NULL,
+    householder_address VARCHAR(512) NOT NULL DEFAULT '',
+    provider_name       VARCHAR(255) NOT NULL,
+    provider_address    VARCHAR(512) NOT NULL DEFAULT '',
+    provider_contact    VARCHAR(64)  NOT NULL DEFAULT '',
+    service_name        VARCHAR(255) NOT NULL,
+    scheduled_time      DATETIME     NOT NULL,
+    started_at          DATETIME     NULL,
+    completed_at        DATETIME     NULL,
+    quote_id            VARCHAR(36)  NULL,
+    quote_version       INT          NOT NULL DEFAULT 0,
+    currency            CHAR(3)      NOT NULL,
+    taxable             BIGINT       NOT NULL,
+    tax_label           VARCHAR(16)  NOT NULL,
+    tax_rate            INT          NOT NULL,
+    tax                 BIGINT       NOT NULL,
+    total               BIGINT       NOT NULL,
+    issued_at           DATETIME     NOT NULL,
+    UNIQUE KEY uq_invoices_request (service_request_id),
+    UNIQUE KEY uq_invoices_provider_sequence (provider_id, sequence),
+    UNIQUE KEY uq_invoices_number (number),
+    CONSTRAINT fk_invoices_request FOREIGN KEY (service_request_id) REFERENCES service_requests (id)
+);
+
+CREATE TABLE invoice_line_items (
+    invoice_id  VARCHAR(36)  NOT NULL,
+    position    INT          NOT NULL,
+    kind        VARCHAR(16)  NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    amount      BIGINT       NOT NULL,
+    PRIMARY KEY (invoice_id, position),
+    CONSTRAINT fk_invoice_line_items_invoice FOREIGN KEY (invoice_id) REFERENCES invoices (id)
+);
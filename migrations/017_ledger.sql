@@ -0,0 +1,29 @@
+-- Double-entry ledger of provider earnings. Every journal's entries add up to zero: debits are stored
+-- as positive amounts and credits as negative ones, in the journal's currency.
+
+CREATE TABLE ledger_journals (
+    id                 VARCHAR(36)  NOT NULL PRIMARY KEY,
+    kind               VARCHAR(16)  NOT NULL,
+    reference          VARCHAR(128) NOT NULL,
+    provider_id        VARCHAR(36)  NOT NULL,
+    service_request_id VARCHAR(36)  NULL,
+    service_id         VARCHAR(36)  NULL,
+    service_name       VARCHAR(255) NOT NULL DEFAULT '',
+    currency           CHAR(3)      NOT NULL,
+    occurred_at        DATETIME     NOT NULL,
+    created_at         DATETIME     NOT NULL,
+    -- a job, refund or payout batch is posted once per provider and currency
+    UNIQUE KEY uq_ledger_journals_reference (kind, reference, provider_id, currency),
+    KEY idx_ledger_journals_provider (provider_id, occurred_at)
+);
+
+CREATE TABLE ledger_entries (
+    journal_id   VARCHAR(36) NOT NULL,
+    position     INT         NOT NULL,
+    account      VARCHAR(32) NOT NULL,
+    provider_id  VARCHAR(36) NULL,
+    amount_minor BIGINT      NOT NULL,
+    PRIMARY KEY (journal_id, position),
+    KEY idx_ledger_entries_account (account, provider_id),
+    CONSTRAINT fk_ledger_entries_journal FOREIGN KEY (journal_id) REFERENCES ledger_journals (id)
+);
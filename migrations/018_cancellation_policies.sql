@@ -0,0 +1,34 @@
+-- Cancellation policies set by admins per provider or per service category, and the fees and penalties
+-- charged when bookings were cancelled. Fees are basis points of the booking's price (10000 is 100%).
+
+CREATE TABLE cancellation_policies (
+    id                       VARCHAR(36)  NOT NULL PRIMARY KEY,
+    scope                    VARCHAR(16)  NOT NULL,
+    -- the provider's user ID or the category's name
+    scope_id                 VARCHAR(255) NOT NULL,
+    free_window_hours        INT          NOT NULL,
+    late_fee_basis_points    INT          NOT NULL,
+    no_show_fee_basis_points INT          NOT NULL,
+    provider_late_penalty    INT          NOT NULL,
+    provider_no_show_penalty INT          NOT NULL,
+    updated_at               DATETIME     NOT NULL,
+    -- one policy per provider and per category
+    UNIQUE KEY uq_cancellation_policies_scope (scope, scope_id)
+);
+
+CREATE TABLE cancellation_charges (
+    id                 VARCHAR(36) NOT NULL PRIMARY KEY,
+    service_request_id VARCHAR(36) NOT NULL,
+    provider_id        VARCHAR(36) NOT NULL,
+    cancelled_by       VARCHAR(20) NOT NULL,
+    actor_id           VARCHAR(36) NOT NULL,
+    policy_id          VARCHAR(36) NOT NULL,
+    tier               VARCHAR(16) NOT NULL,
+    currency           CHAR(3)     NOT NULL,
+    fee_minor          BIGINT      NOT NULL DEFAULT 0,
+    penalty_points     INT         NOT NULL DEFAULT 0,
+    created_at         DATETIME    NOT NULL,
+    KEY idx_cancellation_charges_request (service_request_id),
+    KEY idx_cancellation_charges_provider (provider_id),
+    CONSTRAINT fk_cancellation_charges_request FOREIGN KEY (service_request_id) REFERENCES service_requests (id)
+);
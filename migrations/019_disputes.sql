@@ -0,0 +1,40 @@
+-- Disputes raised on requests by their householder or approved provider, and the notes and evidence the
+-- parties add while an admin reviews them. Refunded amounts are minor units in the payment's currency.
+
+CREATE TABLE disputes (
+    id                 VARCHAR(36) NOT NULL PRIMARY KEY,
+    service_request_id VARCHAR(36) NOT NULL,
+    householder_id     VARCHAR(36) NOT NULL,
+    provider_id        VARCHAR(36) NOT NULL,
+    opened_by          VARCHAR(36) NOT NULL,
+    opened_by_role     VARCHAR(20) NOT NULL,
+    reason             TEXT        NOT NULL,
+    status             VARCHAR(20) NOT NULL,
+    outcome            VARCHAR(20) NULL,
+    refunded_currency  CHAR(3)     NULL,
+    refunded_minor     BIGINT      NULL,
+    penalty_points     INT         NOT NULL DEFAULT 0,
+    resolution         TEXT        NULL,
+    resolved_by        VARCHAR(36) NULL,
+    created_at         DATETIME    NOT NULL,
+    updated_at         DATETIME    NOT NULL,
+    resolved_at        DATETIME    NULL,
+    -- Set while the dispute is unresolved, so a request has at most one unresolved dispute
+    open_request_id    VARCHAR(36) AS (IF(status = 'Resolved', NULL, service_request_id)) STORED,
+    UNIQUE KEY uq_disputes_open_request (open_request_id),
+    KEY idx_disputes_request (service_request_id),
+    KEY idx_disputes_status (status, created_at),
+    KEY idx_disputes_provider (provider_id),
+    CONSTRAINT fk_disputes_request FOREIGN KEY (service_request_id) REFERENCES service_requests (id)
+);
+
+CREATE TABLE dispute_notes (
+    id          VARCHAR(36) NOT NULL PRIMARY KEY,
+    dispute_id  VARCHAR(36) NOT NULL,
+    author_id   VARCHAR(36) NOT NULL,
+    author_role VARCHAR(20) NOT NULL,
+    note        TEXT        NOT NULL,
+    created_at  DATETIME    NOT NULL,
+    KEY idx_dispute_notes_dispute (dispute_id, created_at),
+    CONSTRAINT fk_dispute_notes_dispute FOREIGN KEY (dispute_id) REFERENCES disputes (id)
+);
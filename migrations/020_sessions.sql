@@ -0,0 +1,22 @@
+-- Login sessions and their rotating refresh tokens. Access tokens name their session, which is checked
+-- on every request, so revoking a session logs it out everywhere at once. Refresh tokens are stored as
+-- SHA-256 hashes.
+
+CREATE TABLE auth_sessions (
+    id            VARCHAR(36)  NOT NULL PRIMARY KEY,
+    user_id       VARCHAR(36)  NOT NULL,
+    created_at    DATETIME     NOT NULL,
+    revoked_at    DATETIME     NULL,
+    revoke_reason VARCHAR(255) NULL,
+    KEY idx_auth_sessions_user (user_id, revoked_at)
+);
+
+CREATE TABLE refresh_tokens (
+    token_hash CHAR(64)    NOT NULL PRIMARY KEY,
+    session_id VARCHAR(36) NOT NULL,
+    issued_at  DATETIME    NOT NULL,
+    expires_at DATETIME    NOT NULL,
+    used_at    DATETIME    NULL,
+    KEY idx_refresh_tokens_session (session_id),
+    CONSTRAINT fk_refresh_tokens_session FOREIGN KEY (session_id) REFERENCES auth_sessions (id)
+);
@@ -0,0 +1,22 @@
+-- Failed login, security answer and OTP attempts. auth_throttles counts recent failures per account and
+-- per IP address and holds their lockouts; auth_failures is the audit log of every failed attempt.
+
+CREATE TABLE auth_throttles (
+    subject         VARCHAR(16)  NOT NULL,
+    throttle_key    VARCHAR(255) NOT NULL,
+    failures        INT          NOT NULL,
+    last_failure_at DATETIME     NOT NULL,
+    locked_until    DATETIME     NULL,
+    PRIMARY KEY (subject, throttle_key)
+);
+
+CREATE TABLE auth_failures (
+    id         VARCHAR(36)  NOT NULL PRIMARY KEY,
+    action     VARCHAR(32)  NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    ip         VARCHAR(64)  NOT NULL,
+    reason     VARCHAR(255) NOT NULL,
+    created_at DATETIME     NOT NULL,
+    KEY idx_auth_failures_email (email, created_at),
+    KEY idx_auth_failures_created (created_at)
+);
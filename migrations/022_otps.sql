@@ -0,0 +1,14 @@
+-- One-time codes, moved out of process memory so they survive restarts and work across replicas. Codes
+-- are stored as HMAC-SHA256 hashes keyed by the OTP_SECRET setting, one per email and purpose; expired
+-- codes are swept periodically.
+
+CREATE TABLE otps (
+    email      VARCHAR(255) NOT NULL,
+    purpose    VARCHAR(32)  NOT NULL,
+    code_hash  CHAR(64)     NOT NULL,
+    attempts   INT          NOT NULL DEFAULT 0,
+    created_at DATETIME     NOT NULL,
+    expires_at DATETIME     NOT NULL,
+    PRIMARY KEY (email, purpose),
+    KEY idx_otps_expires (expires_at)
+);
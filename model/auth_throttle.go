@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// ThrottleSubject is what failed authentication attempts are counted against
+type ThrottleSubject string
+
+const (
+	ThrottleAccount ThrottleSubject = "account"
+	ThrottleIP      ThrottleSubject = "ip"
+)
+
+// AuthAction is the credential check an attempt failed
+type AuthAction string
+
+const (
+	AuthActionLogin          AuthAction = "login"
+	AuthActionSecurityAnswer AuthAction = "security_answer"
+	AuthActionOtp            AuthAction = "otp"
+)
+
+// AuthThrottle counts the recent failed attempts against an account or IP address. Once they pass the
+// threshold every further failure locks it out for twice as long as the one before.
+type AuthThrottle struct {
+	Subject       ThrottleSubject `json:"subject"`
+	Key           string          `json:"key"`
+	Failures      int             `json:"failures"`
+	LastFailureAt time.Time       `json:"last_failure_at"`
+	LockedUntil   *time.Time      `json:"locked_until,omitempty"`
+}
+
+// FailedAuthAttempt is an entry of the audit log of failed logins, security answers and OTPs
+type FailedAuthAttempt struct {
+	ID        string     `json:"id"`
+	Action    AuthAction `json:"action"`
+	Email     string     `json:"email"`
+	IP        string     `json:"ip"`
+	Reason    string     `json:"reason"`
+	CreatedAt time.Time  `json:"created_at"`
+}
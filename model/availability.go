@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// WorkingHours is one weekly working window of a provider. Weekday follows time.Weekday
+// (0 = Sunday) and Start/End are "15:04" clock times in the schedule timezone.
+type WorkingHours struct {
+	Weekday time.Weekday `json:"weekday"`
+	Start   string       `json:"start"`
+	End     string       `json:"end"`
+}
+
+// TimeOff blocks a provider's calendar between Start and End
+type TimeOff struct {
+	ID         string    `json:"id"`
+	ProviderID string    `json:"provider_id"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+// ProviderSchedule is everything needed to work out when a provider can take a job
+type ProviderSchedule struct {
+	ProviderID  string `json:"provider_id"`
+	Available   bool   `json:"availability"`
+	SlotMinutes int    `json:"slot_minutes"`
+	// Timezone is the IANA zone working hours are read in; empty means the default timezone
+	Timezone     string         `json:"timezone"`
+	WorkingHours []WorkingHours `json:"working_hours"`
+	TimeOff      []TimeOff      `json:"time_off"`
+}
+
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Overlaps reports whether the two half-open ranges share any instant
+func (r TimeRange) Overlaps(other TimeRange) bool {
+	return r.Start.Before(other.End) && other.Start.Before(r.End)
+}
+
+type Slot struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Available bool      `json:"available"`
+}
+
+// Booking is a job a provider has committed to, either by quoting on it or by being approved for it
+type Booking struct {
+	RequestID string    `json:"request_id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+// ScheduleConflict describes a booking that clashes with a new job. Overlap is true when the jobs
+// overlap outright and false when they only leave less than the travel buffer between them.
+type ScheduleConflict struct {
+	RequestID string    `json:"request_id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Overlap   bool      `json:"overlap"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+type RecurrenceFrequency string
+
+const (
+	FrequencyWeekly   RecurrenceFrequency = "weekly"
+	FrequencyBiweekly RecurrenceFrequency = "biweekly"
+	FrequencyMonthly  RecurrenceFrequency = "monthly"
+)
+
+// RecurrenceRule is an RRULE-style repeat rule. The series ends after Count occurrences or at
+// Until, whichever comes first; at least one of them must be set.
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Until     *time.Time          `json:"until,omitempty"`
+	Count     int                 `json:"count,omitempty"`
+}
+
+type SeriesStatus string
+
+const (
+	SeriesActive    SeriesStatus = "Active"
+	SeriesPaused    SeriesStatus = "Paused"
+	SeriesCancelled SeriesStatus = "Cancelled"
+	SeriesCompleted SeriesStatus = "Completed"
+)
+
+// BookingSeries is a recurring booking from which one service request per occurrence is
+// materialised ahead of time. Occurrences are numbered from 0 and keep the wall-clock time of
+// FirstTime in Timezone.
+type BookingSeries struct {
+	ID                 string             `json:"id"`
+	HouseholderID      string             `json:"householder_id"`
+	ServiceID          string             `json:"service_id"`
+	ServiceName        string             `json:"service_name"`
+	Description        string             `json:"description"`
+	Rule               RecurrenceRule     `json:"rule"`
+	FirstTime          time.Time          `json:"first_time"`
+	Timezone           string             `json:"timezone"`
+	Status             SeriesStatus       `json:"status"`
+	ProviderID         *string            `json:"provider_id,omitempty"`
+	ProviderPrice      *Money             `json:"provider_price,omitempty"`
+	QuoteID            string             `json:"quote_id,omitempty"`
+	GeneratedCount     int                `json:"generated_count"`
+	SkippedOccurrences []int              `json:"skipped_occurrences,omitempty"`
+	Upcoming           []SeriesOccurrence `json:"upcoming,omitempty"`
+	CreatedAt          time.Time          `json:"created_at"`
+}
+
+// SeriesOccurrence is one planned date of a booking series
+type SeriesOccurrence struct {
+	Occurrence int       `json:"occurrence"`
+	Start      time.Time `json:"start"`
+}
+
+// IsSkipped reports whether the occurrence was skipped by the householder
+func (s *BookingSeries) IsSkipped(occurrence int) bool {
+	for _, skipped := range s.SkippedOccurrences {
+		if skipped == occurrence {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+type PolicyScope string
+
+const (
+	// PolicyScopeProvider policies apply to one provider's bookings and win over category policies
+	PolicyScopeProvider PolicyScope = "provider"
+	// PolicyScopeCategory policies apply to the bookings of one service category
+	PolicyScopeCategory PolicyScope = "category"
+	// PolicyScopeDefault is the built-in policy used when no stored policy applies
+	PolicyScopeDefault PolicyScope = "default"
+)
+
+// CancellationPolicy decides what cancelling an approved booking costs. Cancelling at least
+// FreeWindowHours ahead is free; later the householder pays LateFeeBasisPoints of the price, and
+// NoShowFeeBasisPoints once the scheduled time has passed. Providers who cancel lose reliability points
+// on the same tiers.
+type CancellationPolicy struct {
+	ID                    string      `json:"id"`
+	Scope                 PolicyScope `json:"scope"`
+	ScopeID               string      `json:"scope_id"`
+	FreeWindowHours       int         `json:"free_window_hours"`
+	LateFeeBasisPoints    int         `json:"late_fee_basis_points"`
+	NoShowFeeBasisPoints  int         `json:"no_show_fee_basis_points"`
+	ProviderLatePenalty   int         `json:"provider_late_penalty"`
+	ProviderNoShowPenalty int         `json:"provider_no_show_penalty"`
+	UpdatedAt             time.Time   `json:"updated_at"`
+}
+
+type CancellationTier string
+
+const (
+	CancellationFree   CancellationTier = "free"
+	CancellationLate   CancellationTier = "late"
+	CancellationNoShow CancellationTier = "no_show"
+)
+
+// CancellationTerms is what cancelling a booking costs right now: a fee for householders, reliability
+// penalty points for providers. Requests not approved yet cancel for free under no policy.
+type CancellationTerms struct {
+	PolicyID      string           `json:"policy_id,omitempty"`
+	Scope         PolicyScope      `json:"scope,omitempty"`
+	Tier          CancellationTier `json:"tier"`
+	Fee           Money            `json:"fee"`
+	PenaltyPoints int              `json:"penalty_points,omitempty"`
+	FreeUntil     *time.Time       `json:"free_until,omitempty"`
+}
+
+// CancellationCharge records the fee or penalty applied when a booking was cancelled
+type CancellationCharge struct {
+	ID            string           `json:"id"`
+	RequestID     string           `json:"request_id"`
+	ProviderID    string           `json:"provider_id"`
+	CancelledBy   string           `json:"cancelled_by"`
+	ActorID       string           `json:"actor_id"`
+	PolicyID      string           `json:"policy_id"`
+	Tier          CancellationTier `json:"tier"`
+	Fee           Money            `json:"fee"`
+	PenaltyPoints int              `json:"penalty_points"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
@@ -0,0 +1,92 @@
+package model
+
+import "time"
+
+type DisputeStatus string
+
+const (
+	DisputeOpen        DisputeStatus = "Open"
+	DisputeUnderReview DisputeStatus = "UnderReview"
+	DisputeResolved    DisputeStatus = "Resolved"
+)
+
+// disputeTransitions lists, for every dispute status, the statuses it may move to next.
+// Statuses missing from the table are terminal.
+var disputeTransitions = map[DisputeStatus][]DisputeStatus{
+	DisputeOpen:        {DisputeUnderReview, DisputeResolved},
+	DisputeUnderReview: {DisputeResolved},
+}
+
+// CanTransitionTo reports whether a dispute in status s may move to next
+func (s DisputeStatus) CanTransitionTo(next DisputeStatus) bool {
+	for _, allowed := range disputeTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// DisputeOutcome is how an admin settled a dispute
+type DisputeOutcome string
+
+const (
+	// DisputeFullRefund refunds everything the householder paid for the request that was not refunded yet
+	DisputeFullRefund DisputeOutcome = "full_refund"
+	// DisputePartialRefund refunds the amount the admin decided on
+	DisputePartialRefund DisputeOutcome = "partial_refund"
+	// DisputeProviderPenalty takes reliability points off the provider without refunding anything
+	DisputeProviderPenalty DisputeOutcome = "provider_penalty"
+	// DisputeDismissed closes the dispute with no action
+	DisputeDismissed DisputeOutcome = "dismissed"
+)
+
+// IsValid reports whether o is a known outcome
+func (o DisputeOutcome) IsValid() bool {
+	switch o {
+	case DisputeFullRefund, DisputePartialRefund, DisputeProviderPenalty, DisputeDismissed:
+		return true
+	}
+	return false
+}
+
+// Dispute is a complaint about a request raised by its householder or approved provider and settled by
+// an admin. Penalty points count against the provider's reliability once the dispute is resolved.
+type Dispute struct {
+	ID            string         `json:"id"`
+	RequestID     string         `json:"request_id"`
+	HouseholderID string         `json:"householder_id"`
+	ProviderID    string         `json:"provider_id"`
+	OpenedBy      string         `json:"opened_by"`
+	OpenedByRole  string         `json:"opened_by_role"`
+	Reason        string         `json:"reason"`
+	Status        DisputeStatus  `json:"status"`
+	Outcome       DisputeOutcome `json:"outcome,omitempty"`
+	Refunded      *Money         `json:"refunded,omitempty"`
+	PenaltyPoints int            `json:"penalty_points,omitempty"`
+	Resolution    string         `json:"resolution,omitempty"`
+	ResolvedBy    string         `json:"resolved_by,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	ResolvedAt    *time.Time     `json:"resolved_at,omitempty"`
+	Notes         []DisputeNote  `json:"notes,omitempty"`
+}
+
+// DisputeNote is a statement or piece of evidence added to a dispute by one of its parties or an admin
+type DisputeNote struct {
+	ID         string    `json:"id"`
+	DisputeID  string    `json:"dispute_id"`
+	AuthorID   string    `json:"author_id"`
+	AuthorRole string    `json:"author_role"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// DisputeResolution is an admin's decision on a dispute. Amount is required for partial refunds and
+// penalty points for provider penalties; refunds may carry penalty points too.
+type DisputeResolution struct {
+	Outcome       DisputeOutcome `json:"outcome"`
+	Amount        *Money         `json:"amount,omitempty"`
+	PenaltyPoints int            `json:"penalty_points"`
+	Notes         string         `json:"notes"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Invoice is the provider's invoice, and the householder's receipt, for a completed request. It is a
+// snapshot taken when first issued and is numbered in an unbroken sequence per provider.
+type Invoice struct {
+	ID                 string          `json:"id"`
+	Number             string          `json:"number"`
+	Sequence           int64           `json:"sequence"`
+	RequestID          string          `json:"request_id"`
+	ProviderID         string          `json:"provider_id"`
+	HouseholderID      string          `json:"householder_id"`
+	HouseholderName    string          `json:"householder_name"`
+	HouseholderAddress string          `json:"householder_address,omitempty"`
+	ProviderName       string          `json:"provider_name"`
+	ProviderAddress    string          `json:"provider_address,omitempty"`
+	ProviderContact    string          `json:"provider_contact,omitempty"`
+	ServiceName        string          `json:"service_name"`
+	ScheduledTime      time.Time       `json:"scheduled_time"`
+	StartedAt          *time.Time      `json:"started_at,omitempty"`
+	CompletedAt        *time.Time      `json:"completed_at,omitempty"`
+	QuoteID            string          `json:"quote_id,omitempty"`
+	QuoteVersion       int             `json:"quote_version,omitempty"`
+	LineItems          []QuoteLineItem `json:"line_items"`
+	Taxable            Money           `json:"taxable"`
+	TaxLabel           string          `json:"tax_label"`
+	// TaxRate is in basis points; 1800 is 18%
+	TaxRate  int       `json:"tax_rate_basis_points"`
+	Tax      Money     `json:"tax"`
+	Total    Money     `json:"total"`
+	IssuedAt time.Time `json:"issued_at"`
+	// PaymentStatus is the status of the request's payment when the invoice is viewed; it is not stored
+	PaymentStatus PaymentStatus `json:"payment_status,omitempty"`
+}
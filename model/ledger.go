@@ -0,0 +1,126 @@
+package model
+
+import "time"
+
+// LedgerAccount is an account of the double-entry ledger that tracks what providers earn
+type LedgerAccount string
+
+const (
+	// AccountHouseholderReceipts is what householders pay for completed jobs, net of refunds
+	AccountHouseholderReceipts LedgerAccount = "householder_receipts"
+	// AccountProviderPayable is what the platform owes each provider; entries name the provider
+	AccountProviderPayable LedgerAccount = "provider_payable"
+	// AccountPlatformCommission is the platform's commission on jobs
+	AccountPlatformCommission LedgerAccount = "platform_commission"
+	// AccountPlatformBank is money paid out of the platform's bank account
+	AccountPlatformBank LedgerAccount = "platform_bank"
+)
+
+type JournalKind string
+
+const (
+	// JournalEarning splits the price of a completed job between the provider and the platform
+	JournalEarning JournalKind = "earning"
+	// JournalRefund takes a refund back from the provider and the platform in the same proportion
+	JournalRefund JournalKind = "refund"
+	// JournalPayout records money paid out to a provider in a payout batch
+	JournalPayout JournalKind = "payout"
+)
+
+// LedgerEntry is one side of a journal. Debits are positive amounts and credits negative ones, so the
+// entries of a journal add up to zero.
+type LedgerEntry struct {
+	Account    LedgerAccount `json:"account"`
+	ProviderID string        `json:"provider_id,omitempty"`
+	Amount     Money         `json:"amount"`
+}
+
+// LedgerJournal is one balanced posting to the ledger. Kind, Reference and ProviderID identify it, so
+// the same job, refund or payout is never posted twice.
+type LedgerJournal struct {
+	ID          string        `json:"id"`
+	Kind        JournalKind   `json:"kind"`
+	Reference   string        `json:"reference"`
+	ProviderID  string        `json:"provider_id"`
+	RequestID   string        `json:"request_id,omitempty"`
+	ServiceID   string        `json:"service_id,omitempty"`
+	ServiceName string        `json:"service_name,omitempty"`
+	Currency    string        `json:"currency"`
+	OccurredAt  time.Time     `json:"occurred_at"`
+	Entries     []LedgerEntry `json:"entries"`
+}
+
+// Balanced reports whether the journal's debits equal its credits and all entries are in its currency
+func (j *LedgerJournal) Balanced() bool {
+	var sum int64
+	for _, entry := range j.Entries {
+		if entry.Amount.Currency != j.Currency {
+			return false
+		}
+		sum += entry.Amount.Amount
+	}
+	return len(j.Entries) > 1 && sum == 0
+}
+
+// CompletedJob is a completed request whose earnings have not been posted to the ledger yet
+type CompletedJob struct {
+	RequestID   string
+	ProviderID  string
+	ServiceID   string
+	ServiceName string
+	Price       Money
+	CompletedAt time.Time
+}
+
+// EarningsTotals sums a provider's ledger activity in one currency
+type EarningsTotals struct {
+	Currency   string `json:"currency"`
+	Gross      Money  `json:"gross"`
+	Commission Money  `json:"commission"`
+	Refunds    Money  `json:"refunds"`
+	Net        Money  `json:"net"`
+	PaidOut    Money  `json:"paid_out"`
+}
+
+type ServiceEarnings struct {
+	ServiceID   string `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	EarningsTotals
+}
+
+type PeriodEarnings struct {
+	PeriodStart time.Time `json:"period_start"`
+	EarningsTotals
+}
+
+// EarningsSummary is a provider's earnings between From and To, broken down per service and per period
+type EarningsSummary struct {
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Period    string            `json:"period"`
+	Totals    []EarningsTotals  `json:"totals"`
+	ByService []ServiceEarnings `json:"by_service"`
+	ByPeriod  []PeriodEarnings  `json:"by_period"`
+	// Payable is what the platform owes the provider right now, whatever the period
+	Payable []Money `json:"payable"`
+}
+
+// ProviderPayout is one provider's line in a payout batch
+type ProviderPayout struct {
+	ProviderID string `json:"provider_id"`
+	Amount     Money  `json:"amount"`
+	JournalID  string `json:"journal_id"`
+}
+
+// PayoutBatch pays every provider the balance the platform owes them
+type PayoutBatch struct {
+	ID        string           `json:"id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Payouts   []ProviderPayout `json:"payouts"`
+}
+
+// ProviderBalance is what the platform owes a provider in one currency
+type ProviderBalance struct {
+	ProviderID string `json:"provider_id"`
+	Amount     Money  `json:"amount"`
+}
@@ -0,0 +1,174 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"serviceNest/errs"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is assumed for amounts that do not name a currency
+const DefaultCurrency = "INR"
+
+// currencyExponents lists the supported ISO 4217 currencies with their number of minor-unit digits
+var currencyExponents = map[string]int{
+	"INR": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"AED": 2,
+	"SGD": 2,
+	"AUD": 2,
+	"JPY": 0,
+}
+
+var decimalPattern = regexp.MustCompile(`^(-?)(\d{1,15})(?:\.(\d+))?$`)
+
+// Money is an exact amount in the minor units of an ISO 4217 currency; 49950 INR is ₹499.50.
+// Amounts in different currencies are never added or compared.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// ValidCurrency reports whether code is a supported ISO 4217 currency
+func ValidCurrency(code string) bool {
+	_, ok := currencyExponents[code]
+	return ok
+}
+
+// NewMoney builds an amount from minor units
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney reads a decimal amount such as "499.5" in the given currency. It rejects more decimal
+// places than the currency has, so no rounding ever happens.
+func ParseMoney(amount, currency string) (Money, error) {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, errors.New(errs.InvalidCurrency)
+	}
+	match := decimalPattern.FindStringSubmatch(strings.TrimSpace(amount))
+	if match == nil || len(match[3]) > exponent {
+		return Money{}, errors.New(errs.InvalidAmount)
+	}
+
+	digits := match[2] + match[3] + strings.Repeat("0", exponent-len(match[3]))
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, errors.New(errs.InvalidAmount)
+	}
+	if match[1] == "-" {
+		minor = -minor
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+// Decimal renders the amount in major units with the currency's number of decimals, e.g. "499.50"
+func (m Money) Decimal() string {
+	exponent := currencyExponents[m.Currency]
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exponent == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+	scale := int64(1)
+	for i := 0; i < exponent; i++ {
+		scale *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, exponent, amount%scale)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add returns the sum of two amounts in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, errors.New(errs.CurrencyMismatch)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of two amounts in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+type moneyJSON struct {
+	Amount      *json.Number `json:"amount"`
+	AmountMinor *int64       `json:"amount_minor"`
+	Currency    string       `json:"currency"`
+}
+
+// MarshalJSON encodes the amount both as exact minor units and as a decimal string, which clients can
+// display without going through a float
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount      string `json:"amount"`
+		AmountMinor int64  `json:"amount_minor"`
+		Currency    string `json:"currency"`
+	}{m.Decimal(), m.Amount, m.Currency})
+}
+
+// UnmarshalJSON accepts {"amount": "499.50", "currency": "INR"}, {"amount_minor": 49950, "currency": "INR"}
+// or, from clients that predate currencies, a bare number or numeric string in DefaultCurrency
+func (m *Money) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "null" {
+		return nil
+	}
+	if !strings.HasPrefix(trimmed, "{") {
+		var amount json.Number
+		if err := json.Unmarshal(data, &amount); err != nil {
+			return errors.New(errs.InvalidAmount)
+		}
+		parsed, err := ParseMoney(amount.String(), DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	var value moneyJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.New(errs.InvalidAmount)
+	}
+	if value.Currency == "" {
+		value.Currency = DefaultCurrency
+	}
+	switch {
+	case value.AmountMinor != nil:
+		if !ValidCurrency(value.Currency) {
+			return errors.New(errs.InvalidCurrency)
+		}
+		*m = Money{Amount: *value.AmountMinor, Currency: value.Currency}
+	case value.Amount != nil:
+		parsed, err := ParseMoney(value.Amount.String(), value.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	default:
+		*m = Money{Currency: value.Currency}
+	}
+	return nil
+}
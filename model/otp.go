@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// OtpPurpose scopes an OTP to the flow it was sent for, so a code mailed for one flow cannot be used in
+// another
+type OtpPurpose string
+
+const (
+	OtpPasswordReset     OtpPurpose = "password_reset"
+	OtpEmailVerification OtpPurpose = "email_verification"
+	OtpLogin             OtpPurpose = "login"
+)
+
+// IsValid reports whether p is a known OTP purpose
+func (p OtpPurpose) IsValid() bool {
+	switch p {
+	case OtpPasswordReset, OtpEmailVerification, OtpLogin:
+		return true
+	}
+	return false
+}
+
+// Otp is a one-time code sent to an email for one purpose. Only its hash is stored, and a new code for
+// the same email and purpose replaces the old one.
+type Otp struct {
+	Email     string
+	Purpose   OtpPurpose
+	CodeHash  string
+	Attempts  int
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
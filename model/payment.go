@@ -0,0 +1,92 @@
+package model
+
+import "time"
+
+type PaymentStatus string
+
+const (
+	PaymentPending           PaymentStatus = "Pending"
+	PaymentAuthorized        PaymentStatus = "Authorized"
+	PaymentCaptured          PaymentStatus = "Captured"
+	PaymentPartiallyRefunded PaymentStatus = "PartiallyRefunded"
+	PaymentRefunded          PaymentStatus = "Refunded"
+	PaymentFailed            PaymentStatus = "Failed"
+	// PaymentVoided is a payment whose authorization was released without charging anything
+	PaymentVoided PaymentStatus = "Voided"
+)
+
+// paymentTransitions lists, for every payment status, the statuses it may move to next.
+// Statuses missing from the table are terminal.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentPending:           {PaymentAuthorized, PaymentFailed, PaymentVoided},
+	PaymentAuthorized:        {PaymentCaptured, PaymentFailed, PaymentVoided},
+	PaymentCaptured:          {PaymentPartiallyRefunded, PaymentRefunded},
+	PaymentPartiallyRefunded: {PaymentPartiallyRefunded, PaymentRefunded},
+}
+
+// CanTransitionTo reports whether a payment in status s may move to next
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CaptureMode decides when an authorized payment is charged
+type CaptureMode string
+
+const (
+	// CaptureImmediate charges the householder as soon as the gateway authorizes the payment
+	CaptureImmediate CaptureMode = "immediate"
+	// CaptureOnCompletion holds the authorization until the householder confirms the job is done
+	CaptureOnCompletion CaptureMode = "on_completion"
+)
+
+// Payment is the householder's payment for an approved request, made through a payment gateway
+type Payment struct {
+	ID            string        `json:"id"`
+	RequestID     string        `json:"request_id"`
+	HouseholderID string        `json:"householder_id"`
+	ProviderID    string        `json:"provider_id"`
+	Amount        Money         `json:"amount"`
+	Refunded      Money         `json:"refunded"`
+	Status        PaymentStatus `json:"status"`
+	CaptureMode   CaptureMode   `json:"capture_mode"`
+	Gateway       string        `json:"gateway"`
+	IntentID      string        `json:"intent_id"`
+	// ClientSecret lets the householder's client complete the payment with the gateway. It is only
+	// returned when the payment is created and never stored.
+	ClientSecret string    `json:"client_secret,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// PaymentIntent is a gateway's record of an amount the householder is about to pay
+type PaymentIntent struct {
+	ID           string
+	ClientSecret string
+	Amount       Money
+}
+
+const (
+	WebhookPaymentAuthorized = "payment.authorized"
+	WebhookPaymentCaptured   = "payment.captured"
+	WebhookPaymentFailed     = "payment.failed"
+)
+
+// PaymentWebhookEvent is a verified callback from the payment gateway. Gateways may deliver the same
+// event more than once; its ID identifies repeats.
+type PaymentWebhookEvent struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	IntentID string `json:"intent_id"`
+}
+
+// CancelledPayment is a payment on a cancelled request that is still to be settled, with the cancellation
+// fee the householder owes. The fee is captured and the rest is released or refunded.
+type CancelledPayment struct {
+	Payment Payment
+	Fee     Money
+}
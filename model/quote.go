@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+type QuoteStatus string
+
+const (
+	QuoteOpen       QuoteStatus = "Open"
+	QuoteSuperseded QuoteStatus = "Superseded"
+	QuoteApproved   QuoteStatus = "Approved"
+	QuoteDeclined   QuoteStatus = "Declined"
+	QuoteExpired    QuoteStatus = "Expired"
+)
+
+type QuoteLineKind string
+
+const (
+	LineLabour   QuoteLineKind = "labour"
+	LineParts    QuoteLineKind = "parts"
+	LineVisitFee QuoteLineKind = "visit_fee"
+	LineOther    QuoteLineKind = "other"
+)
+
+// QuoteLineItem is one priced line of a quote; all lines of a quote share its currency
+type QuoteLineItem struct {
+	Kind        QuoteLineKind `json:"kind"`
+	Description string        `json:"description,omitempty"`
+	Amount      Money         `json:"amount"`
+}
+
+// Quote is one version in the price negotiation between a provider and the householder on a request.
+// Every counter-offer from either side is a new version; only the latest version can be open.
+type Quote struct {
+	ID         string          `json:"id"`
+	RequestID  string          `json:"request_id"`
+	ProviderID string          `json:"provider_id"`
+	Version    int             `json:"version"`
+	ProposedBy string          `json:"proposed_by"`
+	LineItems  []QuoteLineItem `json:"line_items"`
+	Total      Money           `json:"total"`
+	ValidUntil time.Time       `json:"valid_until"`
+	Status     QuoteStatus     `json:"status"`
+	Note       string          `json:"note,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
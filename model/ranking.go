@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type ProviderJobStats struct {
+	ApprovedJobs  int64 `json:"approved_jobs"`
+	CompletedJobs int64 `json:"completed_jobs"`
+	PenaltyPoints int64 `json:"penalty_points"`
+}
+
+// RankingSignals holds the data used to rank services beyond what is stored on the service row
+type RankingSignals struct {
+	LastReviewDates map[string]time.Time        // keyed by ServiceKey
+	ProviderJobs    map[string]ProviderJobStats // keyed by provider ID
+}
+
+// ServiceKey identifies one provider's offering of a service
+func ServiceKey(providerID, serviceID string) string {
+	return providerID + "|" + serviceID
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type ServiceRequestEvent struct {
+	ID        string        `json:"id" bson:"id"`
+	RequestID string        `json:"request_id" bson:"request_id"`
+	ActorID   string        `json:"actor_id" bson:"actor_id"`
+	ActorRole string        `json:"actor_role" bson:"actor_role"`
+	OldStatus RequestStatus `json:"old_status" bson:"old_status"`
+	NewStatus RequestStatus `json:"new_status" bson:"new_status"`
+	Reason    string        `json:"reason,omitempty" bson:"reason"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import "serviceNest/errs"
+
+type RequestStatus string
+
+const (
+	StatusPending    RequestStatus = "Pending"
+	StatusAccepted   RequestStatus = "Accepted"
+	StatusApproved   RequestStatus = "Approved"
+	StatusInProgress RequestStatus = "InProgress"
+	StatusCompleted  RequestStatus = "Completed"
+	StatusCancelled  RequestStatus = "Cancelled"
+	StatusDeclined   RequestStatus = "Declined"
+	StatusExpired    RequestStatus = "Expired"
+)
+
+// requestTransitions lists, for every status, the statuses a request may move to next.
+// Statuses missing from the table are terminal.
+var requestTransitions = map[RequestStatus][]RequestStatus{
+	StatusPending:    {StatusAccepted, StatusCancelled, StatusDeclined, StatusExpired},
+	StatusAccepted:   {StatusApproved, StatusCancelled, StatusExpired},
+	StatusApproved:   {StatusInProgress, StatusCancelled},
+	StatusInProgress: {StatusCompleted},
+}
+
+// CanTransitionTo reports whether a request in status s may move to next
+func (s RequestStatus) CanTransitionTo(next RequestStatus) bool {
+	for _, allowed := range requestTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no further transitions are possible from s
+func (s RequestStatus) IsTerminal() bool {
+	return len(requestTransitions[s]) == 0
+}
+
+// TransitionTo moves the request to next, or returns an *errs.StatusTransitionError
+// if the transition table does not allow it
+func (r *ServiceRequest) TransitionTo(next RequestStatus) error {
+	if !r.Status.CanTransitionTo(next) {
+		return &errs.StatusTransitionError{From: string(r.Status), To: string(next)}
+	}
+	r.Status = next
+	return nil
+}
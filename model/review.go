@@ -3,11 +3,19 @@ package model
 import "time"
 
 type Review struct {
-	ID            string    `json:"id" bson:"id"`
-	ServiceID     string    `json:"service_id" bson:"service_id"`
-	HouseholderID string    `json:"householder_id" bson:"householder_id"`
-	ProviderID    string    `json:"provider_id"`
-	Rating        float64   `json:"rating" bson:"rating"`
-	Comments      string    `json:"comments" bson:"comments"`
-	ReviewDate    time.Time `json:"review_date" bson:"review_date"`
+	ID            string       `json:"id" bson:"id"`
+	RequestID     string       `json:"request_id" bson:"request_id"`
+	ServiceID     string       `json:"service_id" bson:"service_id"`
+	HouseholderID string       `json:"householder_id" bson:"householder_id"`
+	ProviderID    string       `json:"provider_id"`
+	Rating        float64      `json:"rating" bson:"rating"`
+	Comments      string       `json:"comments" bson:"comments"`
+	ReviewDate    time.Time    `json:"review_date" bson:"review_date"`
+	UpdatedAt     *time.Time   `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	Reply         *ReviewReply `json:"reply,omitempty" bson:"reply,omitempty"`
+}
+
+type ReviewReply struct {
+	Comments  string    `json:"comments" bson:"comments"`
+	ReplyDate time.Time `json:"reply_date" bson:"reply_date"`
 }
@@ -0,0 +1,9 @@
+package model
+
+// ServiceSearchResult is a service matched by a free-text search, with the fields that matched
+// highlighted as snippets keyed by field name
+type ServiceSearchResult struct {
+	Service    Service           `json:"service"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
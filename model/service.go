@@ -4,7 +4,7 @@ type Service struct {
 	ID              string  `json:"id" bson:"id"`
 	Name            string  `json:"name" bson:"name"`
 	Description     string  `json:"description" bson:"description"`
-	Price           float64 `json:"price" bson:"price"`
+	Price           Money   `json:"price" bson:"price"`
 	ProviderID      string  `json:"provider_id" bson:"provider_id"`
 	Category        string  `json:"category" bson:"category"`
 	CategoryId      string  `json:"category_id" bson:"category_id"`
@@ -14,4 +14,6 @@ type Service struct {
 	ProviderContact string  `json:"provider_contact,omitempty" bson:"provider_contact"`
 	ProviderAddress string  `json:"provider_address,omitempty" bson:"provider_address"`
 	ProviderRating  float64 `json:"provider_rating,omitempty" bson:"provider_rating"`
+	// EstimatedMinutes is how long the provider expects a job to take; 0 means the default duration
+	EstimatedMinutes int `json:"estimated_minutes,omitempty" bson:"estimated_minutes"`
 }
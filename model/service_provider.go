@@ -7,4 +7,6 @@ type ServiceProvider struct {
 	Reviews         []*Review `json:"reviews" bson:"reviews"`
 	Availability    bool      `json:"availability" bson:"availability"`
 	IsActive        bool      `json:"is_active" bson:"is_active"`
+	ServiceRadiusKm float64   `json:"service_radius_km" bson:"service_radius_km"`
+	DistanceKm      *float64  `json:"distance_km,omitempty" bson:"-"`
 }
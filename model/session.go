@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Session is one login of a user. Every access token carries its session's ID, so revoking the session
+// locks out its access tokens at once and stops its refresh tokens from being exchanged.
+type Session struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokeReason string     `json:"revoke_reason,omitempty"`
+}
+
+// RefreshToken is a single-use token exchanged for a new access token and refresh token. Only its hash
+// is stored; a token presented after it was used means it leaked, and its session is revoked.
+type RefreshToken struct {
+	TokenHash string     `json:"-"`
+	SessionID string     `json:"session_id"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+// TokenPair is what a login or a refresh hands to the client
+type TokenPair struct {
+	AccessToken      string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
@@ -0,0 +1,42 @@
+package model
+
+// SigningKeyStatus says what a key of the token signing keyset is used for
+type SigningKeyStatus string
+
+const (
+	// SigningKeyActive signs every new token; a keyset has exactly one active key
+	SigningKeyActive SigningKeyStatus = "active"
+	// SigningKeyNext is published before it starts signing, so verifiers already know it once it does
+	SigningKeyNext SigningKeyStatus = "next"
+	// SigningKeyRetiring no longer signs but still verifies the tokens it signed until they expire
+	SigningKeyRetiring SigningKeyStatus = "retiring"
+)
+
+// SigningKeyConfig is one entry of the keyset file. Key files hold PEM keys; relative paths are read from
+// the directory of the keyset file. Retiring keys only need their public key.
+type SigningKeyConfig struct {
+	ID             string           `json:"kid"`
+	Algorithm      string           `json:"alg"` // RS256 or EdDSA
+	Status         SigningKeyStatus `json:"status"`
+	PrivateKeyFile string           `json:"private_key_file,omitempty"`
+	PublicKeyFile  string           `json:"public_key_file,omitempty"`
+}
+
+// JWK is the public half of a signing key as published in the JSON Web Key Set (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA keys
+	Modulus  string `json:"n,omitempty"`
+	Exponent string `json:"e,omitempty"`
+	// Ed25519 keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type AuthThrottleRepository struct {
+	db *sql.DB
+}
+
+// NewAuthThrottleRepository initializes a new AuthThrottleRepository with MySQL
+func NewAuthThrottleRepository(db *sql.DB) interfaces.AuthThrottleRepository {
+	return &AuthThrottleRepository{db: db}
+}
+
+var authThrottleColumns = []string{"subject", "throttle_key", "failures", "last_failure_at", "locked_until"}
+var failedAuthAttemptColumns = []string{"id", "action", "email", "ip", "reason", "created_at"}
+
+// GetThrottle returns the failure count of an account or IP address; one that never failed has a zero count
+func (repo *AuthThrottleRepository) GetThrottle(subject model.ThrottleSubject, key string) (*model.AuthThrottle, error) {
+	throttle, err := scanAuthThrottle(repo.db.QueryRow(config.SelectQuery("auth_throttles", "subject", "throttle_key", authThrottleColumns), subject, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return &model.AuthThrottle{Subject: subject, Key: key}, nil
+	}
+	return throttle, err
+}
+
+// RecordFailure adds a failed attempt to the count of an account or IP address and returns the new count.
+// The increment happens in the database, so concurrent attempts are all counted.
+func (repo *AuthThrottleRepository) RecordFailure(subject model.ThrottleSubject, key string, now, resetBefore time.Time) (throttle *model.AuthThrottle, err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if _, err = tx.Exec(config.RecordAuthFailureQuery(), subject, key, now.UTC(), resetBefore.UTC()); err != nil {
+		return nil, err
+	}
+	return scanAuthThrottle(tx.QueryRow(config.SelectQuery("auth_throttles", "subject", "throttle_key", authThrottleColumns), subject, key))
+}
+
+// CreditFailure takes one attempt back off the count of an account or IP address
+func (repo *AuthThrottleRepository) CreditFailure(subject model.ThrottleSubject, key string) error {
+	_, err := repo.db.Exec(config.CreditAuthFailureQuery(), subject, key)
+	return err
+}
+
+func (repo *AuthThrottleRepository) LockUntil(subject model.ThrottleSubject, key string, until time.Time) error {
+	_, err := repo.db.Exec(config.UpdateQuery("auth_throttles", "subject", "throttle_key", []string{"locked_until"}), until.UTC(), subject, key)
+	return err
+}
+
+// ClaimLock locks out an account or IP address until the given time and reports true, unless it is
+// already locked out at now
+func (repo *AuthThrottleRepository) ClaimLock(subject model.ThrottleSubject, key string, now, until time.Time) (bool, error) {
+	result, err := repo.db.Exec(config.ClaimAuthLockQuery(), until.UTC(), subject, key, now.UTC())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// ResetThrottle forgets the failures of an account or IP address
+func (repo *AuthThrottleRepository) ResetThrottle(subject model.ThrottleSubject, key string) error {
+	_, err := repo.db.Exec(config.DeleteQuery("auth_throttles", "subject", "throttle_key"), subject, key)
+	return err
+}
+
+func (repo *AuthThrottleRepository) SaveFailedAttempt(attempt *model.FailedAuthAttempt) error {
+	_, err := repo.db.Exec(config.InsertQuery("auth_failures", failedAuthAttemptColumns), attempt.ID, attempt.Action, attempt.Email,
+		attempt.IP, attempt.Reason, attempt.CreatedAt.UTC())
+	return err
+}
+
+// GetFailedAttempts returns the audit log of failed attempts, newest first; an empty email returns all of them
+func (repo *AuthThrottleRepository) GetFailedAttempts(email string, limit, offset int) ([]model.FailedAuthAttempt, error) {
+	var args []interface{}
+	if email != "" {
+		args = append(args, email)
+	}
+	rows, err := repo.db.Query(config.FailedAuthAttemptsQuery(failedAuthAttemptColumns, email != "", limit, offset), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []model.FailedAuthAttempt
+	for rows.Next() {
+		var attempt model.FailedAuthAttempt
+		var createdAt []uint8
+		if err := rows.Scan(&attempt.ID, &attempt.Action, &attempt.Email, &attempt.IP, &attempt.Reason, &createdAt); err != nil {
+			return nil, err
+		}
+		if attempt.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, attempt)
+	}
+	return attempts, rows.Err()
+}
+
+func scanAuthThrottle(row rowScanner) (*model.AuthThrottle, error) {
+	var throttle model.AuthThrottle
+	var lastFailureAt, lockedUntil []uint8
+	if err := row.Scan(&throttle.Subject, &throttle.Key, &throttle.Failures, &lastFailureAt, &lockedUntil); err != nil {
+		return nil, err
+	}
+	var err error
+	if throttle.LastFailureAt, err = util.ParseTime(lastFailureAt); err != nil {
+		return nil, err
+	}
+	if throttle.LockedUntil, err = util.ParseNullableTime(lockedUntil); err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
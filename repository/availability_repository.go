@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type AvailabilityRepository struct {
+	db *sql.DB
+}
+
+func NewAvailabilityRepository(db *sql.DB) interfaces.AvailabilityRepository {
+	return &AvailabilityRepository{db: db}
+}
+
+// GetSchedule loads the provider's availability flag, slot length, weekly working hours and any
+// time off that has not ended yet
+func (repo *AvailabilityRepository) GetSchedule(providerID string) (*model.ProviderSchedule, error) {
+	schedule := &model.ProviderSchedule{ProviderID: providerID, WorkingHours: []model.WorkingHours{}, TimeOff: []model.TimeOff{}}
+
+	var slotMinutes sql.NullInt64
+	err := repo.db.QueryRow(config.ProviderScheduleQuery(), providerID).Scan(&schedule.Available, &slotMinutes, &schedule.Timezone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.ProviderNotFound)
+		}
+		return nil, err
+	}
+	schedule.SlotMinutes = int(slotMinutes.Int64)
+
+	query := config.SelectQuery("provider_working_hours", "provider_id", "", []string{"weekday", "start_time", "end_time"})
+	rows, err := repo.db.Query(query, providerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var window model.WorkingHours
+		if err := rows.Scan(&window.Weekday, &window.Start, &window.End); err != nil {
+			return nil, err
+		}
+		schedule.WorkingHours = append(schedule.WorkingHours, window)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	timeOffRows, err := repo.db.Query(config.UpcomingTimeOffQuery(), providerID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer timeOffRows.Close()
+	for timeOffRows.Next() {
+		var timeOff model.TimeOff
+		var start, end []uint8
+		var reason sql.NullString
+		if err := timeOffRows.Scan(&timeOff.ID, &start, &end, &reason); err != nil {
+			return nil, err
+		}
+		if timeOff.Start, err = util.ParseTime(start); err != nil {
+			return nil, err
+		}
+		if timeOff.End, err = util.ParseTime(end); err != nil {
+			return nil, err
+		}
+		timeOff.ProviderID = providerID
+		timeOff.Reason = reason.String
+		schedule.TimeOff = append(schedule.TimeOff, timeOff)
+	}
+	return schedule, timeOffRows.Err()
+}
+
+// SaveSchedule replaces the provider's slot length, schedule timezone and weekly working hours in one
+// transaction; an empty timezone falls back to the provider's own
+func (repo *AvailabilityRepository) SaveSchedule(providerID string, slotMinutes int, timezone string, hours []model.WorkingHours) (err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	query := config.UpdateQuery("service_providers", "user_id", "", []string{"slot_minutes", "schedule_timezone"})
+	if _, err = tx.Exec(query, slotMinutes, nullableString(timezone), providerID); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(config.DeleteQuery("provider_working_hours", "provider_id", ""), providerID); err != nil {
+		return err
+	}
+	insert := config.InsertQuery("provider_working_hours", []string{"provider_id", "weekday", "start_time", "end_time"})
+	for _, window := range hours {
+		if _, err = tx.Exec(insert, providerID, int(window.Weekday), window.Start, window.End); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *AvailabilityRepository) AddTimeOff(timeOff *model.TimeOff) error {
+	query := config.InsertQuery("provider_time_off", []string{"id", "provider_id", "start_time", "end_time", "reason"})
+	_, err := repo.db.Exec(query, timeOff.ID, timeOff.ProviderID, timeOff.Start.UTC(), timeOff.End.UTC(), timeOff.Reason)
+	return err
+}
+
+func (repo *AvailabilityRepository) DeleteTimeOff(providerID, timeOffID string) error {
+	result, err := repo.db.Exec(config.DeleteQuery("provider_time_off", "id", "provider_id"), timeOffID, providerID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(errs.TimeOffNotFound)
+	}
+	return nil
+}
+
+// GetBookedRanges returns the time taken by every approved job of the provider starting in [from, to),
+// ignoring excludeRequestID. Jobs last their estimated duration, as in the conflict check.
+func (repo *AvailabilityRepository) GetBookedRanges(providerID string, from, to time.Time, excludeRequestID string) ([]model.TimeRange, error) {
+	rows, err := repo.db.Query(config.BookedTimesQuery(), providerID, from.UTC(), to.UTC(), excludeRequestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var booked []model.TimeRange
+	for rows.Next() {
+		var scheduled []uint8
+		var minutes int
+		if err := rows.Scan(&scheduled, &minutes); err != nil {
+			return nil, err
+		}
+		start, err := util.ParseTime(scheduled)
+		if err != nil {
+			return nil, err
+		}
+		booked = append(booked, model.TimeRange{Start: start, End: start.Add(util.JobDuration(minutes))})
+	}
+	return booked, rows.Err()
+}
+
+// GetProviderIDsByServiceID lists the active providers offering the service
+func (repo *AvailabilityRepository) GetProviderIDsByServiceID(serviceID string) ([]string, error) {
+	rows, err := repo.db.Query(config.ProviderIDsByServiceQuery(), serviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var providerIDs []string
+	for rows.Next() {
+		var providerID string
+		if err := rows.Scan(&providerID); err != nil {
+			return nil, err
+		}
+		providerIDs = append(providerIDs, providerID)
+	}
+	return providerIDs, rows.Err()
+}
+
+// GetEstimatedMinutes returns the provider's estimated duration for the service, 0 if none is set
+func (repo *AvailabilityRepository) GetEstimatedMinutes(providerID, serviceID string) (int, error) {
+	query := config.SelectQuery("services", "id", "provider_id", []string{"estimated_minutes"})
+	var minutes sql.NullInt64
+	err := repo.db.QueryRow(query, serviceID, providerID).Scan(&minutes)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return int(minutes.Int64), nil
+}
+
+// GetCommitments returns the provider's bookings starting in (from, to), ignoring excludeRequestID.
+// Bookings without an estimated duration last config.DEFAULT_JOB_MINUTES.
+func (repo *AvailabilityRepository) GetCommitments(providerID string, from, to time.Time, excludeRequestID string) ([]model.Booking, error) {
+	rows, err := repo.db.Query(config.ProviderCommitmentsQuery(), providerID, excludeRequestID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []model.Booking
+	for rows.Next() {
+		var booking model.Booking
+		var scheduled []uint8
+		var minutes int
+		if err := rows.Scan(&booking.RequestID, &scheduled, &minutes); err != nil {
+			return nil, err
+		}
+		if booking.Start, err = util.ParseTime(scheduled); err != nil {
+			return nil, err
+		}
+		booking.End = booking.Start.Add(util.JobDuration(minutes))
+		bookings = append(bookings, booking)
+	}
+	return bookings, rows.Err()
+}
+
+// WithProviderLock runs fn while holding a lock on the provider, so that concurrent accepts and
+// approvals for the same provider are checked for conflicts one at a time
+func (repo *AvailabilityRepository) WithProviderLock(providerID string, fn func() error) error {
+	var id string
+	err := repo.db.QueryRow(config.SelectQuery("service_providers", "user_id", "", []string{"user_id"}), providerID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New(errs.ProviderNotFound)
+		}
+		return err
+	}
+	return withNamedLock(repo.db, config.PROVIDER_LOCK_PREFIX+providerID, config.RECORD_LOCK_TIMEOUT_SECONDS, errs.RecordBusy, fn)
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/go-sql-driver/mysql"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+// duplicateEntryErrorNumber is the MySQL error raised when an insert breaks a unique key
+const duplicateEntryErrorNumber = 1062
+
+type BookingSeriesRepository struct {
+	db *sql.DB
+}
+
+// NewBookingSeriesRepository initializes a new BookingSeriesRepository with MySQL
+func NewBookingSeriesRepository(db *sql.DB) interfaces.BookingSeriesRepository {
+	return &BookingSeriesRepository{db: db}
+}
+
+var bookingSeriesColumns = []string{"id", "householder_id", "service_id", "service_name", "description", "frequency", "until", "occurrence_count", "first_time", "timezone", "status", "provider_id", "provider_price_minor", "provider_currency", "quote_id", "generated_count", "created_at"}
+
+func (repo *BookingSeriesRepository) SaveSeries(series *model.BookingSeries) error {
+	query := config.InsertQuery("booking_series", bookingSeriesColumns)
+
+	var until *time.Time
+	if series.Rule.Until != nil {
+		utc := series.Rule.Until.UTC()
+		until = &utc
+	}
+	_, err := repo.db.Exec(query, series.ID, series.HouseholderID, series.ServiceID, series.ServiceName, series.Description,
+		series.Rule.Frequency, until, series.Rule.Count, series.FirstTime.UTC(), series.Timezone, series.Status,
+		series.ProviderID, providerPriceAmount(series.ProviderPrice), providerPriceCurrency(series.ProviderPrice), nullableString(series.QuoteID), series.GeneratedCount, series.CreatedAt.UTC())
+	return err
+}
+
+// GetSeriesByID loads a booking series together with its skipped occurrences
+func (repo *BookingSeriesRepository) GetSeriesByID(seriesID string) (*model.BookingSeries, error) {
+	query := config.SelectQuery("booking_series", "id", "", bookingSeriesColumns)
+	series, err := scanSeries(repo.db.QueryRow(query, seriesID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.SeriesNotFound)
+		}
+		return nil, err
+	}
+	if series.SkippedOccurrences, err = repo.getSkippedOccurrences(seriesID); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (repo *BookingSeriesRepository) GetSeriesByHouseholderID(householderID string) ([]model.BookingSeries, error) {
+	query := config.SelectQuery("booking_series", "householder_id", "", bookingSeriesColumns) + " ORDER BY created_at DESC"
+	return repo.querySeries(query, householderID)
+}
+
+func (repo *BookingSeriesRepository) GetSeriesByStatus(status model.SeriesStatus) ([]model.BookingSeries, error) {
+	query := config.SelectQuery("booking_series", "status", "", bookingSeriesColumns)
+	return repo.querySeries(query, status)
+}
+
+// UpdateSeries saves the mutable state of a series: its status, adopted provider and quote and generation progress
+func (repo *BookingSeriesRepository) UpdateSeries(series *model.BookingSeries) error {
+	query := config.UpdateQuery("booking_series", "id", "", []string{"status", "provider_id", "provider_price_minor", "provider_currency", "quote_id", "generated_count"})
+	_, err := repo.db.Exec(query, series.Status, series.ProviderID, providerPriceAmount(series.ProviderPrice), providerPriceCurrency(series.ProviderPrice),
+		nullableString(series.QuoteID), series.GeneratedCount, series.ID)
+	return err
+}
+
+// UpdateSeriesProgress saves how many occurrences the generator has handled, completing the series once
+// its rule has run out. Only an active series is written, so a pause or cancellation made while the
+// generator ran is kept; it reports false if the series was no longer active.
+func (repo *BookingSeriesRepository) UpdateSeriesProgress(seriesID string, generatedCount int, completed bool) (bool, error) {
+	result, err := repo.db.Exec(config.UpdateSeriesProgressQuery(), generatedCount, completed, seriesID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (repo *BookingSeriesRepository) AddSkippedOccurrence(seriesID string, occurrence int) error {
+	query := config.InsertQuery("booking_series_skips", []string{"series_id", "occurrence"})
+	_, err := repo.db.Exec(query, seriesID, occurrence)
+	return err
+}
+
+// GetSeriesRequestID returns the request materialised for an occurrence, or "" if there is none yet
+func (repo *BookingSeriesRepository) GetSeriesRequestID(seriesID string, occurrence int) (string, error) {
+	query := config.SelectQuery("service_requests", "series_id", "series_occurrence", []string{"id"})
+	var requestID string
+	err := repo.db.QueryRow(query, seriesID, occurrence).Scan(&requestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return requestID, nil
+}
+
+func (repo *BookingSeriesRepository) GetSeriesRequestIDs(seriesID string, from time.Time) ([]string, error) {
+	rows, err := repo.db.Query(config.SeriesRequestIDsQuery(), seriesID, from.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requestIDs []string
+	for rows.Next() {
+		var requestID string
+		if err := rows.Scan(&requestID); err != nil {
+			return nil, err
+		}
+		requestIDs = append(requestIDs, requestID)
+	}
+	return requestIDs, rows.Err()
+}
+
+func (repo *BookingSeriesRepository) getSkippedOccurrences(seriesID string) ([]int, error) {
+	query := config.SelectQuery("booking_series_skips", "series_id", "", []string{"occurrence"}) + " ORDER BY occurrence"
+	rows, err := repo.db.Query(query, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var skipped []int
+	for rows.Next() {
+		var occurrence int
+		if err := rows.Scan(&occurrence); err != nil {
+			return nil, err
+		}
+		skipped = append(skipped, occurrence)
+	}
+	return skipped, rows.Err()
+}
+
+// querySeries runs a booking_series select and loads the skipped occurrences of every row
+func (repo *BookingSeriesRepository) querySeries(query string, args ...interface{}) ([]model.BookingSeries, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seriesList []model.BookingSeries
+	for rows.Next() {
+		series, err := scanSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		seriesList = append(seriesList, *series)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range seriesList {
+		if seriesList[i].SkippedOccurrences, err = repo.getSkippedOccurrences(seriesList[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return seriesList, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func scanSeries(row rowScanner) (*model.BookingSeries, error) {
+	var series model.BookingSeries
+	var until, firstTime, createdAt []uint8
+	var providerID, providerCurrency, quoteID sql.NullString
+	var providerPrice sql.NullInt64
+	err := row.Scan(&series.ID, &series.HouseholderID, &series.ServiceID, &series.ServiceName, &series.Description,
+		&series.Rule.Frequency, &until, &series.Rule.Count, &firstTime, &series.Timezone, &series.Status,
+		&providerID, &providerPrice, &providerCurrency, &quoteID, &series.GeneratedCount, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if series.Rule.Until, err = util.ParseNullableTime(until); err != nil {
+		return nil, err
+	}
+	if series.FirstTime, err = util.ParseTime(firstTime); err != nil {
+		return nil, err
+	}
+	if series.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if providerID.Valid {
+		series.ProviderID = &providerID.String
+	}
+	if providerPrice.Valid {
+		price := model.NewMoney(providerPrice.Int64, providerCurrency.String)
+		series.ProviderPrice = &price
+	}
+	series.QuoteID = quoteID.String
+	return &series, nil
+}
+
+// isDuplicateEntry reports whether err is MySQL refusing an insert that breaks a unique key
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == duplicateEntryErrorNumber
+}
+
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// providerPriceAmount and providerPriceCurrency split the series' optional agreed price into its columns
+func providerPriceAmount(price *model.Money) sql.NullInt64 {
+	if price == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: price.Amount, Valid: true}
+}
+
+func providerPriceCurrency(price *model.Money) sql.NullString {
+	if price == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: price.Currency, Valid: true}
+}
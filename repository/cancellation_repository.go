@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type CancellationRepository struct {
+	db *sql.DB
+}
+
+// NewCancellationRepository initializes a new CancellationRepository with MySQL
+func NewCancellationRepository(db *sql.DB) interfaces.CancellationRepository {
+	return &CancellationRepository{db: db}
+}
+
+var cancellationPolicyColumns = []string{"id", "scope", "scope_id", "free_window_hours", "late_fee_basis_points", "no_show_fee_basis_points", "provider_late_penalty", "provider_no_show_penalty", "updated_at"}
+
+func (repo *CancellationRepository) GetPolicy(scope model.PolicyScope, scopeID string) (*model.CancellationPolicy, error) {
+	query := config.SelectQuery("cancellation_policies", "scope", "scope_id", cancellationPolicyColumns)
+	policy, err := scanCancellationPolicy(repo.db.QueryRow(query, scope, scopeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.CancellationPolicyNotFound)
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetPolicies lists every stored policy, category policies first
+func (repo *CancellationRepository) GetPolicies() ([]model.CancellationPolicy, error) {
+	query := config.SelectQuery("cancellation_policies", "", "", cancellationPolicyColumns) + " ORDER BY scope, scope_id"
+	rows, err := repo.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []model.CancellationPolicy
+	for rows.Next() {
+		policy, err := scanCancellationPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *policy)
+	}
+	return policies, rows.Err()
+}
+
+// SavePolicy stores a policy; a policy already set for the same scope is overwritten and keeps its ID
+func (repo *CancellationRepository) SavePolicy(policy *model.CancellationPolicy) error {
+	_, err := repo.db.Exec(config.SaveCancellationPolicyQuery(), policy.ID, policy.Scope, policy.ScopeID, policy.FreeWindowHours,
+		policy.LateFeeBasisPoints, policy.NoShowFeeBasisPoints, policy.ProviderLatePenalty, policy.ProviderNoShowPenalty,
+		policy.UpdatedAt.UTC())
+	return err
+}
+
+func (repo *CancellationRepository) DeletePolicy(policyID string) error {
+	result, err := repo.db.Exec(config.DeleteQuery("cancellation_policies", "id", ""), policyID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New(errs.CancellationPolicyNotFound)
+	}
+	return nil
+}
+
+// SaveCancellation cancels a request that still has the status it was read with, together with the
+// charge the cancellation costs (nil if free) and its history entry. It reports false, writing nothing,
+// if the request moved on in the meantime.
+func (repo *CancellationRepository) SaveCancellation(request *model.ServiceRequest, fromStatus model.RequestStatus, charge *model.CancellationCharge, event *model.ServiceRequestEvent) (cancelled bool, err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil || !cancelled {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	result, err := tx.Exec(config.UpdateQuery("service_requests", "id", "status", []string{"status"}), request.Status, request.ID, fromStatus)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+	if charge != nil {
+		if err = insertCancellationCharge(tx, charge); err != nil {
+			return false, err
+		}
+	}
+	if err = insertRequestEvent(tx, event); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func insertCancellationCharge(db execer, charge *model.CancellationCharge) error {
+	query := config.InsertQuery("cancellation_charges", []string{"id", "service_request_id", "provider_id", "cancelled_by", "actor_id", "policy_id", "tier", "currency", "fee_minor", "penalty_points", "created_at"})
+	_, err := db.Exec(query, charge.ID, charge.RequestID, charge.ProviderID, charge.CancelledBy, charge.ActorID, charge.PolicyID,
+		charge.Tier, charge.Fee.Currency, charge.Fee.Amount, charge.PenaltyPoints, charge.CreatedAt.UTC())
+	return err
+}
+
+func scanCancellationPolicy(row rowScanner) (*model.CancellationPolicy, error) {
+	var policy model.CancellationPolicy
+	var updatedAt []uint8
+	err := row.Scan(&policy.ID, &policy.Scope, &policy.ScopeID, &policy.FreeWindowHours, &policy.LateFeeBasisPoints,
+		&policy.NoShowFeeBasisPoints, &policy.ProviderLatePenalty, &policy.ProviderNoShowPenalty, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if policy.UpdatedAt, err = util.ParseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
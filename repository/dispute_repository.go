@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type DisputeRepository struct {
+	db *sql.DB
+}
+
+// NewDisputeRepository initializes a new DisputeRepository with MySQL
+func NewDisputeRepository(db *sql.DB) interfaces.DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+var disputeColumns = []string{"id", "service_request_id", "householder_id", "provider_id", "opened_by", "opened_by_role", "reason", "status", "outcome", "refunded_currency", "refunded_minor", "penalty_points", "resolution", "resolved_by", "created_at", "updated_at", "resolved_at"}
+
+func (repo *DisputeRepository) SaveDispute(dispute *model.Dispute) error {
+	query := config.InsertQuery("disputes", disputeColumns)
+	currency, amount := disputeRefundColumns(dispute.Refunded)
+	_, err := repo.db.Exec(query, dispute.ID, dispute.RequestID, dispute.HouseholderID, dispute.ProviderID, dispute.OpenedBy,
+		dispute.OpenedByRole, dispute.Reason, dispute.Status, nullableString(string(dispute.Outcome)), currency, amount,
+		dispute.PenaltyPoints, nullableString(dispute.Resolution), nullableString(dispute.ResolvedBy), dispute.CreatedAt.UTC(),
+		dispute.UpdatedAt.UTC(), dispute.ResolvedAt)
+	if isDuplicateEntry(err) {
+		return errors.New(errs.DisputeAlreadyOpen)
+	}
+	return err
+}
+
+func (repo *DisputeRepository) GetDisputeByID(disputeID string) (*model.Dispute, error) {
+	query := config.SelectQuery("disputes", "id", "", disputeColumns)
+	dispute, err := scanDispute(repo.db.QueryRow(query, disputeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.DisputeNotFound)
+		}
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// GetDisputesByRequestID lists the disputes raised on a request, oldest first
+func (repo *DisputeRepository) GetDisputesByRequestID(requestID string) ([]model.Dispute, error) {
+	query := config.SelectQuery("disputes", "service_request_id", "", disputeColumns) + " ORDER BY created_at"
+	return repo.getDisputes(query, requestID)
+}
+
+// GetDisputesByStatus lists the disputes in any of the given statuses, oldest first
+func (repo *DisputeRepository) GetDisputesByStatus(statuses []model.DisputeStatus, limit, offset int) ([]model.Dispute, error) {
+	args := make([]interface{}, len(statuses))
+	for i, status := range statuses {
+		args[i] = status
+	}
+	return repo.getDisputes(config.DisputesByStatusQuery(disputeColumns, len(statuses), limit, offset), args...)
+}
+
+// UpdateDispute stores the status and resolution of a dispute, provided it is still in fromStatus. It
+// reports whether the dispute was updated, so two admins cannot settle the same dispute twice.
+func (repo *DisputeRepository) UpdateDispute(dispute *model.Dispute, fromStatus model.DisputeStatus) (bool, error) {
+	query := config.UpdateQuery("disputes", "id", "status", []string{"status", "outcome", "refunded_currency", "refunded_minor", "penalty_points", "resolution", "resolved_by", "updated_at", "resolved_at"})
+	currency, amount := disputeRefundColumns(dispute.Refunded)
+	result, err := repo.db.Exec(query, dispute.Status, nullableString(string(dispute.Outcome)), currency, amount, dispute.PenaltyPoints,
+		nullableString(dispute.Resolution), nullableString(dispute.ResolvedBy), dispute.UpdatedAt.UTC(), dispute.ResolvedAt,
+		dispute.ID, fromStatus)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (repo *DisputeRepository) SaveNote(note *model.DisputeNote) error {
+	query := config.InsertQuery("dispute_notes", []string{"id", "dispute_id", "author_id", "author_role", "note", "created_at"})
+	_, err := repo.db.Exec(query, note.ID, note.DisputeID, note.AuthorID, note.AuthorRole, note.Note, note.CreatedAt.UTC())
+	return err
+}
+
+// GetNotes lists the notes on a dispute in the order they were added
+func (repo *DisputeRepository) GetNotes(disputeID string) ([]model.DisputeNote, error) {
+	query := config.SelectQuery("dispute_notes", "dispute_id", "", []string{"id", "dispute_id", "author_id", "author_role", "note", "created_at"}) + " ORDER BY created_at, id"
+	rows, err := repo.db.Query(query, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []model.DisputeNote
+	for rows.Next() {
+		var note model.DisputeNote
+		var createdAt []uint8
+		if err := rows.Scan(&note.ID, &note.DisputeID, &note.AuthorID, &note.AuthorRole, &note.Note, &createdAt); err != nil {
+			return nil, err
+		}
+		if note.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+func (repo *DisputeRepository) getDisputes(query string, args ...interface{}) ([]model.Dispute, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disputes []model.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, *dispute)
+	}
+	return disputes, rows.Err()
+}
+
+func scanDispute(row rowScanner) (*model.Dispute, error) {
+	var dispute model.Dispute
+	var outcome, currency, resolution, resolvedBy sql.NullString
+	var amount sql.NullInt64
+	var createdAt, updatedAt, resolvedAt []uint8
+	err := row.Scan(&dispute.ID, &dispute.RequestID, &dispute.HouseholderID, &dispute.ProviderID, &dispute.OpenedBy,
+		&dispute.OpenedByRole, &dispute.Reason, &dispute.Status, &outcome, &currency, &amount, &dispute.PenaltyPoints,
+		&resolution, &resolvedBy, &createdAt, &updatedAt, &resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	dispute.Outcome = model.DisputeOutcome(outcome.String)
+	dispute.Resolution = resolution.String
+	dispute.ResolvedBy = resolvedBy.String
+	if currency.Valid && amount.Valid {
+		refunded := model.NewMoney(amount.Int64, currency.String)
+		dispute.Refunded = &refunded
+	}
+	if dispute.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if dispute.UpdatedAt, err = util.ParseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	if dispute.ResolvedAt, err = util.ParseNullableTime(resolvedAt); err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// disputeRefundColumns splits the refunded amount into its nullable currency and amount columns
+func disputeRefundColumns(refunded *model.Money) (sql.NullString, sql.NullInt64) {
+	if refunded == nil {
+		return sql.NullString{}, sql.NullInt64{}
+	}
+	return sql.NullString{String: refunded.Currency, Valid: true}, sql.NullInt64{Int64: refunded.Amount, Valid: true}
+}
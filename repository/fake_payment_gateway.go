@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"serviceNest/util"
+	"sync"
+)
+
+// fakeIntent is the in-process state of one payment intent
+type fakeIntent struct {
+	amount     model.Money
+	authorized bool
+	voided     bool
+	captured   model.Money
+	refunded   model.Money
+}
+
+// FakePaymentGateway is an in-process PaymentGateway for tests and local development. Nothing is charged;
+// Authorize and Fail stand in for the householder completing the payment and produce the signed webhook
+// the real gateway would send.
+type FakePaymentGateway struct {
+	secret  []byte
+	mu      sync.Mutex
+	intents map[string]*fakeIntent
+}
+
+// NewFakePaymentGateway creates a fake gateway that signs its webhooks with secret
+func NewFakePaymentGateway(secret string) *FakePaymentGateway {
+	return &FakePaymentGateway{secret: []byte(secret), intents: make(map[string]*fakeIntent)}
+}
+
+func (g *FakePaymentGateway) Name() string {
+	return "fake"
+}
+
+func (g *FakePaymentGateway) CreateIntent(amount model.Money, reference string) (*model.PaymentIntent, error) {
+	if amount.Amount <= 0 || !model.ValidCurrency(amount.Currency) {
+		return nil, errors.New(errs.InvalidAmount)
+	}
+	intentID := "pi_" + util.GenerateUUID()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.intents[intentID] = &fakeIntent{amount: amount, captured: model.NewMoney(0, amount.Currency), refunded: model.NewMoney(0, amount.Currency)}
+	return &model.PaymentIntent{ID: intentID, ClientSecret: intentID + "_secret_" + reference, Amount: amount}, nil
+}
+
+// Capture charges an authorized intent, at most up to the authorized amount
+func (g *FakePaymentGateway) Capture(intentID string, amount model.Money) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return errors.New(errs.PaymentIntentNotFound)
+	}
+	if !intent.authorized || intent.voided || !intent.captured.IsZero() {
+		return errors.New(errs.PaymentNotCapturable)
+	}
+	if amount.Currency != intent.amount.Currency {
+		return errors.New(errs.CurrencyMismatch)
+	}
+	if amount.Amount <= 0 || amount.Amount > intent.amount.Amount {
+		return errors.New(errs.InvalidAmount)
+	}
+	intent.captured = amount
+	return nil
+}
+
+// Refund returns part or all of the captured amount
+func (g *FakePaymentGateway) Refund(intentID string, amount model.Money) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return "", errors.New(errs.PaymentIntentNotFound)
+	}
+	if intent.captured.IsZero() {
+		return "", errors.New(errs.PaymentNotRefundable)
+	}
+	refunded, err := intent.refunded.Add(amount)
+	if err != nil {
+		return "", err
+	}
+	if amount.Amount <= 0 || refunded.Amount > intent.captured.Amount {
+		return "", errors.New(errs.RefundExceedsPayment)
+	}
+	intent.refunded = refunded
+	return "re_" + util.GenerateUUID(), nil
+}
+
+// Void releases an intent that was not captured, so the householder is not charged
+func (g *FakePaymentGateway) Void(intentID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	intent, ok := g.intents[intentID]
+	if !ok {
+		return errors.New(errs.PaymentIntentNotFound)
+	}
+	if !intent.captured.IsZero() {
+		return errors.New(errs.PaymentNotVoidable)
+	}
+	intent.voided = true
+	return nil
+}
+
+// VerifyWebhook checks the hex HMAC-SHA256 signature of the payload and decodes the event. Without a
+// secret anyone could produce the signature, so every webhook is refused.
+func (g *FakePaymentGateway) VerifyWebhook(payload []byte, signature string) (*model.PaymentWebhookEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(g.secret) == 0 || !hmac.Equal(expected, g.sign(payload)) {
+		return nil, errors.New(errs.InvalidWebhookSignature)
+	}
+	var event model.PaymentWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" || event.IntentID == "" {
+		return nil, errors.New(errs.InvalidWebhookEvent)
+	}
+	return &event, nil
+}
+
+// Authorize marks the intent as paid by the householder and returns the signed payment.authorized webhook
+func (g *FakePaymentGateway) Authorize(intentID string) (payload []byte, signature string, err error) {
+	g.mu.Lock()
+	intent, ok := g.intents[intentID]
+	if ok {
+		intent.authorized = true
+	}
+	g.mu.Unlock()
+	if !ok {
+		return nil, "", errors.New(errs.PaymentIntentNotFound)
+	}
+	return g.SignedEvent(model.WebhookPaymentAuthorized, intentID)
+}
+
+// Fail returns the signed payment.failed webhook for a declined payment
+func (g *FakePaymentGateway) Fail(intentID string) (payload []byte, signature string, err error) {
+	g.mu.Lock()
+	_, ok := g.intents[intentID]
+	g.mu.Unlock()
+	if !ok {
+		return nil, "", errors.New(errs.PaymentIntentNotFound)
+	}
+	return g.SignedEvent(model.WebhookPaymentFailed, intentID)
+}
+
+// SignedEvent builds a new webhook event of the given type and signs it
+func (g *FakePaymentGateway) SignedEvent(eventType, intentID string) ([]byte, string, error) {
+	payload, err := json.Marshal(model.PaymentWebhookEvent{ID: "evt_" + util.GenerateUUID(), Type: eventType, IntentID: intentID})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, hex.EncodeToString(g.sign(payload)), nil
+}
+
+func (g *FakePaymentGateway) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -5,6 +5,7 @@ import (
 	"serviceNest/config"
 	"serviceNest/interfaces"
 	"serviceNest/model"
+	"serviceNest/util"
 )
 
 type MySQLHouseholderRepository struct {
@@ -26,15 +27,20 @@ func (repo *MySQLHouseholderRepository) SaveHouseholder(householder *model.House
 }
 
 func (repo *MySQLHouseholderRepository) GetHouseholderByID(id string) (*model.Householder, error) {
-	column := []string{"id", "name", "email", "password", "role", "address", "contact"}
+	column := []string{"id", "name", "email", "password", "role", "address", "contact", "pincode", "latitude", "longitude"}
 	query := config.SelectQuery("users", "id", "", column)
 	row := repo.db.QueryRow(query, id)
 
 	var householder model.Householder
-	err := row.Scan(&householder.ID, &householder.Name, &householder.Email, &householder.Password, &householder.Role, &householder.Address, &householder.Contact)
+	var pincode sql.NullString
+	var latitude, longitude sql.NullFloat64
+	err := row.Scan(&householder.ID, &householder.Name, &householder.Email, &householder.Password, &householder.Role, &householder.Address, &householder.Contact, &pincode, &latitude, &longitude)
 	if err != nil {
 		return nil, err
 	}
+	householder.Pincode = pincode.String
+	householder.Latitude = util.NullableFloat(latitude)
+	householder.Longitude = util.NullableFloat(longitude)
 
 	return &householder, nil
 }
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+// InMemoryServiceSearcher searches a fixed set of services held in memory. It applies the same
+// scoring as the MySQL searcher and is meant for tests and local runs without a database.
+type InMemoryServiceSearcher struct {
+	services []model.Service
+}
+
+func NewInMemoryServiceSearcher(services []model.Service) interfaces.ServiceSearcher {
+	return &InMemoryServiceSearcher{services: services}
+}
+
+func (s *InMemoryServiceSearcher) Search(query string, limit, offset int) ([]model.ServiceSearchResult, error) {
+	return util.ApplyPagination(util.ScoreServices(query, s.services), limit, offset), nil
+}
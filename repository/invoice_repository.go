@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type InvoiceRepository struct {
+	db *sql.DB
+}
+
+// NewInvoiceRepository initializes a new InvoiceRepository with MySQL
+func NewInvoiceRepository(db *sql.DB) interfaces.InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+var invoiceColumns = []string{"id", "number", "sequence", "service_request_id", "provider_id", "householder_id", "householder_name",
+	"householder_address", "provider_name", "provider_address", "provider_contact", "service_name", "scheduled_time", "started_at",
+	"completed_at", "quote_id", "quote_version", "currency", "taxable", "tax_label", "tax_rate", "tax", "total", "issued_at"}
+
+// SaveInvoice numbers the invoice with the provider's next sequence number and stores it with its line
+// items. Numbering and storing happen in one transaction, so a failed save leaves no gap.
+func (repo *InvoiceRepository) SaveInvoice(invoice *model.Invoice) (err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	if _, err = tx.Exec(config.NextInvoiceSequenceQuery(), invoice.ProviderID); err != nil {
+		return err
+	}
+	query := config.SelectQuery("invoice_sequences", "provider_id", "", []string{"last_number"})
+	if err = tx.QueryRow(query, invoice.ProviderID).Scan(&invoice.Sequence); err != nil {
+		return err
+	}
+	invoice.Number = util.InvoiceNumber(invoice.ProviderID, invoice.Sequence)
+
+	insert := config.InsertQuery("invoices", invoiceColumns)
+	_, err = tx.Exec(insert, invoice.ID, invoice.Number, invoice.Sequence, invoice.RequestID, invoice.ProviderID, invoice.HouseholderID,
+		invoice.HouseholderName, invoice.HouseholderAddress, invoice.ProviderName, invoice.ProviderAddress, invoice.ProviderContact,
+		invoice.ServiceName, invoice.ScheduledTime.UTC(), invoice.StartedAt, invoice.CompletedAt, nullableString(invoice.QuoteID),
+		invoice.QuoteVersion, invoice.Total.Currency, invoice.Taxable.Amount, invoice.TaxLabel, invoice.TaxRate, invoice.Tax.Amount,
+		invoice.Total.Amount, invoice.IssuedAt.UTC())
+	if err != nil {
+		return err
+	}
+	insert = config.InsertQuery("invoice_line_items", []string{"invoice_id", "position", "kind", "description", "amount"})
+	for i, item := range invoice.LineItems {
+		if _, err = tx.Exec(insert, invoice.ID, i, item.Kind, item.Description, item.Amount.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *InvoiceRepository) GetInvoiceByRequestID(requestID string) (*model.Invoice, error) {
+	query := config.SelectQuery("invoices", "service_request_id", "", invoiceColumns)
+
+	var invoice model.Invoice
+	var quoteID sql.NullString
+	var scheduledTime, startedAt, completedAt, issuedAt []uint8
+	err := repo.db.QueryRow(query, requestID).Scan(&invoice.ID, &invoice.Number, &invoice.Sequence, &invoice.RequestID,
+		&invoice.ProviderID, &invoice.HouseholderID, &invoice.HouseholderName, &invoice.HouseholderAddress, &invoice.ProviderName,
+		&invoice.ProviderAddress, &invoice.ProviderContact, &invoice.ServiceName, &scheduledTime, &startedAt, &completedAt, &quoteID,
+		&invoice.QuoteVersion, &invoice.Total.Currency, &invoice.Taxable.Amount, &invoice.TaxLabel, &invoice.TaxRate, &invoice.Tax.Amount,
+		&invoice.Total.Amount, &issuedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.InvoiceNotFound)
+		}
+		return nil, err
+	}
+	invoice.QuoteID = quoteID.String
+	invoice.Taxable.Currency = invoice.Total.Currency
+	invoice.Tax.Currency = invoice.Total.Currency
+	if invoice.ScheduledTime, err = util.ParseTime(scheduledTime); err != nil {
+		return nil, err
+	}
+	if invoice.StartedAt, err = util.ParseNullableTime(startedAt); err != nil {
+		return nil, err
+	}
+	if invoice.CompletedAt, err = util.ParseNullableTime(completedAt); err != nil {
+		return nil, err
+	}
+	if invoice.IssuedAt, err = util.ParseTime(issuedAt); err != nil {
+		return nil, err
+	}
+	if invoice.LineItems, err = repo.getLineItems(invoice.ID, invoice.Total.Currency); err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// getLineItems loads the line items of an invoice; their amounts are stored in the invoice's currency
+func (repo *InvoiceRepository) getLineItems(invoiceID, currency string) ([]model.QuoteLineItem, error) {
+	query := config.SelectQuery("invoice_line_items", "invoice_id", "", []string{"kind", "description", "amount"}) + " ORDER BY position"
+	rows, err := repo.db.Query(query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.QuoteLineItem
+	for rows.Next() {
+		var item model.QuoteLineItem
+		var amount int64
+		if err := rows.Scan(&item.Kind, &item.Description, &amount); err != nil {
+			return nil, err
+		}
+		item.Amount = model.NewMoney(amount, currency)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type LedgerRepository struct {
+	db *sql.DB
+}
+
+// NewLedgerRepository initializes a new LedgerRepository with MySQL
+func NewLedgerRepository(db *sql.DB) interfaces.LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// SaveJournal posts a balanced journal with its entries in one transaction. It reports false, and posts
+// nothing, when a journal of the same kind and reference was already posted for the provider in its currency.
+func (repo *LedgerRepository) SaveJournal(journal *model.LedgerJournal) (posted bool, err error) {
+	if !journal.Balanced() {
+		return false, errors.New(errs.UnbalancedJournal)
+	}
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+	return saveJournal(tx, journal)
+}
+
+// SaveJournals posts all of the journals or none of them. A journal that was already posted fails the
+// whole set.
+func (repo *LedgerRepository) SaveJournals(journals []*model.LedgerJournal) (err error) {
+	for _, journal := range journals {
+		if !journal.Balanced() {
+			return errors.New(errs.UnbalancedJournal)
+		}
+	}
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	for _, journal := range journals {
+		posted, err := saveJournal(tx, journal)
+		if err != nil {
+			return err
+		}
+		if !posted {
+			return errors.New(errs.JournalAlreadyPosted)
+		}
+	}
+	return nil
+}
+
+// WithPayoutLock runs fn while holding the database-wide payout lock, so that two payout batches never
+// read and pay out the same balances
+func (repo *LedgerRepository) WithPayoutLock(fn func() error) error {
+	return withNamedLock(repo.db, config.PAYOUT_LOCK_NAME, config.PAYOUT_LOCK_TIMEOUT_SECONDS, errs.PayoutInProgress, fn)
+}
+
+// saveJournal inserts a journal and its entries in tx, reporting false when it was already posted
+func saveJournal(tx *sql.Tx, journal *model.LedgerJournal) (bool, error) {
+	result, err := tx.Exec(config.InsertLedgerJournalQuery(), journal.ID, journal.Kind, journal.Reference, journal.ProviderID,
+		nullableString(journal.RequestID), nullableString(journal.ServiceID), journal.ServiceName, journal.Currency,
+		journal.OccurredAt.UTC(), time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil || inserted == 0 {
+		return false, err
+	}
+
+	insert := config.InsertQuery("ledger_entries", []string{"journal_id", "position", "account", "provider_id", "amount_minor"})
+	for i, entry := range journal.Entries {
+		if _, err := tx.Exec(insert, journal.ID, i, entry.Account, nullableString(entry.ProviderID), entry.Amount.Amount); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// GetUnpostedCompletedJobs lists up to limit completed requests whose earnings are not in the ledger yet
+func (repo *LedgerRepository) GetUnpostedCompletedJobs(limit int) ([]model.CompletedJob, error) {
+	rows, err := repo.db.Query(config.UnpostedCompletedJobsQuery(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []model.CompletedJob
+	for rows.Next() {
+		var job model.CompletedJob
+		var completedAt []uint8
+		if err := rows.Scan(&job.RequestID, &job.ProviderID, &job.ServiceID, &job.ServiceName, &job.Price.Amount,
+			&job.Price.Currency, &completedAt); err != nil {
+			return nil, err
+		}
+		if job.CompletedAt, err = util.ParseTime(completedAt); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// GetProviderJournals returns the provider's journals that occurred in [from, to), oldest first
+func (repo *LedgerRepository) GetProviderJournals(providerID string, from, to time.Time) ([]model.LedgerJournal, error) {
+	query := config.LedgerJournalsQuery("j.provider_id = ? AND j.occurred_at >= ? AND j.occurred_at < ?")
+	return repo.getJournals(query, providerID, from.UTC(), to.UTC())
+}
+
+func (repo *LedgerRepository) GetJournalsByReference(kind model.JournalKind, reference string) ([]model.LedgerJournal, error) {
+	query := config.LedgerJournalsQuery("j.kind = ? AND j.reference = ?")
+	return repo.getJournals(query, kind, reference)
+}
+
+// GetPayableBalances returns what the platform owes each provider, or only the given provider, per currency
+func (repo *LedgerRepository) GetPayableBalances(providerID string) ([]model.ProviderBalance, error) {
+	var rows *sql.Rows
+	var err error
+	if providerID != "" {
+		rows, err = repo.db.Query(config.PayableBalancesQuery(true), providerID)
+	} else {
+		rows, err = repo.db.Query(config.PayableBalancesQuery(false))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []model.ProviderBalance
+	for rows.Next() {
+		var balance model.ProviderBalance
+		if err := rows.Scan(&balance.ProviderID, &balance.Amount.Currency, &balance.Amount.Amount); err != nil {
+			return nil, err
+		}
+		balances = append(balances, balance)
+	}
+	return balances, rows.Err()
+}
+
+// getJournals reads the one-row-per-entry result of a LedgerJournalsQuery back into journals
+func (repo *LedgerRepository) getJournals(query string, args ...interface{}) ([]model.LedgerJournal, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var journals []model.LedgerJournal
+	for rows.Next() {
+		var journal model.LedgerJournal
+		var entry model.LedgerEntry
+		var occurredAt []uint8
+		err := rows.Scan(&journal.ID, &journal.Kind, &journal.Reference, &journal.ProviderID, &journal.RequestID, &journal.ServiceID,
+			&journal.ServiceName, &journal.Currency, &occurredAt, &entry.Account, &entry.ProviderID, &entry.Amount.Amount)
+		if err != nil {
+			return nil, err
+		}
+		entry.Amount.Currency = journal.Currency
+
+		if n := len(journals); n > 0 && journals[n-1].ID == journal.ID {
+			journals[n-1].Entries = append(journals[n-1].Entries, entry)
+			continue
+		}
+		if journal.OccurredAt, err = util.ParseTime(occurredAt); err != nil {
+			return nil, err
+		}
+		journal.Entries = []model.LedgerEntry{entry}
+		journals = append(journals, journal)
+	}
+	return journals, rows.Err()
+}
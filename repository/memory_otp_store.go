@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"crypto/subtle"
+	"serviceNest/config"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"sync"
+	"time"
+)
+
+type otpKey struct {
+	email   string
+	purpose model.OtpPurpose
+}
+
+// MemoryOtpStore keeps OTPs in process memory. They are lost on restart and not shared between
+// replicas, so it only suits a single instance and tests.
+type MemoryOtpStore struct {
+	mu   sync.Mutex
+	otps map[otpKey]model.Otp
+}
+
+// NewMemoryOtpStore initializes an empty in-memory OtpStore
+func NewMemoryOtpStore() interfaces.OtpStore {
+	return &MemoryOtpStore{otps: make(map[otpKey]model.Otp)}
+}
+
+// SaveOTP stores an OTP, replacing any earlier one for the same email and purpose
+func (s *MemoryOtpStore) SaveOTP(otp *model.Otp) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := *otp
+	saved.Attempts = 0
+	s.otps[otpKey{otp.Email, otp.Purpose}] = saved
+	return nil
+}
+
+// ConsumeOTP checks an OTP and uses it up when it matches. Every wrong guess counts, and after
+// config.MAX_OTP_ATTEMPTS of them the OTP is deleted.
+func (s *MemoryOtpStore) ConsumeOTP(email string, purpose model.OtpPurpose, codeHash string, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := otpKey{email, purpose}
+	otp, exists := s.otps[key]
+	if !exists {
+		return false, nil
+	}
+	if !now.Before(otp.ExpiresAt) {
+		delete(s.otps, key)
+		return false, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(otp.CodeHash), []byte(codeHash)) != 1 {
+		otp.Attempts++
+		if otp.Attempts >= config.MAX_OTP_ATTEMPTS {
+			delete(s.otps, key)
+		} else {
+			s.otps[key] = otp
+		}
+		return false, nil
+	}
+	delete(s.otps, key)
+	return true, nil
+}
+
+// DeleteExpiredOTPs removes the OTPs that can no longer be used and returns how many there were
+func (s *MemoryOtpStore) DeleteExpiredOTPs(now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deleted int64
+	for key, otp := range s.otps {
+		if !now.Before(otp.ExpiresAt) {
+			delete(s.otps, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
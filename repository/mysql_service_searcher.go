@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"database/sql"
+	"serviceNest/config"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+// MySQLServiceSearcher uses ngram FULLTEXT indexes to find candidate services and re-ranks them with the
+// shared typo-tolerant scoring. Its FULLTEXT indexes are created by migrations/006_service_search_index.sql.
+type MySQLServiceSearcher struct {
+	db *sql.DB
+}
+
+func NewMySQLServiceSearcher(db *sql.DB) interfaces.ServiceSearcher {
+	return &MySQLServiceSearcher{db: db}
+}
+
+func (s *MySQLServiceSearcher) Search(query string, limit, offset int) ([]model.ServiceSearchResult, error) {
+	booleanQuery := util.FullTextBooleanQuery(query)
+	if booleanQuery == "" {
+		return []model.ServiceSearchResult{}, nil
+	}
+
+	rows, err := s.db.Query(config.SearchServicesQuery(), booleanQuery, booleanQuery, booleanQuery, booleanQuery,
+		config.SEARCH_CANDIDATE_LIMIT)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []model.Service
+	for rows.Next() {
+		var service model.Service
+		var providerID, providerName, providerContact, providerAddress sql.NullString
+		err := rows.Scan(&service.ID, &service.Name, &service.Description, &service.Price.Amount, &service.Price.Currency, &providerID, &service.Category,
+			&service.AvgRating, &service.RatingCount, &providerName, &providerContact, &providerAddress)
+		if err != nil {
+			return nil, err
+		}
+		service.ProviderID = providerID.String
+		service.ProviderName = providerName.String
+		service.ProviderContact = providerContact.String
+		service.ProviderAddress = providerAddress.String
+		candidates = append(candidates, service)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return util.ApplyPagination(util.ScoreServices(query, candidates), limit, offset), nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/logger"
+)
+
+// withNamedLock runs fn while holding the MySQL named lock name. The lock is held by a connection of its
+// own, so fn is free to use the pool, and callers queue on it for at most timeoutSeconds before busyErr
+// is returned instead.
+func withNamedLock(db *sql.DB, name string, timeoutSeconds int, busyErr string, fn func() error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, config.AcquireLockQuery(), name, timeoutSeconds).Scan(&acquired); err != nil {
+		return err
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return errors.New(busyErr)
+	}
+	defer func() {
+		var released sql.NullInt64
+		if err := conn.QueryRowContext(ctx, config.ReleaseLockQuery(), name).Scan(&released); err != nil {
+			logger.Error("Error releasing lock", map[string]interface{}{"lock": name, "error": err.Error()})
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type MySQLOtpStore struct {
+	db *sql.DB
+}
+
+// NewMySQLOtpStore initializes an OtpStore that keeps OTPs in MySQL, shared by every replica
+func NewMySQLOtpStore(db *sql.DB) interfaces.OtpStore {
+	return &MySQLOtpStore{db: db}
+}
+
+// SaveOTP stores an OTP, replacing any earlier one for the same email and purpose
+func (s *MySQLOtpStore) SaveOTP(otp *model.Otp) error {
+	_, err := s.db.Exec(config.SaveOtpQuery(), otp.Email, otp.Purpose, otp.CodeHash, otp.CreatedAt.UTC(), otp.ExpiresAt.UTC())
+	return err
+}
+
+// ConsumeOTP checks an OTP and uses it up when it matches. Every wrong guess counts, and after
+// config.MAX_OTP_ATTEMPTS of them the OTP is deleted, so it cannot be brute-forced while it is valid.
+func (s *MySQLOtpStore) ConsumeOTP(email string, purpose model.OtpPurpose, codeHash string, now time.Time) (valid bool, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	var savedHash string
+	var attempts int
+	var expiresAt []uint8
+	err = tx.QueryRow(config.LockOtpQuery(), email, purpose).Scan(&savedHash, &attempts, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	expiry, err := util.ParseTime(expiresAt)
+	if err != nil {
+		return false, err
+	}
+
+	deleteQuery := config.DeleteQuery("otps", "email", "purpose")
+	if !now.Before(expiry) {
+		_, err = tx.Exec(deleteQuery, email, purpose)
+		return false, err
+	}
+	if subtle.ConstantTimeCompare([]byte(savedHash), []byte(codeHash)) != 1 {
+		if attempts+1 >= config.MAX_OTP_ATTEMPTS {
+			_, err = tx.Exec(deleteQuery, email, purpose)
+		} else {
+			_, err = tx.Exec(config.UpdateQuery("otps", "email", "purpose", []string{"attempts"}), attempts+1, email, purpose)
+		}
+		return false, err
+	}
+	if _, err = tx.Exec(deleteQuery, email, purpose); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteExpiredOTPs removes the OTPs that can no longer be used and returns how many there were
+func (s *MySQLOtpStore) DeleteExpiredOTPs(now time.Time) (int64, error) {
+	result, err := s.db.Exec(config.DeleteExpiredOtpsQuery(), now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
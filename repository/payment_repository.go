@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type PaymentRepository struct {
+	db *sql.DB
+}
+
+// NewPaymentRepository initializes a new PaymentRepository with MySQL
+func NewPaymentRepository(db *sql.DB) interfaces.PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+var paymentColumns = []string{"id", "service_request_id", "householder_id", "provider_id", "currency", "amount_minor", "refunded_minor", "status", "capture_mode", "gateway", "intent_id", "created_at", "updated_at"}
+
+func (repo *PaymentRepository) SavePayment(payment *model.Payment) error {
+	query := config.InsertQuery("payments", paymentColumns)
+	_, err := repo.db.Exec(query, payment.ID, payment.RequestID, payment.HouseholderID, payment.ProviderID, payment.Amount.Currency,
+		payment.Amount.Amount, payment.Refunded.Amount, payment.Status, payment.CaptureMode, payment.Gateway, payment.IntentID,
+		payment.CreatedAt.UTC(), payment.UpdatedAt.UTC())
+	if isDuplicateEntry(err) {
+		return errors.New(errs.PaymentAlreadyExists)
+	}
+	return err
+}
+
+func (repo *PaymentRepository) GetPaymentByID(paymentID string) (*model.Payment, error) {
+	query := config.SelectQuery("payments", "id", "", paymentColumns)
+	return repo.getPayment(query, paymentID)
+}
+
+func (repo *PaymentRepository) GetPaymentByIntentID(gateway, intentID string) (*model.Payment, error) {
+	query := config.SelectQuery("payments", "gateway", "intent_id", paymentColumns)
+	return repo.getPayment(query, gateway, intentID)
+}
+
+// GetPaymentsByRequestID returns every payment attempt on a request, oldest first
+func (repo *PaymentRepository) GetPaymentsByRequestID(requestID string) ([]model.Payment, error) {
+	query := config.SelectQuery("payments", "service_request_id", "", paymentColumns) + " ORDER BY created_at"
+	return repo.getPayments(query, requestID)
+}
+
+// UpdatePayment stores the status, amount and refunded amount of a payment, provided it is still in fromStatus.
+// It reports whether the payment was updated, so concurrent webhook deliveries and jobs apply a
+// transition only once.
+func (repo *PaymentRepository) UpdatePayment(payment *model.Payment, fromStatus model.PaymentStatus) (bool, error) {
+	query := config.UpdateQuery("payments", "id", "status", []string{"status", "amount_minor", "refunded_minor", "updated_at"})
+	result, err := repo.db.Exec(query, payment.Status, payment.Amount.Amount, payment.Refunded.Amount, payment.UpdatedAt.UTC(), payment.ID, fromStatus)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ReserveRefund adds amount to the refunded total of a captured payment in one conditional update and
+// reports whether it fit. Concurrent refunds are applied one after another, so together they never
+// exceed what was paid.
+func (repo *PaymentRepository) ReserveRefund(paymentID string, amount int64, now time.Time) (bool, error) {
+	result, err := repo.db.Exec(config.ReserveRefundQuery(), amount, now.UTC(), paymentID, amount)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ReleaseRefund gives back a reserved refund that was not paid out
+func (repo *PaymentRepository) ReleaseRefund(paymentID string, amount int64, now time.Time) error {
+	_, err := repo.db.Exec(config.ReleaseRefundQuery(), amount, now.UTC(), paymentID, amount)
+	return err
+}
+
+// GetCapturablePayments lists the authorized payments held until completion whose job the householder has confirmed
+func (repo *PaymentRepository) GetCapturablePayments() ([]model.Payment, error) {
+	return repo.getPayments(config.CapturablePaymentsQuery(paymentColumns))
+}
+
+// ApplyWebhookEvent records a webhook event and, when payment is given, moves it from fromStatus to
+// payment.Status, in one transaction. The event is recorded first: a delivery of an event that is already
+// recorded, even one racing with it, changes nothing and reports recorded false. moved reports whether
+// the payment was still in fromStatus and changed.
+func (repo *PaymentRepository) ApplyWebhookEvent(gateway string, event *model.PaymentWebhookEvent, payment *model.Payment, fromStatus model.PaymentStatus) (recorded, moved bool, err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return false, false, err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil || !recorded {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	result, err := tx.Exec(config.RecordWebhookEventQuery(), gateway, event.ID, event.Type, event.IntentID, time.Now().UTC())
+	if err != nil {
+		return false, false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, false, err
+	}
+	if payment == nil {
+		return true, false, nil
+	}
+	query := config.UpdateQuery("payments", "id", "status", []string{"status", "refunded_minor", "updated_at"})
+	result, err = tx.Exec(query, payment.Status, payment.Refunded.Amount, payment.UpdatedAt.UTC(), payment.ID, fromStatus)
+	if err != nil {
+		return false, false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, false, err
+	}
+	return true, affected == 1, nil
+}
+
+// GetCancelledPayments lists the payments of cancelled requests that are not settled yet, with the
+// cancellation fee the householder owes on each
+func (repo *PaymentRepository) GetCancelledPayments() ([]model.CancelledPayment, error) {
+	rows, err := repo.db.Query(config.CancelledPaymentsQuery(paymentColumns))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cancelled []model.CancelledPayment
+	for rows.Next() {
+		var fee int64
+		payment, err := scanPayment(withExtraColumns{rows, []interface{}{&fee}})
+		if err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, model.CancelledPayment{Payment: *payment, Fee: model.NewMoney(fee, payment.Amount.Currency)})
+	}
+	return cancelled, rows.Err()
+}
+
+func (repo *PaymentRepository) getPayment(query string, args ...interface{}) (*model.Payment, error) {
+	payment, err := scanPayment(repo.db.QueryRow(query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.PaymentNotFound)
+		}
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (repo *PaymentRepository) getPayments(query string, args ...interface{}) ([]model.Payment, error) {
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []model.Payment
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, *payment)
+	}
+	return payments, rows.Err()
+}
+
+// scanPayment reads a payment; the refunded amount is stored in the payment's currency
+func scanPayment(row rowScanner) (*model.Payment, error) {
+	var payment model.Payment
+	var createdAt, updatedAt []uint8
+	err := row.Scan(&payment.ID, &payment.RequestID, &payment.HouseholderID, &payment.ProviderID, &payment.Amount.Currency,
+		&payment.Amount.Amount, &payment.Refunded.Amount, &payment.Status, &payment.CaptureMode, &payment.Gateway,
+		&payment.IntentID, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	payment.Refunded.Currency = payment.Amount.Currency
+	if payment.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if payment.UpdatedAt, err = util.ParseTime(updatedAt); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// withExtraColumns scans rows that carry columns after those a scan function reads into extra
+type withExtraColumns struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+func (w withExtraColumns) Scan(dest ...interface{}) error {
+	return w.row.Scan(append(dest, w.extra...)...)
+}
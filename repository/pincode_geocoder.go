@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"strings"
+)
+
+type PincodeCentroid struct {
+	Pincode   string  `json:"pincode"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// PincodeGeocoder resolves pincodes to coordinates from an offline centroid table, so
+// geocoding works without any network access
+type PincodeGeocoder struct {
+	centroids map[string]PincodeCentroid
+	districts map[string]PincodeCentroid
+}
+
+// NewPincodeGeocoder loads the centroid table from a JSON file of {pincode, latitude, longitude} entries
+func NewPincodeGeocoder(path string) (interfaces.Geocoder, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []PincodeCentroid
+	if err := json.Unmarshal(file, &entries); err != nil {
+		return nil, err
+	}
+	return NewPincodeGeocoderFromEntries(entries), nil
+}
+
+// NewPincodeGeocoderFromEntries builds a geocoder from an in-memory centroid table
+func NewPincodeGeocoderFromEntries(entries []PincodeCentroid) *PincodeGeocoder {
+	geocoder := &PincodeGeocoder{
+		centroids: make(map[string]PincodeCentroid, len(entries)),
+		districts: make(map[string]PincodeCentroid),
+	}
+	for _, entry := range entries {
+		geocoder.centroids[entry.Pincode] = entry
+		if len(entry.Pincode) < 3 {
+			continue
+		}
+		district := entry.Pincode[:3]
+		if existing, ok := geocoder.districts[district]; !ok || entry.Pincode < existing.Pincode {
+			geocoder.districts[district] = entry
+		}
+	}
+	return geocoder
+}
+
+// Geocode returns the centroid of the pincode. Pincodes missing from the table fall back to the
+// lowest known pincode of the same three digit sorting district.
+func (g *PincodeGeocoder) Geocode(pincode string) (float64, float64, error) {
+	pincode = strings.TrimSpace(pincode)
+	if centroid, ok := g.centroids[pincode]; ok {
+		return centroid.Latitude, centroid.Longitude, nil
+	}
+	if len(pincode) == 6 {
+		if centroid, ok := g.districts[pincode[:3]]; ok {
+			return centroid.Latitude, centroid.Longitude, nil
+		}
+	}
+	return 0, 0, errors.New(errs.UnknownPincode)
+}
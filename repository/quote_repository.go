@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type QuoteRepository struct {
+	db *sql.DB
+}
+
+// NewQuoteRepository initializes a new QuoteRepository with MySQL
+func NewQuoteRepository(db *sql.DB) interfaces.QuoteRepository {
+	return &QuoteRepository{db: db}
+}
+
+var quoteColumns = []string{"id", "service_request_id", "provider_id", "version", "proposed_by", "currency", "total", "valid_until", "status", "note", "created_at"}
+
+// SaveQuote stores a quote version together with its line items in one transaction
+func (repo *QuoteRepository) SaveQuote(quote *model.Quote) (err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	query := config.InsertQuery("quotes", quoteColumns)
+	_, err = tx.Exec(query, quote.ID, quote.RequestID, quote.ProviderID, quote.Version, quote.ProposedBy, quote.Total.Currency,
+		quote.Total.Amount, quote.ValidUntil.UTC(), quote.Status, quote.Note, quote.CreatedAt.UTC())
+	if err != nil {
+		if isDuplicateEntry(err) {
+			// Another version with this number was saved first, so the one this was based on is gone
+			return errors.New(errs.QuoteNotOpen)
+		}
+		return err
+	}
+	insert := config.InsertQuery("quote_line_items", []string{"quote_id", "position", "kind", "description", "amount"})
+	for i, item := range quote.LineItems {
+		if _, err = tx.Exec(insert, quote.ID, i, item.Kind, item.Description, item.Amount.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithRequestLock runs fn while holding a lock on the request, so that concurrent revisions and
+// counter-offers on its quotes are made one at a time, each on top of the latest version
+func (repo *QuoteRepository) WithRequestLock(requestID string, fn func() error) error {
+	var id string
+	err := repo.db.QueryRow(config.SelectQuery("service_requests", "id", "", []string{"id"}), requestID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New(errs.ServiceRequestNotFound)
+		}
+		return err
+	}
+	return withNamedLock(repo.db, config.REQUEST_LOCK_PREFIX+requestID, config.RECORD_LOCK_TIMEOUT_SECONDS, errs.RecordBusy, fn)
+}
+
+func (repo *QuoteRepository) GetQuoteByID(quoteID string) (*model.Quote, error) {
+	query := config.SelectQuery("quotes", "id", "", quoteColumns)
+	return repo.getQuote(query, quoteID)
+}
+
+// GetQuotesByRequestID returns the negotiation history of a request, grouped by provider and oldest version first
+func (repo *QuoteRepository) GetQuotesByRequestID(requestID string) ([]model.Quote, error) {
+	query := config.SelectQuery("quotes", "service_request_id", "", quoteColumns) + " ORDER BY provider_id, version"
+	rows, err := repo.db.Query(query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []model.Quote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, *quote)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range quotes {
+		if quotes[i].LineItems, err = repo.getLineItems(quotes[i].ID, quotes[i].Total.Currency); err != nil {
+			return nil, err
+		}
+	}
+	return quotes, nil
+}
+
+// GetLatestQuote returns the newest quote version between the provider and the householder on a request
+func (repo *QuoteRepository) GetLatestQuote(requestID, providerID string) (*model.Quote, error) {
+	query := config.SelectQuery("quotes", "service_request_id", "provider_id", quoteColumns) + " ORDER BY version DESC LIMIT 1"
+	return repo.getQuote(query, requestID, providerID)
+}
+
+func (repo *QuoteRepository) UpdateQuoteStatus(quoteID string, status model.QuoteStatus) error {
+	query := config.UpdateQuery("quotes", "id", "", []string{"status"})
+	_, err := repo.db.Exec(query, status, quoteID)
+	return err
+}
+
+// ApproveQuote approves a quote only if it is still open at the given version, reporting false otherwise
+func (repo *QuoteRepository) ApproveQuote(quoteID string, version int) (bool, error) {
+	result, err := repo.db.Exec(config.ApproveQuoteQuery(), quoteID, version)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// DeclineOpenQuotes declines the open quotes of every provider on the request except the given one
+func (repo *QuoteRepository) DeclineOpenQuotes(requestID, exceptProviderID string) error {
+	_, err := repo.db.Exec(config.DeclineOtherOpenQuotesQuery(), requestID, exceptProviderID)
+	return err
+}
+
+// ExpireLapsedQuotes expires open quotes whose validity ended at or before now and returns how many were expired
+func (repo *QuoteRepository) ExpireLapsedQuotes(now time.Time) (int64, error) {
+	result, err := repo.db.Exec(config.ExpireLapsedQuotesQuery(), now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeclineStaleQuotes declines open quotes on requests that can no longer be approved and returns how many were declined
+func (repo *QuoteRepository) DeclineStaleQuotes() (int64, error) {
+	result, err := repo.db.Exec(config.DeclineStaleQuotesQuery())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (repo *QuoteRepository) getQuote(query string, args ...interface{}) (*model.Quote, error) {
+	quote, err := scanQuote(repo.db.QueryRow(query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.QuoteNotFound)
+		}
+		return nil, err
+	}
+	if quote.LineItems, err = repo.getLineItems(quote.ID, quote.Total.Currency); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// getLineItems loads the line items of a quote; their amounts are stored in the quote's currency
+func (repo *QuoteRepository) getLineItems(quoteID, currency string) ([]model.QuoteLineItem, error) {
+	query := config.SelectQuery("quote_line_items", "quote_id", "", []string{"kind", "description", "amount"}) + " ORDER BY position"
+	rows, err := repo.db.Query(query, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.QuoteLineItem
+	for rows.Next() {
+		var item model.QuoteLineItem
+		var amount int64
+		if err := rows.Scan(&item.Kind, &item.Description, &amount); err != nil {
+			return nil, err
+		}
+		item.Amount = model.NewMoney(amount, currency)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func scanQuote(row rowScanner) (*model.Quote, error) {
+	var quote model.Quote
+	var validUntil, createdAt []uint8
+	err := row.Scan(&quote.ID, &quote.RequestID, &quote.ProviderID, &quote.Version, &quote.ProposedBy, &quote.Total.Currency,
+		&quote.Total.Amount, &validUntil, &quote.Status, &quote.Note, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if quote.ValidUntil, err = util.ParseTime(validUntil); err != nil {
+		return nil, err
+	}
+	if quote.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+)
+
+type RatingRepository struct {
+	db *sql.DB
+}
+
+// NewRatingRepository initializes a new RatingRepository with MySQL
+func NewRatingRepository(db *sql.DB) interfaces.RatingRepository {
+	return &RatingRepository{db: db}
+}
+
+// RecalculateProviderRating recomputes the rating of one provider's service and the provider's
+// overall rating from the reviews table. All copies of the rating are written in one transaction.
+func (repo *RatingRepository) RecalculateProviderRating(providerID string, serviceID string) error {
+	return repo.recalculate(true, providerID, serviceID)
+}
+
+// RecalculateAllRatings rebuilds every stored rating from the reviews table to repair drift
+func (repo *RatingRepository) RecalculateAllRatings() error {
+	return repo.recalculate(false, "", "")
+}
+
+func (repo *RatingRepository) recalculate(scoped bool, providerID string, serviceID string) (err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %v", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	var serviceArgs, providerArgs []interface{}
+	if scoped {
+		serviceArgs = []interface{}{providerID, serviceID}
+		providerArgs = []interface{}{providerID}
+	}
+
+	serviceQueries := []string{
+		config.UpdateServiceRatingQuery(scoped),
+		config.UpdateProviderServiceRatingQuery(scoped),
+		config.UpdateProviderDetailRatingQuery(scoped),
+	}
+	for _, query := range serviceQueries {
+		if _, err = tx.Exec(query, serviceArgs...); err != nil {
+			return fmt.Errorf("%v: %v", errs.FailUpdateRating, err)
+		}
+	}
+
+	if _, err = tx.Exec(config.UpdateProviderRatingQuery(scoped), providerArgs...); err != nil {
+		return fmt.Errorf("%v: %v", errs.FailUpdateRating, err)
+	}
+
+	return nil
+}
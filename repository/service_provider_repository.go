@@ -47,14 +47,7 @@ func (repo *ServiceProviderRepository) GetProviderByID(providerID string) (*mode
 }
 
 func (repo *ServiceProviderRepository) GetProvidersByServiceType(serviceType string) ([]model.ServiceProvider, error) {
-	query := `
-	SELECT sp.user_id, sp.rating, sp.availability, sp.is_active
-	FROM service_providers sp
-	INNER JOIN service_providers_services sps ON sp.user_id = sps.service_provider_id
-	INNER JOIN services s ON sps.service_id = s.id
-	WHERE s.name = ?
-	`
-	rows, err := repo.Collection.Query(query, serviceType)
+	rows, err := repo.Collection.Query(config.ProvidersByServiceTypeQuery(), serviceType)
 	if err != nil {
 		return nil, err
 	}
@@ -63,16 +56,40 @@ func (repo *ServiceProviderRepository) GetProvidersByServiceType(serviceType str
 	var providers []model.ServiceProvider
 	for rows.Next() {
 		var provider model.ServiceProvider
-		err := rows.Scan(&provider.User.ID, &provider.Rating, &provider.Availability, &provider.IsActive)
+		var latitude, longitude sql.NullFloat64
+		var serviceRadius sql.NullFloat64
+		err := rows.Scan(&provider.User.ID, &provider.Name, &provider.Address, &provider.Contact, &latitude, &longitude, &provider.Rating, &provider.Availability, &provider.IsActive, &serviceRadius)
 		if err != nil {
 			return nil, err
 		}
+		provider.Latitude = util.NullableFloat(latitude)
+		provider.Longitude = util.NullableFloat(longitude)
+		provider.ServiceRadiusKm = serviceRadius.Float64
 		providers = append(providers, provider)
 	}
 
 	return providers, nil
 }
 
+// UpdateServiceRadius sets how far, in kilometres, a provider is willing to travel
+func (repo *ServiceProviderRepository) UpdateServiceRadius(providerID string, radiusKm float64) error {
+	column := []string{"service_radius_km"}
+	query := config.UpdateQuery("service_providers", "user_id", "", column)
+
+	result, err := repo.Collection.Exec(query, radiusKm, providerID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(errs.ProviderNotFound)
+	}
+	return nil
+}
+
 func (repo *ServiceProviderRepository) GetProviderByServiceID(serviceID string) (*model.ServiceProvider, error) {
 	firstTableColumn := []string{"user_id", "rating", "availability", "is_active"}
 	secondTableColumn := []string{}
@@ -136,10 +153,10 @@ func (repo *ServiceProviderRepository) SaveServiceProviderDetail(provider *model
 
 	// Proceed with the insertion
 	id := util.GenerateUniqueID()
-	column := []string{"id", "service_request_id", "service_provider_id", "name", "contact", "address", "price", "rating", "approve", "service_id"}
+	column := []string{"id", "service_request_id", "service_provider_id", "name", "contact", "address", "price_minor", "currency", "rating", "approve", "service_id"}
 	query := config.InsertQuery("service_provider_details", column)
 
-	_, err = repo.Collection.Exec(query, id, requestID, provider.ServiceProviderID, provider.Name, provider.Contact, provider.Address, provider.Price, provider.Rating, provider.Approve, serviceID)
+	_, err = repo.Collection.Exec(query, id, requestID, provider.ServiceProviderID, provider.Name, provider.Contact, provider.Address, provider.Price.Amount, provider.Price.Currency, provider.Rating, provider.Approve, serviceID)
 	return err
 }
 
@@ -151,6 +168,15 @@ func (repo *ServiceProviderRepository) UpdateServiceProviderDetailByRequestID(pr
 	return err
 }
 
+// UpdateQuotedPrice keeps the price shown on the provider's quote in step with their latest quote version
+func (repo *ServiceProviderRepository) UpdateQuotedPrice(providerID, requestID string, price model.Money) error {
+	column := []string{"price_minor", "currency"}
+	query := config.UpdateQuery("service_provider_details", "service_provider_id", "service_request_id", column)
+
+	_, err := repo.Collection.Exec(query, price.Amount, price.Currency, providerID, requestID)
+	return err
+}
+
 func (repo *ServiceProviderRepository) IsProviderApproved(providerID string) (bool, error) {
 	var approveStatus bool
 	column := []string{"approve"}
@@ -166,19 +192,30 @@ func (repo *ServiceProviderRepository) IsProviderApproved(providerID string) (bo
 	return approveStatus, nil
 }
 
-// AddReview adds a review to the reviews table
+// AddReview adds a review to the reviews table, allowing at most one review per service request
 func (repo *ServiceProviderRepository) AddReview(review model.Review) error {
 	tx, err := repo.Collection.Begin()
 	if err != nil {
 		return err
 	}
 
-	columns := []string{"id", "provider_id", "service_id", "householder_id", "rating", "comments", "review_date"}
+	columns := []string{"id", "service_request_id", "provider_id", "service_id", "householder_id", "rating", "comments", "review_date"}
 	reviewQuery := config.InsertQuery("reviews", columns)
 
+	// Lock the request row so concurrent reviews for the same booking are serialised
+	var lockedID string
+	err = tx.QueryRow(config.LockServiceRequestQuery(), review.RequestID).Scan(&lockedID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New(errs.ServiceRequestNotFound)
+		}
+		return err
+	}
+
 	checkQuery := config.CountReviewAddedQuery()
 	var count int
-	err = tx.QueryRow(checkQuery, review.ProviderID, review.ServiceID, review.HouseholderID).Scan(&count)
+	err = tx.QueryRow(checkQuery, review.RequestID).Scan(&count)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -187,10 +224,10 @@ func (repo *ServiceProviderRepository) AddReview(review model.Review) error {
 	if count > 0 {
 
 		tx.Rollback()
-		return fmt.Errorf("review already exists for this provider, service, and householder")
+		return errors.New(errs.ReviewAlreadyExists)
 	}
 
-	_, err = tx.Exec(reviewQuery, review.ID, review.ProviderID, review.ServiceID, review.HouseholderID, review.Rating, review.Comments, review.ReviewDate)
+	_, err = tx.Exec(reviewQuery, review.ID, review.RequestID, review.ProviderID, review.ServiceID, review.HouseholderID, review.Rating, review.Comments, review.ReviewDate)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -199,49 +236,8 @@ func (repo *ServiceProviderRepository) AddReview(review model.Review) error {
 	return tx.Commit()
 }
 
-// UpdateProviderRating recalculates and updates the provider's average rating
-func (repo *ServiceProviderRepository) UpdateProviderRating(providerID string, serviceId string, rating float64) error {
-	// Calculate the  rating from the service table
-	column := []string{"avg_rating", "rating_count"}
-	ratingQuery := config.SelectQuery("services", "id", "provider_id", column)
-
-	var avgRating float64
-	var ratingCount int64
-	err := repo.Collection.QueryRow(ratingQuery, serviceId, providerID).Scan(&avgRating, &ratingCount)
-	if err != nil {
-		return fmt.Errorf("%v: %v", errs.FailCalculateRating, err)
-	}
-	updatedRating := util.CalculateRating(avgRating, ratingCount, rating)
-	// Update the rating in the service_providers table
-	providerColumn := []string{"rating"}
-	updateServiceProviderQuery := config.UpdateQuery("service_providers", "user_id", "", providerColumn)
-
-	_, err = repo.Collection.Exec(updateServiceProviderQuery, updatedRating, providerID)
-	if err != nil {
-		return fmt.Errorf("%ve: %v", errs.FailUpdateRating, err)
-	}
-
-	// Update the rating in the service_provider_details table
-	providerDetailColumn := []string{"rating"}
-	updateServiceProviderDetailsQuery := config.UpdateQuery("service_provider_details", "service_provider_id", "service_id", providerDetailColumn)
-
-	_, err = repo.Collection.Exec(updateServiceProviderDetailsQuery, updatedRating, providerID, serviceId)
-	if err != nil {
-		return fmt.Errorf("%v %v", errs.FailUpdateRating, err)
-	}
-	// Update the rating in the services table
-	serviceDetailColumn := []string{"avg_rating", "rating_count"}
-	updateServiceDetailsQuery := config.UpdateQuery("services", "id", "provider_id", serviceDetailColumn)
-
-	_, err = repo.Collection.Exec(updateServiceDetailsQuery, updatedRating, ratingCount+1, serviceId, providerID)
-	if err != nil {
-		return fmt.Errorf("%v %v", errs.FailUpdateRating, err)
-	}
-	return nil
-}
-
 func (repo *ServiceProviderRepository) GetReviewsByProviderID(providerID string, limit, offset int, serviceID string) ([]model.Review, error) {
-	column := []string{"id", "provider_id", "service_id", "householder_id", "rating", "comments", "review_date"}
+	column := reviewColumns()
 	var query string
 	var rows *sql.Rows
 	var err error
@@ -263,18 +259,11 @@ func (repo *ServiceProviderRepository) GetReviewsByProviderID(providerID string,
 
 	var reviews []model.Review
 	for rows.Next() {
-		var review model.Review
-		var reviewDate []uint8
-		err := rows.Scan(&review.ID, &review.ProviderID, &review.ServiceID, &review.HouseholderID, &review.Rating, &review.Comments, &reviewDate)
-		if err != nil {
-			return nil, err
-		}
-		parsedDate, err := util.ParseTime(reviewDate)
+		review, err := scanReview(rows)
 		if err != nil {
 			return nil, err
 		}
-		review.ReviewDate = parsedDate
-		reviews = append(reviews, review)
+		reviews = append(reviews, *review)
 	}
 	if len(reviews) == 0 {
 		return nil, fmt.Errorf("no reviews found")
@@ -318,3 +307,108 @@ func (repo *ServiceProviderRepository) DeleteServicesByProviderID(providerID str
 	_, err := repo.Collection.Exec(query, providerID)
 	return err
 }
+
+func reviewColumns() []string {
+	return []string{"id", "service_request_id", "provider_id", "service_id", "householder_id", "rating", "comments", "review_date", "updated_at", "reply", "reply_date"}
+}
+
+// scanReview reads a row selected with reviewColumns, including the optional provider reply
+func scanReview(row interface{ Scan(dest ...any) error }) (*model.Review, error) {
+	var review model.Review
+	var reviewDate, updatedAt, replyDate []uint8
+	var requestID, reply sql.NullString
+	err := row.Scan(&review.ID, &requestID, &review.ProviderID, &review.ServiceID, &review.HouseholderID, &review.Rating, &review.Comments, &reviewDate, &updatedAt, &reply, &replyDate)
+	if err != nil {
+		return nil, err
+	}
+	// Reviews written before they were tied to a request have none
+	review.RequestID = requestID.String
+	review.ReviewDate, err = util.ParseTime(reviewDate)
+	if err != nil {
+		return nil, err
+	}
+	review.UpdatedAt, err = util.ParseNullableTime(updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Valid {
+		review.Reply = &model.ReviewReply{Comments: reply.String}
+		parsedReplyDate, err := util.ParseNullableTime(replyDate)
+		if err != nil {
+			return nil, err
+		}
+		if parsedReplyDate != nil {
+			review.Reply.ReplyDate = *parsedReplyDate
+		}
+	}
+	return &review, nil
+}
+
+// GetReviewByID retrieves a single review together with its reply
+func (repo *ServiceProviderRepository) GetReviewByID(reviewID string) (*model.Review, error) {
+	query := config.SelectQuery("reviews", "id", "", reviewColumns())
+
+	review, err := scanReview(repo.Collection.QueryRow(query, reviewID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.ReviewNotFound)
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+// UpdateReview updates the rating and comments of a review
+func (repo *ServiceProviderRepository) UpdateReview(review *model.Review) error {
+	column := []string{"rating", "comments", "updated_at"}
+	query := config.UpdateQuery("reviews", "id", "", column)
+
+	_, err := repo.Collection.Exec(query, review.Rating, review.Comments, review.UpdatedAt, review.ID)
+	return err
+}
+
+// DeleteReview removes a review
+func (repo *ServiceProviderRepository) DeleteReview(reviewID string) error {
+	query := config.DeleteQuery("reviews", "id", "")
+
+	result, err := repo.Collection.Exec(query, reviewID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(errs.ReviewNotFound)
+	}
+	return nil
+}
+
+// AddReviewReply stores the provider's reply on a review; a review can only be replied to once
+func (repo *ServiceProviderRepository) AddReviewReply(reviewID string, reply model.ReviewReply) error {
+	query := `UPDATE reviews SET reply = ?, reply_date = ? WHERE id = ? AND reply IS NULL`
+
+	result, err := repo.Collection.Exec(query, reply.Comments, reply.ReplyDate, reviewID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(errs.ReviewAlreadyReplied)
+	}
+	return nil
+}
+
+// DeclineUnapprovedQuotes marks every quote the householder can no longer approve as declined and
+// returns how many were declined
+func (repo *ServiceProviderRepository) DeclineUnapprovedQuotes() (int64, error) {
+	result, err := repo.Collection.Exec(config.DeclineUnapprovedQuotesQuery())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -8,6 +8,8 @@ import (
 	"serviceNest/errs"
 	"serviceNest/interfaces"
 	"serviceNest/model"
+	"serviceNest/util"
+	"time"
 )
 
 type ServiceRepository struct {
@@ -20,7 +22,7 @@ func NewServiceRepository(client *sql.DB) interfaces.ServiceRepository {
 }
 
 func (repo *ServiceRepository) GetAllServices(limit, offset int) ([]model.Service, error) {
-	column := []string{"id", "name", "description", "price", "provider_id", "category", "avg_rating", "rating_count"}
+	column := []string{"id", "name", "description", "price_minor", "currency", "provider_id", "category", "avg_rating", "rating_count"}
 	query := config.SelectQueryWithLimit("services", "", "", column, limit, offset)
 
 	rows, err := repo.db.Query(query)
@@ -34,7 +36,7 @@ func (repo *ServiceRepository) GetAllServices(limit, offset int) ([]model.Servic
 		var service model.Service
 		var providerID sql.NullString
 
-		if err := rows.Scan(&service.ID, &service.Name, &service.Description, &service.Price, &providerID, &service.Category, &service.AvgRating, &service.RatingCount); err != nil {
+		if err := rows.Scan(&service.ID, &service.Name, &service.Description, &service.Price.Amount, &service.Price.Currency, &providerID, &service.Category, &service.AvgRating, &service.RatingCount); err != nil {
 			return nil, err
 		}
 
@@ -56,11 +58,11 @@ func (repo *ServiceRepository) GetAllServices(limit, offset int) ([]model.Servic
 
 // GetServiceByID retrieves a service by its ID
 func (repo *ServiceRepository) GetServiceByID(serviceID string) (*model.Service, error) {
-	column := []string{"id", "name", "description", "price", "provider_id", "category"}
+	column := []string{"id", "name", "description", "price_minor", "currency", "provider_id", "category"}
 	query := config.SelectQuery("services", "id", "", column)
 
 	var service model.Service
-	err := repo.db.QueryRow(query, serviceID).Scan(&service.ID, &service.Name, &service.Description, &service.Price, &service.ProviderID, &service.Category)
+	err := repo.db.QueryRow(query, serviceID).Scan(&service.ID, &service.Name, &service.Description, &service.Price.Amount, &service.Price.Currency, &service.ProviderID, &service.Category)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New(errs.ServiceNotFound)
@@ -73,7 +75,7 @@ func (repo *ServiceRepository) GetServiceByID(serviceID string) (*model.Service,
 
 // SaveService adds a new service to the MySQL database
 func (repo *ServiceRepository) SaveService(service model.Service) error {
-	column := []string{"id", "name", "description", "price", "provider_id", "category", "avg_rating", "rating_count"}
+	column := []string{"id", "name", "description", "price_minor", "currency", "provider_id", "category", "avg_rating", "rating_count", "estimated_minutes"}
 	query := config.InsertQuery("services", column)
 
 	var providerID *string
@@ -82,7 +84,7 @@ func (repo *ServiceRepository) SaveService(service model.Service) error {
 	} else {
 		providerID = &service.ProviderID
 	}
-	_, err := repo.db.Exec(query, service.ID, service.Name, service.Description, service.Price, providerID, service.Category, service.AvgRating, service.RatingCount)
+	_, err := repo.db.Exec(query, service.ID, service.Name, service.Description, service.Price.Amount, service.Price.Currency, providerID, service.Category, service.AvgRating, service.RatingCount, service.EstimatedMinutes)
 	return err
 }
 
@@ -160,7 +162,7 @@ func (repo *ServiceRepository) GetServiceIdByCategory(category string) (*string,
 
 // GetServiceByProviderID retrieves a service by its ProviderID
 func (repo *ServiceRepository) GetServiceByProviderID(providerID string) ([]model.Service, error) {
-	column := []string{"id", "name", "description", "price", "provider_id", "category", "avg_rating", "rating_count"}
+	column := []string{"id", "name", "description", "price_minor", "currency", "provider_id", "category", "avg_rating", "rating_count"}
 	query := config.SelectQuery("services", "provider_id", "", column)
 
 	rows, err := repo.db.Query(query, providerID)
@@ -173,7 +175,7 @@ func (repo *ServiceRepository) GetServiceByProviderID(providerID string) ([]mode
 	var services []model.Service
 	for rows.Next() {
 		var service model.Service
-		err := rows.Scan(&service.ID, &service.Name, &service.Description, &service.Price, &service.ProviderID, &service.Category, &service.AvgRating, &service.RatingCount)
+		err := rows.Scan(&service.ID, &service.Name, &service.Description, &service.Price.Amount, &service.Price.Currency, &service.ProviderID, &service.Category, &service.AvgRating, &service.RatingCount)
 		if err != nil {
 			return nil, err
 		} else {
@@ -185,10 +187,10 @@ func (repo *ServiceRepository) GetServiceByProviderID(providerID string) ([]mode
 }
 
 func (repo *ServiceRepository) UpdateService(providerID string, updatedService model.Service) error {
-	column := []string{"name", "description", "price"}
+	column := []string{"name", "description", "price_minor", "currency", "estimated_minutes"}
 	query := config.UpdateQuery("services", "provider_id", "id", column)
 
-	result, err := repo.db.Exec(query, updatedService.Name, updatedService.Description, updatedService.Price, providerID, updatedService.ID)
+	result, err := repo.db.Exec(query, updatedService.Name, updatedService.Description, updatedService.Price.Amount, updatedService.Price.Currency, updatedService.EstimatedMinutes, providerID, updatedService.ID)
 	// Check how many rows were affected
 	if err != nil {
 
@@ -248,7 +250,7 @@ func (r *ServiceRepository) CategoryExists(categoryName string) (bool, error) {
 }
 
 func (repo *ServiceRepository) GetServicesByCategory(category string) ([]model.Service, error) {
-	column := []string{"id", "name", "description", "price", "provider_id", "category", "avg_rating"}
+	column := []string{"id", "name", "description", "price_minor", "currency", "provider_id", "category", "avg_rating"}
 	query := config.SelectQuery("services", "category", "", column)
 
 	rows, err := repo.db.Query(query, category)
@@ -261,7 +263,7 @@ func (repo *ServiceRepository) GetServicesByCategory(category string) ([]model.S
 	var services []model.Service
 	for rows.Next() {
 		var service model.Service
-		err := rows.Scan(&service.ID, &service.Name, &service.Description, &service.Price, &service.ProviderID, &service.Category, &service.AvgRating)
+		err := rows.Scan(&service.ID, &service.Name, &service.Description, &service.Price.Amount, &service.Price.Currency, &service.ProviderID, &service.Category, &service.AvgRating)
 		if err != nil {
 			return nil, err
 		} else {
@@ -303,3 +305,71 @@ func (repo *ServiceRepository) AddCategory(service *model.Category) error {
 	return err
 
 }
+
+// GetRankingSignals collects the review recency, job completion and cancellation penalty data used to
+// rank services
+func (repo *ServiceRepository) GetRankingSignals() (*model.RankingSignals, error) {
+	signals := &model.RankingSignals{
+		LastReviewDates: make(map[string]time.Time),
+		ProviderJobs:    make(map[string]model.ProviderJobStats),
+	}
+
+	rows, err := repo.db.Query(config.LastReviewDatesQuery())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var providerID, serviceID string
+		var lastReview []uint8
+		if err := rows.Scan(&providerID, &serviceID, &lastReview); err != nil {
+			return nil, err
+		}
+		lastReviewDate, err := util.ParseTime(lastReview)
+		if err != nil {
+			return nil, err
+		}
+		signals.LastReviewDates[model.ServiceKey(providerID, serviceID)] = lastReviewDate
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	jobRows, err := repo.db.Query(config.ProviderJobStatsQuery(), model.StatusCompleted)
+	if err != nil {
+		return nil, err
+	}
+	defer jobRows.Close()
+	for jobRows.Next() {
+		var providerID string
+		var stats model.ProviderJobStats
+		if err := jobRows.Scan(&providerID, &stats.ApprovedJobs, &stats.CompletedJobs); err != nil {
+			return nil, err
+		}
+		signals.ProviderJobs[providerID] = stats
+	}
+	if err := jobRows.Err(); err != nil {
+		return nil, err
+	}
+
+	penaltyRows, err := repo.db.Query(config.ProviderPenaltyPointsQuery())
+	if err != nil {
+		return nil, err
+	}
+	defer penaltyRows.Close()
+	for penaltyRows.Next() {
+		var providerID string
+		var points int64
+		if err := penaltyRows.Scan(&providerID, &points); err != nil {
+			return nil, err
+		}
+		stats := signals.ProviderJobs[providerID]
+		stats.PenaltyPoints = points
+		signals.ProviderJobs[providerID] = stats
+	}
+	if err := penaltyRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return signals, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"database/sql"
+	"serviceNest/config"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type ServiceRequestEventRepository struct {
+	db *sql.DB
+}
+
+// NewServiceRequestEventRepository initializes a new ServiceRequestEventRepository with MySQL
+func NewServiceRequestEventRepository(db *sql.DB) interfaces.ServiceRequestEventRepository {
+	return &ServiceRequestEventRepository{db: db}
+}
+
+// SaveEvent records a single status transition of a service request
+func (repo *ServiceRequestEventRepository) SaveEvent(event *model.ServiceRequestEvent) error {
+	return insertRequestEvent(repo.db, event)
+}
+
+// insertRequestEvent writes an event on its own or as part of a caller's transaction
+func insertRequestEvent(db execer, event *model.ServiceRequestEvent) error {
+	column := []string{"id", "service_request_id", "actor_id", "actor_role", "old_status", "new_status", "reason", "created_at"}
+	query := config.InsertQuery("service_request_events", column)
+
+	_, err := db.Exec(query, event.ID, event.RequestID, event.ActorID, event.ActorRole, event.OldStatus, event.NewStatus, event.Reason, event.CreatedAt.Format("2006-01-02 15:04:05"))
+	return err
+}
+
+// GetEventsByRequestID returns the status history of a service request, oldest first
+func (repo *ServiceRequestEventRepository) GetEventsByRequestID(requestID string) ([]model.ServiceRequestEvent, error) {
+	column := []string{"id", "service_request_id", "actor_id", "actor_role", "old_status", "new_status", "reason", "created_at"}
+	query := config.SelectQuery("service_request_events", "service_request_id", "", column) + " ORDER BY created_at ASC"
+
+	rows, err := repo.db.Query(query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.ServiceRequestEvent
+	for rows.Next() {
+		var event model.ServiceRequestEvent
+		var createdAt []uint8
+		err := rows.Scan(&event.ID, &event.RequestID, &event.ActorID, &event.ActorRole, &event.OldStatus, &event.NewStatus, &event.Reason, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+		event.CreatedAt, err = util.ParseTime(createdAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -10,6 +10,7 @@ import (
 	"serviceNest/interfaces"
 	"serviceNest/model"
 	"serviceNest/util"
+	"time"
 )
 
 type ServiceRequestRepository struct {
@@ -21,29 +22,35 @@ func NewServiceRequestRepository(db *sql.DB) interfaces.ServiceRequestRepository
 	return &ServiceRequestRepository{db: db}
 }
 
-// SaveServiceRequest saves a service request to the MySQL database
+// SaveServiceRequest saves a service request to the MySQL database. Saving a second request for the same
+// occurrence of a booking series fails with errs.SeriesOccurrenceExists.
 func (repo *ServiceRequestRepository) SaveServiceRequest(request model.ServiceRequest) error {
-	column := []string{"id", "householder_id", "householder_name", "householder_address", "householder_contact", "service_id", "requested_time", "scheduled_time", "status", "approve_status", "service_name", "description"}
+	column := []string{"id", "householder_id", "householder_name", "householder_address", "householder_contact", "service_id", "requested_time", "scheduled_time", "status", "approve_status", "service_name", "description", "series_id", "series_occurrence"}
 	query := config.InsertQuery("service_requests", column)
 
-	_, err := repo.db.Exec(query, request.ID, request.HouseholderID, request.HouseholderName, request.HouseholderAddress, request.HouseholderContact, request.ServiceID, request.RequestedTime.Format("2006-01-02 15:04:05"), request.ScheduledTime, request.Status, request.ApproveStatus, request.ServiceName, request.Description)
+	_, err := repo.db.Exec(query, request.ID, request.HouseholderID, request.HouseholderName, request.HouseholderAddress, request.HouseholderContact, request.ServiceID, request.RequestedTime.Format("2006-01-02 15:04:05"), request.ScheduledTime, request.Status, request.ApproveStatus, request.ServiceName, request.Description, request.SeriesID, request.SeriesOccurrence)
+	if err != nil && request.SeriesID != nil && isDuplicateEntry(err) {
+		return errors.New(errs.SeriesOccurrenceExists)
+	}
 	return err
 }
 
 // GetServiceRequestByID retrieves a service request by its ID from MySQL
 func (repo *ServiceRequestRepository) GetServiceRequestByID(requestID string) (*model.ServiceRequest, error) {
-	firstTableColumn := []string{"id", "householder_id", "householder_name", "householder_address", "service_id", "requested_time", "scheduled_time", "status", "approve_status"}
+	firstTableColumn := []string{"id", "householder_id", "householder_name", "householder_address", "service_id", "requested_time", "scheduled_time", "status", "approve_status", "actual_start_time", "actual_end_time", "completion_confirmed", "series_id"}
 	secondTableColumn := []string{"name"}
 	query := config.SelectInnerJoinQuery("service_requests", "services", "service_requests.service_id = services.id", "service_requests.id", firstTableColumn, secondTableColumn)
 
 	var request model.ServiceRequest
 	var requestedTime []uint8
 	var scheduledTime []uint8
+	var actualStartTime, actualEndTime []uint8
 
 	// Execute the query
 	err := repo.db.QueryRow(query, requestID).Scan(
 		&request.ID, &request.HouseholderID, &request.HouseholderName, &request.HouseholderAddress,
-		&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus, &request.ServiceName,
+		&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus,
+		&actualStartTime, &actualEndTime, &request.CompletionConfirmed, &request.SeriesID, &request.ServiceName,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -63,6 +70,15 @@ func (repo *ServiceRequestRepository) GetServiceRequestByID(requestID string) (*
 		return nil, fmt.Errorf("%v: %v", errs.ErrorParsingScheduleTime, err)
 	}
 
+	request.ActualStartTime, err = util.ParseNullableTime(actualStartTime)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", errs.ErrorParsingActualTime, err)
+	}
+	request.ActualEndTime, err = util.ParseNullableTime(actualEndTime)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", errs.ErrorParsingActualTime, err)
+	}
+
 	return &request, nil
 }
 
@@ -141,22 +157,42 @@ func (repo *ServiceRequestRepository) GetServiceRequestsByHouseholderID(househol
 	return requests, nil
 }
 
-// UpdateServiceRequest updates an existing service request in MySQL
-func (repo *ServiceRequestRepository) UpdateServiceRequest(updatedRequest *model.ServiceRequest) error {
+// UpdateServiceRequest updates an existing service request in MySQL, provided it still has the status it
+// was read with. A request that moved on in the meantime is left alone and reported as an
+// *errs.StatusTransitionError from its current status.
+func (repo *ServiceRequestRepository) UpdateServiceRequest(updatedRequest *model.ServiceRequest, fromStatus model.RequestStatus) error {
 	query := `
 		UPDATE service_requests 
-		SET householder_id = ?, householder_name = ?, householder_address = ?, service_id = ?, requested_time = ?, scheduled_time = ?, status = ?, approve_status = ? 
-		WHERE id = ?
+		SET householder_id = ?, householder_name = ?, householder_address = ?, service_id = ?, requested_time = ?, scheduled_time = ?, status = ?, approve_status = ?, actual_start_time = ?, actual_end_time = ?, completion_confirmed = ? 
+		WHERE id = ? AND status = ?
 	`
 
-	_, err := repo.db.Exec(query, updatedRequest.HouseholderID, updatedRequest.HouseholderName, updatedRequest.HouseholderAddress, updatedRequest.ServiceID, updatedRequest.RequestedTime, updatedRequest.ScheduledTime, updatedRequest.Status, updatedRequest.ApproveStatus, updatedRequest.ID)
-	return err
+	result, err := repo.db.Exec(query, updatedRequest.HouseholderID, updatedRequest.HouseholderName, updatedRequest.HouseholderAddress, updatedRequest.ServiceID, updatedRequest.RequestedTime, updatedRequest.ScheduledTime, updatedRequest.Status, updatedRequest.ApproveStatus, updatedRequest.ActualStartTime, updatedRequest.ActualEndTime, updatedRequest.CompletionConfirmed, updatedRequest.ID, fromStatus)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+	// MySQL reports no rows for an update that changed nothing, so only a different status is a conflict
+	var current model.RequestStatus
+	if err := repo.db.QueryRow(config.SelectQuery("service_requests", "id", "", []string{"status"}), updatedRequest.ID).Scan(&current); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New(errs.ServiceRequestNotFound)
+		}
+		return err
+	}
+	if current != fromStatus {
+		return &errs.StatusTransitionError{From: string(current), To: string(updatedRequest.Status)}
+	}
+	return nil
 }
 
 // GetAllServiceRequests retrieves all service requests from MySQL
 func (repo *ServiceRequestRepository) GetAllServiceRequests(limit, offset int) ([]model.ServiceRequest, error) {
 	firstTableColumn := []string{"id", "householder_id", "householder_name", "householder_address", "service_id", "requested_time", "scheduled_time", "status", "approve_status", "service_name"}
-	secondTableColumn := []string{"service_provider_id", "name", "contact", "address", "price", "rating", "approve"}
+	secondTableColumn := []string{"service_provider_id", "name", "contact", "address", "price_minor", "currency", "rating", "approve"}
 	query := config.SelectLeftJoinQuery("service_requests", "service_provider_details", "service_requests.id = service_provider_details.service_request_id", "", firstTableColumn, secondTableColumn, limit, offset)
 
 	rows, err := repo.db.Query(query)
@@ -172,7 +208,8 @@ func (repo *ServiceRequestRepository) GetAllServiceRequests(limit, offset int) (
 		var provider model.ServiceProviderDetails
 
 		// Use sql.NullString and other nullable types for fields that may contain NULLs
-		var providerID, providerName, providerContact, providerAddress, providerPrice sql.NullString
+		var providerID, providerName, providerContact, providerAddress, providerCurrency sql.NullString
+		var providerPrice sql.NullInt64
 		var providerRating sql.NullFloat64
 		var providerApprove sql.NullBool
 		var ServiceName sql.NullString
@@ -180,7 +217,7 @@ func (repo *ServiceRequestRepository) GetAllServiceRequests(limit, offset int) (
 		err := rows.Scan(
 			&request.ID, &request.HouseholderID, &request.HouseholderName, &request.HouseholderAddress,
 			&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus, &ServiceName,
-			&providerID, &providerName, &providerContact, &providerAddress, &providerPrice,
+			&providerID, &providerName, &providerContact, &providerAddress, &providerPrice, &providerCurrency,
 			&providerRating, &providerApprove,
 		)
 		if err != nil {
@@ -213,7 +250,7 @@ func (repo *ServiceRequestRepository) GetAllServiceRequests(limit, offset int) (
 			provider.Address = providerAddress.String
 		}
 		if providerPrice.Valid {
-			provider.Price = providerPrice.String
+			provider.Price = model.NewMoney(providerPrice.Int64, providerCurrency.String)
 		}
 		if providerRating.Valid {
 			provider.Rating = providerRating.Float64
@@ -289,8 +326,8 @@ func (repo *ServiceRequestRepository) GetServiceRequestsByProviderID(providerID
 }
 
 func (repo *ServiceRequestRepository) GetServiceProviderByRequestID(requestID, providerID string) (*model.ServiceRequest, error) {
-	firstTableColumn := []string{"id", "householder_id", "householder_name", "householder_address", "service_id", "requested_time", "scheduled_time", "status", "approve_status"}
-	secondTableColumn := []string{"service_provider_id", "name", "contact", "address", "price", "rating", "approve"}
+	firstTableColumn := []string{"id", "householder_id", "householder_name", "householder_address", "service_id", "requested_time", "scheduled_time", "status", "approve_status", "series_id"}
+	secondTableColumn := []string{"service_provider_id", "name", "contact", "address", "price_minor", "currency", "rating", "approve"}
 	query := config.SelectInnerJoinQuery("service_requests", "service_provider_details", "service_requests.id = service_provider_details.service_request_id", "service_provider_details.service_provider_id = ? AND service_requests.id", firstTableColumn, secondTableColumn)
 
 	rows, err := repo.db.Query(query, providerID, requestID)
@@ -309,8 +346,8 @@ func (repo *ServiceRequestRepository) GetServiceProviderByRequestID(requestID, p
 		// Scan the row data
 		err = rows.Scan(
 			&request.ID, &request.HouseholderID, &request.HouseholderName, &request.HouseholderAddress,
-			&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus,
-			&provider.ServiceProviderID, &provider.Name, &provider.Contact, &provider.Address, &provider.Price,
+			&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus, &request.SeriesID,
+			&provider.ServiceProviderID, &provider.Name, &provider.Contact, &provider.Address, &provider.Price.Amount, &provider.Price.Currency,
 			&provider.Rating, &provider.Approve,
 		)
 		if err != nil {
@@ -465,3 +502,52 @@ func (repo *ServiceRequestRepository) GetAllPendingRequestsByProvider(providerId
 
 	return requests, nil
 }
+
+// GetApprovedProviderIDByRequestID returns the provider the householder approved for a request
+func (repo *ServiceRequestRepository) GetApprovedProviderIDByRequestID(requestID string) (string, error) {
+	column := []string{"service_provider_id"}
+	query := config.SelectQuery("service_provider_details", "service_request_id", "approve", column)
+
+	var providerID string
+	err := repo.db.QueryRow(query, requestID, true).Scan(&providerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errors.New(errs.NoApprovedProviderForRequest)
+		}
+		return "", err
+	}
+	return providerID, nil
+}
+
+// GetStaleRequestIDs lists the requests still waiting for approval that are scheduled at or before deadline
+func (repo *ServiceRequestRepository) GetStaleRequestIDs(deadline time.Time) ([]string, error) {
+	rows, err := repo.db.Query(config.StaleRequestIDsQuery(), deadline.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requestIDs []string
+	for rows.Next() {
+		var requestID string
+		if err := rows.Scan(&requestID); err != nil {
+			return nil, err
+		}
+		requestIDs = append(requestIDs, requestID)
+	}
+	return requestIDs, rows.Err()
+}
+
+// ExpireRequest moves an unapproved request from fromStatus to Expired. It reports false, and changes
+// nothing, when the request was approved or changed status in the meantime.
+func (repo *ServiceRequestRepository) ExpireRequest(requestID string, fromStatus model.RequestStatus) (bool, error) {
+	result, err := repo.db.Exec(config.ExpireRequestQuery(), requestID, fromStatus)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository initializes a new SessionRepository with MySQL
+func NewSessionRepository(db *sql.DB) interfaces.SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+var refreshTokenColumns = []string{"token_hash", "session_id", "issued_at", "expires_at", "used_at"}
+
+// CreateSession stores a new session together with its first refresh token
+func (repo *SessionRepository) CreateSession(session *model.Session, refreshToken *model.RefreshToken) (err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	query := config.InsertQuery("auth_sessions", []string{"id", "user_id", "created_at"})
+	if _, err = tx.Exec(query, session.ID, session.UserID, session.CreatedAt.UTC()); err != nil {
+		return err
+	}
+	_, err = tx.Exec(config.InsertQuery("refresh_tokens", refreshTokenColumns), refreshToken.TokenHash, refreshToken.SessionID,
+		refreshToken.IssuedAt.UTC(), refreshToken.ExpiresAt.UTC(), nil)
+	return err
+}
+
+func (repo *SessionRepository) GetSession(sessionID string) (*model.Session, error) {
+	query := config.SelectQuery("auth_sessions", "id", "", []string{"id", "user_id", "created_at", "revoked_at", "revoke_reason"})
+	var session model.Session
+	var createdAt, revokedAt []uint8
+	var reason sql.NullString
+	err := repo.db.QueryRow(query, sessionID).Scan(&session.ID, &session.UserID, &createdAt, &revokedAt, &reason)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.SessionNotFound)
+		}
+		return nil, err
+	}
+	session.RevokeReason = reason.String
+	if session.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if session.RevokedAt, err = util.ParseNullableTime(revokedAt); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (repo *SessionRepository) GetRefreshToken(tokenHash string) (*model.RefreshToken, error) {
+	query := config.SelectQuery("refresh_tokens", "token_hash", "", refreshTokenColumns)
+	var token model.RefreshToken
+	var issuedAt, expiresAt, usedAt []uint8
+	err := repo.db.QueryRow(query, tokenHash).Scan(&token.TokenHash, &token.SessionID, &issuedAt, &expiresAt, &usedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(errs.InvalidRefreshToken)
+		}
+		return nil, err
+	}
+	if token.IssuedAt, err = util.ParseTime(issuedAt); err != nil {
+		return nil, err
+	}
+	if token.ExpiresAt, err = util.ParseTime(expiresAt); err != nil {
+		return nil, err
+	}
+	if token.UsedAt, err = util.ParseNullableTime(usedAt); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken marks a refresh token used and stores the one replacing it, in one transaction. It
+// reports false, and stores nothing, when the token was already used, so a token is exchanged only once
+// however many requests race with it.
+func (repo *SessionRepository) RotateRefreshToken(usedHash string, next *model.RefreshToken) (rotated bool, err error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil || !rotated {
+			tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+	}()
+
+	result, err := tx.Exec(config.UseRefreshTokenQuery(), next.IssuedAt.UTC(), usedHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+	_, err = tx.Exec(config.InsertQuery("refresh_tokens", refreshTokenColumns), next.TokenHash, next.SessionID,
+		next.IssuedAt.UTC(), next.ExpiresAt.UTC(), nil)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RevokeSession ends a session; revoking it again keeps the first revocation
+func (repo *SessionRepository) RevokeSession(sessionID, reason string) error {
+	_, err := repo.db.Exec(config.RevokeSessionsQuery("id"), time.Now().UTC(), reason, sessionID)
+	return err
+}
+
+// RevokeUserSessions ends every session of a user
+func (repo *SessionRepository) RevokeUserSessions(userID, reason string) error {
+	_, err := repo.db.Exec(config.RevokeSessionsQuery("user_id"), time.Now().UTC(), reason, userID)
+	return err
+}
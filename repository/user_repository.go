@@ -8,6 +8,7 @@ import (
 	"serviceNest/errs"
 	"serviceNest/interfaces"
 	"serviceNest/model"
+	"serviceNest/util"
 )
 
 type UserRepository struct {
@@ -49,31 +50,60 @@ func (repo *UserRepository) UpdateUser(updatedUser *model.User) error {
 	if err == nil && existingUser.ID != updatedUser.ID {
 		return fmt.Errorf(errs.EmailAlreadyUse)
 	}
-	column := []string{"name", "email", "password", "role", "address", "contact"}
+	column := []string{"name", "email", "password", "role", "address", "contact", "timezone"}
 	query := config.UpdateQuery("users", "id", "", column)
 
-	_, err = repo.db.Exec(query, updatedUser.Name, updatedUser.Email, updatedUser.Password, updatedUser.Role, updatedUser.Address, updatedUser.Contact, updatedUser.ID)
+	var timezone *string
+	if updatedUser.Timezone != "" {
+		timezone = &updatedUser.Timezone
+	}
+	_, err = repo.db.Exec(query, updatedUser.Name, updatedUser.Email, updatedUser.Password, updatedUser.Role, updatedUser.Address, updatedUser.Contact, timezone, updatedUser.ID)
 	return err
 }
 
 func (repo *UserRepository) GetUserByID(userID string) (*model.User, error) {
-	column := []string{"id", "name", "email", "password", "role", "address", "contact"}
+	column := []string{"id", "name", "email", "password", "role", "address", "contact", "pincode", "latitude", "longitude", "timezone"}
 	query := config.SelectQuery("users", "id", "", column)
 
 	row := repo.db.QueryRow(query, userID)
 
 	var user model.User
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Address, &user.Contact)
+	var pincode, timezone sql.NullString
+	var latitude, longitude sql.NullFloat64
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Address, &user.Contact, &pincode, &latitude, &longitude, &timezone)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf(errs.UserNotFound)
 		}
 		return nil, err
 	}
+	user.Pincode = pincode.String
+	user.Timezone = timezone.String
+	user.Latitude = util.NullableFloat(latitude)
+	user.Longitude = util.NullableFloat(longitude)
 
 	return &user, nil
 }
 
+// UpdateUserLocation stores the pincode and resolved coordinates of a user
+func (repo *UserRepository) UpdateUserLocation(userID, pincode string, latitude, longitude float64) error {
+	column := []string{"pincode", "latitude", "longitude"}
+	query := config.UpdateQuery("users", "id", "", column)
+
+	result, err := repo.db.Exec(query, pincode, latitude, longitude, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New(errs.UserNotFound)
+	}
+	return nil
+}
+
 func (repo *UserRepository) DeActivateUser(userID string) error {
 	column := []string{"is_active"}
 	query := config.UpdateQuery("users", "id", "", column)
@@ -0,0 +1,10 @@
+package response
+
+// Error codes carried in the error_code field of failed responses that the long-standing 1001-1009 and
+// 2001-2002 codes do not cover
+const (
+	// ErrCodeScheduleConflict means the job overlaps another job the provider has committed to
+	ErrCodeScheduleConflict = 1010
+	// ErrCodeLockedOut means the account or IP address is locked out after too many failed attempts
+	ErrCodeLockedOut = 1011
+)
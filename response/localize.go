@@ -0,0 +1,87 @@
+package response
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// localizedWriter carries the timezone success payloads are rendered in
+type localizedWriter struct {
+	http.ResponseWriter
+	location *time.Location
+}
+
+// WithLocation returns a writer for which SuccessResponse renders every time.Time in data in location
+func WithLocation(w http.ResponseWriter, location *time.Location) http.ResponseWriter {
+	return &localizedWriter{ResponseWriter: w, location: location}
+}
+
+func locationOf(w http.ResponseWriter) *time.Location {
+	if lw, ok := w.(*localizedWriter); ok {
+		return lw.location
+	}
+	return nil
+}
+
+// localizeTimes returns a copy of data with every reachable time.Time converted to location
+func localizeTimes(data interface{}, location *time.Location) interface{} {
+	if data == nil {
+		return nil
+	}
+	return localizeValue(reflect.ValueOf(data), location).Interface()
+}
+
+func localizeValue(v reflect.Value, location *time.Location) reflect.Value {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return reflect.ValueOf(v.Interface().(time.Time).In(location))
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(localizeValue(v.Field(i), location))
+			}
+		}
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(localizeValue(v.Elem(), location))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(localizeValue(v.Index(i), location))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), localizeValue(iter.Value(), location))
+		}
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(localizeValue(v.Elem(), location))
+		return out
+	default:
+		return v
+	}
+}
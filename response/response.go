@@ -14,6 +14,9 @@ type Response struct {
 
 // SuccessResponse generates a standard success response
 func SuccessResponse(w http.ResponseWriter, data interface{}, message string, code int) {
+	if location := locationOf(w); location != nil {
+		data = localizeTimes(data, location)
+	}
 	response := Response{
 		Status:  "Success",
 		Message: message,
@@ -9,11 +9,11 @@ import (
 	"serviceNest/response"
 )
 
-func SetupRouter(userService interfaces.UserService, householderService interfaces.HouseholderService, providerService interfaces.ServiceProviderService, adminService interfaces.AdminService) *mux.Router {
+func SetupRouter(userService interfaces.UserService, householderService interfaces.HouseholderService, providerService interfaces.ServiceProviderService, adminService interfaces.AdminService, paymentService interfaces.PaymentService, invoiceService interfaces.InvoiceService, ledgerService interfaces.LedgerService, disputeService interfaces.DisputeService, authService interfaces.AuthService, throttleService interfaces.AuthThrottleService) *mux.Router {
 	r := mux.NewRouter()
 	r.Use(middlewares.LoggingMiddleware)
 	// Public Routes
-	userController := controllers.NewUserController(userService)
+	userController := controllers.NewUserController(userService, authService, throttleService)
 
 	r.HandleFunc("/signup", userController.SignupUser).Methods("POST")
 
@@ -24,41 +24,110 @@ func SetupRouter(userService interfaces.UserService, householderService interfac
 	r.HandleFunc("/forgot", userController.VerifyOtpAndUpdatePassword).Methods("PUT")
 
 	r.HandleFunc("/otp", userController.GenerateOtp).Methods("POST")
+
+	r.HandleFunc("/token/refresh", userController.RefreshToken).Methods("POST")
+
+	r.HandleFunc("/.well-known/jwks.json", controllers.JWKSHandler).Methods("GET")
+
+	r.Handle("/logout", middlewares.AuthMiddleware(authService)(http.HandlerFunc(userController.Logout))).Methods("POST")
 	// Protected Routes (JWT authentication required)
 	api := r.PathPrefix("/api").Subrouter()
 
+	// Payment gateway callbacks are signed by the gateway instead of carrying a JWT. Without a payment
+	// service payments are disabled and none of their routes exist.
+	paymentController := controllers.NewPaymentController(paymentService)
+	if paymentService != nil {
+		api.HandleFunc("/payments/webhook", paymentController.PaymentWebhook).Methods("POST")
+	}
+
 	// User routes
 
 	userRoutes := api.PathPrefix("/user").Subrouter()
-	userRoutes.Use(middlewares.AuthMiddleware)
+	userRoutes.Use(middlewares.AuthMiddleware(authService))
+	userRoutes.Use(middlewares.TimezoneMiddleware(userService))
 	userRoutes.HandleFunc("/profile", userController.ViewProfileByIDHandler).Methods("GET")
 	userRoutes.HandleFunc("/profile", userController.UpdateUserHandler).Methods("PUT")
+	userRoutes.HandleFunc("/profile/location", userController.UpdateLocationHandler).Methods("PUT")
 
 	// Householder routes connected to admin
 	householderController := controllers.NewHouseholderController(householderService)
 	userRoutes.HandleFunc("/categories", householderController.GetAllServiceCategories).Methods("GET")
 
+	userRoutes.HandleFunc("/services/search", householderController.SearchServices).Methods("GET")
+
 	userRoutes.HandleFunc("/services/request", householderController.RequestService).Methods("POST")
 
 	userRoutes.HandleFunc("/services/request", householderController.RescheduleServiceRequest).Methods("PUT")
 
 	userRoutes.HandleFunc("/services/request/{request_id}", householderController.CancelServiceRequest).Methods("PATCH")
 
+	userRoutes.HandleFunc("/services/request/{request_id}/cancellation", householderController.ViewCancellationTerms).Methods("GET")
+
+	userRoutes.HandleFunc("/services/request/{request_id}/history", householderController.ViewServiceRequestHistory).Methods("GET")
+
+	userRoutes.HandleFunc("/services/request/{request_id}/quotes", householderController.ViewRequestQuotes).Methods("GET")
+
+	userRoutes.HandleFunc("/services/request/{request_id}/quotes/counter", householderController.CounterQuote).Methods("POST")
+
+	if paymentService != nil {
+		userRoutes.HandleFunc("/services/request/{request_id}/payments", paymentController.PayForRequest).Methods("POST")
+
+		userRoutes.HandleFunc("/services/request/{request_id}/payments", paymentController.ViewRequestPayments).Methods("GET")
+	}
+
+	disputeController := controllers.NewDisputeController(disputeService)
+	userRoutes.HandleFunc("/services/request/{request_id}/disputes", disputeController.OpenDispute).Methods("POST")
+
+	userRoutes.HandleFunc("/services/request/{request_id}/disputes", disputeController.ViewRequestDisputes).Methods("GET")
+
+	userRoutes.HandleFunc("/disputes/{dispute_id}", disputeController.ViewDispute).Methods("GET")
+
+	userRoutes.HandleFunc("/disputes/{dispute_id}/responses", disputeController.RespondToDispute).Methods("POST")
+
+	userRoutes.HandleFunc("/services/request/{request_id}/confirm", householderController.ConfirmServiceCompletion).Methods("PUT")
+
 	userRoutes.HandleFunc("/bookings", householderController.ViewBookingHistory).Methods("GET")
 
+	invoiceController := controllers.NewInvoiceController(invoiceService)
+	userRoutes.HandleFunc("/bookings/{id}/invoice", invoiceController.ViewInvoice).Methods("GET")
+
+	userRoutes.HandleFunc("/bookings/series", householderController.CreateBookingSeries).Methods("POST")
+
+	userRoutes.HandleFunc("/bookings/series", householderController.ViewBookingSeries).Methods("GET")
+
+	userRoutes.HandleFunc("/bookings/series/{series_id}", householderController.ViewBookingSeriesByID).Methods("GET")
+
+	userRoutes.HandleFunc("/bookings/series/{series_id}/skip", householderController.SkipSeriesOccurrence).Methods("POST")
+
+	userRoutes.HandleFunc("/bookings/series/{series_id}/pause", householderController.PauseBookingSeries).Methods("PUT")
+
+	userRoutes.HandleFunc("/bookings/series/{series_id}/resume", householderController.ResumeBookingSeries).Methods("PUT")
+
+	userRoutes.HandleFunc("/bookings/series/{series_id}/cancel", householderController.CancelBookingSeries).Methods("PUT")
+
+	userRoutes.HandleFunc("/providers/search", householderController.SearchProviders).Methods("GET")
+
+	userRoutes.HandleFunc("/providers/{provider_id}/slots", householderController.GetProviderSlots).Methods("GET")
+
 	userRoutes.HandleFunc("/services/request/approve", householderController.ApproveRequest).Methods("PUT")
 
 	householderRoutes := api.PathPrefix("/householder").Subrouter()
-	householderRoutes.Use(middlewares.AuthMiddleware)
+	householderRoutes.Use(middlewares.AuthMiddleware(authService))
+	householderRoutes.Use(middlewares.TimezoneMiddleware(userService))
 	householderRoutes.Use(middlewares.HouseHolderAuthMiddleware)
 
 	householderRoutes.HandleFunc("/review", householderController.LeaveReview).Methods("POST")
 
+	householderRoutes.HandleFunc("/review/{review_id}", householderController.UpdateReview).Methods("PUT")
+
+	householderRoutes.HandleFunc("/review/{review_id}", householderController.DeleteReview).Methods("DELETE")
+
 	// Service provider routes
 	serviceProviderController := controllers.NewServiceProviderController(providerService)
 
 	providerRoutes := api.PathPrefix("/provider").Subrouter()
-	providerRoutes.Use(middlewares.AuthMiddleware)
+	providerRoutes.Use(middlewares.AuthMiddleware(authService))
+	providerRoutes.Use(middlewares.TimezoneMiddleware(userService))
 	providerRoutes.Use(middlewares.ServiceProviderAuthMiddleware)
 
 	providerRoutes.HandleFunc("/service", serviceProviderController.AddService).Methods("POST")
@@ -73,7 +142,32 @@ func SetupRouter(userService interfaces.UserService, householderService interfac
 
 	providerRoutes.HandleFunc("/service/requests", serviceProviderController.AcceptServiceRequest).Methods("POST")
 
+	providerRoutes.HandleFunc("/service/requests/{request_id}/quotes", serviceProviderController.ReviseQuote).Methods("POST")
+
+	providerRoutes.HandleFunc("/service/requests/{request_id}/start", serviceProviderController.StartServiceRequest).Methods("POST")
+
+	providerRoutes.HandleFunc("/service/requests/{request_id}/finish", serviceProviderController.CompleteServiceRequest).Methods("POST")
+
+	providerRoutes.HandleFunc("/service/requests/{request_id}/cancel", serviceProviderController.CancelApprovedRequest).Methods("POST")
+
+	providerRoutes.HandleFunc("/service-radius", serviceProviderController.UpdateServiceRadius).Methods("PUT")
+
+	providerRoutes.HandleFunc("/availability", serviceProviderController.UpdateAvailability).Methods("PUT")
+
+	providerRoutes.HandleFunc("/schedule", serviceProviderController.GetSchedule).Methods("GET")
+
+	providerRoutes.HandleFunc("/schedule", serviceProviderController.UpdateSchedule).Methods("PUT")
+
+	providerRoutes.HandleFunc("/time-off", serviceProviderController.AddTimeOff).Methods("POST")
+
+	providerRoutes.HandleFunc("/time-off/{time_off_id}", serviceProviderController.RemoveTimeOff).Methods("DELETE")
+
 	providerRoutes.HandleFunc("/reviews", serviceProviderController.ViewReviews).Methods("GET")
+
+	providerRoutes.HandleFunc("/reviews/{review_id}/reply", serviceProviderController.ReplyToReview).Methods("POST")
+
+	ledgerController := controllers.NewLedgerController(ledgerService)
+	providerRoutes.HandleFunc("/earnings", ledgerController.ViewEarnings).Methods("GET")
 	userRoutes.HandleFunc("/service/request/approved", func(w http.ResponseWriter, r *http.Request) {
 		// Get role from context
 		role, ok := r.Context().Value("role").(string)
@@ -95,7 +189,8 @@ func SetupRouter(userService interfaces.UserService, householderService interfac
 
 	//admin routes
 	adminRoutes := api.PathPrefix("/admin").Subrouter()
-	adminRoutes.Use(middlewares.AuthMiddleware)
+	adminRoutes.Use(middlewares.AuthMiddleware(authService))
+	adminRoutes.Use(middlewares.TimezoneMiddleware(userService))
 	adminRoutes.Use(middlewares.AdminAuthMiddleware)
 
 	// Initialize AdminController
@@ -108,6 +203,19 @@ func SetupRouter(userService interfaces.UserService, householderService interfac
 	adminRoutes.HandleFunc("/deactivate/{providerID}", adminController.DeactivateUserAccount).Methods("PATCH")
 	adminRoutes.HandleFunc("/service", adminController.AddService).Methods("POST")
 	adminRoutes.HandleFunc("/users/{userEmail}", adminController.ViewUserDetail).Methods("GET")
+	adminRoutes.HandleFunc("/ratings/recompute", adminController.RecomputeRatings).Methods("POST")
+	if paymentService != nil {
+		adminRoutes.HandleFunc("/payments/{payment_id}/refund", paymentController.RefundPayment).Methods("POST")
+	}
+	adminRoutes.HandleFunc("/payouts/batches", ledgerController.CreatePayoutBatch).Methods("POST")
+	adminRoutes.HandleFunc("/payouts/batches/{batch_id}", ledgerController.DownloadPayoutBatch).Methods("GET")
+	adminRoutes.HandleFunc("/disputes", disputeController.ViewDisputeQueue).Methods("GET")
+	adminRoutes.HandleFunc("/disputes/{dispute_id}/review", disputeController.ReviewDispute).Methods("PUT")
+	adminRoutes.HandleFunc("/disputes/{dispute_id}/resolve", disputeController.ResolveDispute).Methods("POST")
+	adminRoutes.HandleFunc("/cancellation-policies", adminController.ViewCancellationPolicies).Methods("GET")
+	adminRoutes.HandleFunc("/cancellation-policies", adminController.SaveCancellationPolicy).Methods("PUT")
+	adminRoutes.HandleFunc("/cancellation-policies/{policy_id}", adminController.DeleteCancellationPolicy).Methods("DELETE")
+	adminRoutes.HandleFunc("/auth-failures", userController.ViewFailedAuthAttempts).Methods("GET")
 	// Get available service for admin and householder
 	userRoutes.HandleFunc("/services", func(w http.ResponseWriter, r *http.Request) {
 		role, ok := r.Context().Value("role").(string)
@@ -2,9 +2,11 @@ package service
 
 import (
 	"errors"
+	"serviceNest/errs"
 	"serviceNest/interfaces"
 	"serviceNest/model"
 	"serviceNest/util"
+	"time"
 )
 
 type AdminService struct {
@@ -13,14 +15,20 @@ type AdminService struct {
 	householderRepo    interfaces.HouseholderRepository
 	providerRepo       interfaces.ServiceProviderRepository
 	serviceRequestRepo interfaces.ServiceRequestRepository
+	ratingRepo         interfaces.RatingRepository
+	cancellationRepo   interfaces.CancellationRepository
+	sessionRepo        interfaces.SessionRepository
 }
 
-func NewAdminService(serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, userRepo interfaces.UserRepository, providerRepo interfaces.ServiceProviderRepository) interfaces.AdminService {
+func NewAdminService(serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, userRepo interfaces.UserRepository, providerRepo interfaces.ServiceProviderRepository, ratingRepo interfaces.RatingRepository, cancellationRepo interfaces.CancellationRepository, sessionRepo interfaces.SessionRepository) interfaces.AdminService {
 	return &AdminService{
 		serviceRepo:        serviceRepo,
 		userRepo:           userRepo,
 		providerRepo:       providerRepo,
 		serviceRequestRepo: serviceRequestRepo,
+		ratingRepo:         ratingRepo,
+		cancellationRepo:   cancellationRepo,
+		sessionRepo:        sessionRepo,
 	}
 }
 
@@ -48,6 +56,11 @@ func (s *AdminService) DeactivateAccount(userID string) error {
 	if err != nil {
 		return err
 	}
+	// Cut off API access straight away instead of when the provider's tokens expire
+	err = revokeSessions(s.sessionRepo, userID, "user deactivated")
+	if err != nil {
+		return err
+	}
 
 	// Delete all services associated with the user
 	err = s.providerRepo.DeleteServicesByProviderID(userID)
@@ -85,3 +98,47 @@ func (s *AdminService) GetUserByEmail(email string) (*model.User, error) {
 	}
 	return user, nil
 }
+
+// RecomputeRatings rebuilds all stored service and provider ratings from the reviews
+func (s *AdminService) RecomputeRatings() error {
+	return s.ratingRepo.RecalculateAllRatings()
+}
+
+// GetCancellationPolicies lists the cancellation policies set for providers and categories
+func (s *AdminService) GetCancellationPolicies() ([]model.CancellationPolicy, error) {
+	return s.cancellationRepo.GetPolicies()
+}
+
+// SaveCancellationPolicy sets the cancellation policy of a provider or a category, replacing the one it
+// had, and returns the policy as stored
+func (s *AdminService) SaveCancellationPolicy(policy *model.CancellationPolicy) (*model.CancellationPolicy, error) {
+	if err := util.ValidateCancellationPolicy(policy); err != nil {
+		return nil, err
+	}
+	switch policy.Scope {
+	case model.PolicyScopeProvider:
+		if _, err := s.providerRepo.GetProviderByID(policy.ScopeID); err != nil {
+			return nil, err
+		}
+	case model.PolicyScopeCategory:
+		exists, err := s.serviceRepo.CategoryExists(policy.ScopeID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.New(errs.CategoryNotFound)
+		}
+	}
+
+	policy.ID = util.GenerateUUID()
+	policy.UpdatedAt = time.Now().UTC()
+	if err := s.cancellationRepo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+	return s.cancellationRepo.GetPolicy(policy.Scope, policy.ScopeID)
+}
+
+// DeleteCancellationPolicy removes a policy; its bookings fall back to the category or default policy
+func (s *AdminService) DeleteCancellationPolicy(policyID string) error {
+	return s.cancellationRepo.DeletePolicy(policyID)
+}
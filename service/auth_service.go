@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type AuthService struct {
+	sessionRepo interfaces.SessionRepository
+	userRepo    interfaces.UserRepository
+}
+
+// NewAuthService initializes a new AuthService that keeps login sessions in the given repository
+func NewAuthService(sessionRepo interfaces.SessionRepository, userRepo interfaces.UserRepository) interfaces.AuthService {
+	return &AuthService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// StartSession opens a session for a user who just logged in and returns its first tokens
+func (s *AuthService) StartSession(user *model.User) (*model.TokenPair, error) {
+	if !user.IsActive {
+		return nil, errors.New(errs.UserDeactivated)
+	}
+	now := time.Now().UTC()
+	session := &model.Session{
+		ID:        util.GenerateUUID(),
+		UserID:    user.ID,
+		CreatedAt: now,
+	}
+	refreshToken, refresh, err := newRefreshToken(session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.CreateSession(session, refresh); err != nil {
+		return nil, err
+	}
+	return issueTokenPair(user, session.ID, refreshToken, refresh, now)
+}
+
+// RefreshSession exchanges a refresh token for a new access token and refresh token. Each refresh token
+// works once: presenting one again means it was stolen or replayed, so the whole session is revoked.
+func (s *AuthService) RefreshSession(refreshToken string) (*model.TokenPair, error) {
+	usedHash := util.HashToken(refreshToken)
+	used, err := s.sessionRepo.GetRefreshToken(usedHash)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.sessionRepo.GetSession(used.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.RevokedAt != nil {
+		return nil, errors.New(errs.InvalidRefreshToken)
+	}
+	now := time.Now().UTC()
+	if used.UsedAt != nil {
+		s.revokeReusedSession(session)
+		return nil, errors.New(errs.InvalidRefreshToken)
+	}
+	if !now.Before(used.ExpiresAt) {
+		return nil, errors.New(errs.InvalidRefreshToken)
+	}
+
+	user, err := s.userRepo.GetUserByID(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		if err := s.sessionRepo.RevokeUserSessions(user.ID, "user deactivated"); err != nil {
+			return nil, err
+		}
+		return nil, errors.New(errs.UserDeactivated)
+	}
+
+	nextToken, next, err := newRefreshToken(session.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.sessionRepo.RotateRefreshToken(usedHash, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request exchanged the same token first
+		s.revokeReusedSession(session)
+		return nil, errors.New(errs.InvalidRefreshToken)
+	}
+	return issueTokenPair(user, session.ID, nextToken, next, now)
+}
+
+// EndSession logs a session out; its access tokens stop working straight away
+func (s *AuthService) EndSession(sessionID string) error {
+	return s.sessionRepo.RevokeSession(sessionID, "logged out")
+}
+
+// IsSessionRevoked reports whether access tokens of the session must be refused. Unknown sessions count
+// as revoked.
+func (s *AuthService) IsSessionRevoked(sessionID string) (bool, error) {
+	session, err := s.sessionRepo.GetSession(sessionID)
+	if err != nil {
+		if err.Error() == errs.SessionNotFound {
+			return true, nil
+		}
+		return false, err
+	}
+	return session.RevokedAt != nil, nil
+}
+
+func (s *AuthService) revokeReusedSession(session *model.Session) {
+	logger.Error("Refresh token reused, revoking session", map[string]interface{}{"sessionID": session.ID, "userID": session.UserID})
+	if err := s.sessionRepo.RevokeSession(session.ID, "refresh token reused"); err != nil {
+		logger.Error("Error revoking session", map[string]interface{}{"sessionID": session.ID, "error": err.Error()})
+	}
+}
+
+// newRefreshToken creates a refresh token for the session, returning the token for the client and the
+// record, holding only its hash, to store
+func newRefreshToken(sessionID string, now time.Time) (string, *model.RefreshToken, error) {
+	token, err := util.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &model.RefreshToken{
+		TokenHash: util.HashToken(token),
+		SessionID: sessionID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(config.REFRESH_TOKEN_TTL),
+	}, nil
+}
+
+func issueTokenPair(user *model.User, sessionID, refreshToken string, refresh *model.RefreshToken, now time.Time) (*model.TokenPair, error) {
+	accessToken, err := util.GenerateJWT(user.ID, user.Role, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TokenPair{
+		AccessToken:      accessToken,
+		ExpiresAt:        now.Add(config.ACCESS_TOKEN_TTL),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refresh.ExpiresAt,
+	}, nil
+}
+
+// revokeSessions ends every session of a user after a change that must log them out everywhere, such as a
+// new password or deactivation
+func revokeSessions(sessionRepo interfaces.SessionRepository, userID, reason string) error {
+	if err := sessionRepo.RevokeUserSessions(userID, reason); err != nil {
+		logger.Error("Error revoking sessions", map[string]interface{}{"userID": userID, "error": err.Error()})
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type AuthThrottleService struct {
+	throttleRepo interfaces.AuthThrottleRepository
+}
+
+// NewAuthThrottleService initializes a new AuthThrottleService that counts failed credential checks in the
+// given repository
+func NewAuthThrottleService(throttleRepo interfaces.AuthThrottleRepository) interfaces.AuthThrottleService {
+	return &AuthThrottleService{throttleRepo: throttleRepo}
+}
+
+// CheckLockout returns an *errs.LockoutError while the account or the IP address is locked out. It is
+// called before credentials are checked, so a locked out caller learns nothing about them.
+func (s *AuthThrottleService) CheckLockout(email, ip string) error {
+	now := time.Now().UTC()
+	var until *time.Time
+	for _, subject := range throttleSubjects(email, ip) {
+		throttle, err := s.throttleRepo.GetThrottle(subject.subject, subject.key)
+		if err != nil {
+			return err
+		}
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) && (until == nil || throttle.LockedUntil.After(*until)) {
+			until = throttle.LockedUntil
+		}
+	}
+	if until != nil {
+		return &errs.LockoutError{Until: *until}
+	}
+	return nil
+}
+
+// BeginAttempt counts a credential check against both the account and the IP address before it runs, and
+// returns an *errs.LockoutError instead while either is locked out. As the count goes up first, concurrent
+// guesses cannot all pass the check before any of them failed: an attempt past the threshold locks out
+// straight away, as its failure would, and of concurrent ones only the attempt that set the lockout goes
+// ahead. Every attempt that began ends in RecordFailure, RecordSuccess or ReleaseAttempt.
+func (s *AuthThrottleService) BeginAttempt(email, ip string) error {
+	if err := s.CheckLockout(email, ip); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	subjects := throttleSubjects(email, ip)
+	for i, subject := range subjects {
+		throttle, err := s.throttleRepo.RecordFailure(subject.subject, subject.key, now, now.Add(-config.FAILED_ATTEMPT_RESET_WINDOW))
+		if err != nil {
+			s.logCreditError(s.creditAttempt(subjects[:i]))
+			return err
+		}
+		if throttle.Failures <= subject.threshold {
+			continue
+		}
+		lockout := util.LockoutDuration(throttle.Failures, subject.threshold, config.LOCKOUT_BASE_DURATION, config.LOCKOUT_MAX_DURATION)
+		claimed, err := s.throttleRepo.ClaimLock(subject.subject, subject.key, now, now.Add(lockout))
+		if err == nil && claimed {
+			continue
+		}
+		// The attempt is refused without checking anything, so it does not count
+		s.logCreditError(s.creditAttempt(subjects[:i+1]))
+		if err != nil {
+			return err
+		}
+		if err := s.CheckLockout(email, ip); err != nil {
+			return err
+		}
+		return &errs.LockoutError{Until: now.Add(lockout)}
+	}
+	return nil
+}
+
+// RecordFailure writes a failed attempt to the audit log and locks out the account or the IP address once
+// the attempts BeginAttempt counted against it are past its threshold. The lockout does not depend on the
+// audit log being written.
+func (s *AuthThrottleService) RecordFailure(action model.AuthAction, email, ip, reason string) error {
+	now := time.Now().UTC()
+	attempt := &model.FailedAuthAttempt{
+		ID:        util.GenerateUUID(),
+		Action:    action,
+		Email:     util.ThrottleKey(email),
+		IP:        ip,
+		Reason:    reason,
+		CreatedAt: now,
+	}
+	auditErr := s.throttleRepo.SaveFailedAttempt(attempt)
+
+	for _, subject := range throttleSubjects(email, ip) {
+		throttle, err := s.throttleRepo.GetThrottle(subject.subject, subject.key)
+		if err != nil {
+			return err
+		}
+		lockout := util.LockoutDuration(throttle.Failures, subject.threshold, config.LOCKOUT_BASE_DURATION, config.LOCKOUT_MAX_DURATION)
+		if lockout == 0 || (throttle.LockedUntil != nil && !throttle.LockedUntil.Before(now.Add(lockout))) {
+			continue
+		}
+		if err := s.throttleRepo.LockUntil(subject.subject, subject.key, now.Add(lockout)); err != nil {
+			return err
+		}
+		logger.Info("Locked out after failed attempts", map[string]interface{}{"subject": subject.subject, "key": subject.key,
+			"failures": throttle.Failures, "lockout": lockout.String()})
+	}
+	return auditErr
+}
+
+// RecordSuccess clears the failures of an account once its owner proved who they are. The IP address
+// only gets the successful attempt back, as one success there says nothing about the other accounts tried
+// from it.
+func (s *AuthThrottleService) RecordSuccess(email, ip string) error {
+	if err := s.throttleRepo.ResetThrottle(model.ThrottleAccount, util.ThrottleKey(email)); err != nil {
+		return err
+	}
+	return s.throttleRepo.CreditFailure(model.ThrottleIP, ip)
+}
+
+// ReleaseAttempt takes back an attempt that ended without the credentials being found right or wrong,
+// such as one that hit an internal error
+func (s *AuthThrottleService) ReleaseAttempt(email, ip string) error {
+	return s.creditAttempt(throttleSubjects(email, ip))
+}
+
+// creditAttempt takes an attempt back from every subject, even if crediting one of them fails
+func (s *AuthThrottleService) creditAttempt(subjects []throttleSubject) error {
+	var firstErr error
+	for _, subject := range subjects {
+		if err := s.throttleRepo.CreditFailure(subject.subject, subject.key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (s *AuthThrottleService) logCreditError(err error) {
+	if err != nil {
+		logger.Error("Error crediting back attempt", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// GetFailedAttempts returns the audit log of failed attempts, newest first, optionally for one email
+func (s *AuthThrottleService) GetFailedAttempts(email string, limit, offset int) ([]model.FailedAuthAttempt, error) {
+	return s.throttleRepo.GetFailedAttempts(util.ThrottleKey(email), limit, offset)
+}
+
+type throttleSubject struct {
+	subject   model.ThrottleSubject
+	key       string
+	threshold int
+}
+
+func throttleSubjects(email, ip string) []throttleSubject {
+	return []throttleSubject{
+		{model.ThrottleAccount, util.ThrottleKey(email), config.ACCOUNT_LOCKOUT_THRESHOLD},
+		{model.ThrottleIP, ip, config.IP_LOCKOUT_THRESHOLD},
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+// providerSlots lists the provider's slots on the calendar day of date, taken in the provider's schedule
+// timezone, with their availability
+func providerSlots(availabilityRepo interfaces.AvailabilityRepository, providerID string, date time.Time) ([]model.Slot, error) {
+	schedule, err := availabilityRepo.GetSchedule(providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, util.ScheduleLocation(schedule))
+	// No job runs longer than a day, so earlier bookings are the only ones that can reach into this day
+	booked, err := availabilityRepo.GetBookedRanges(providerID, dayStart.Add(-24*time.Hour), dayStart.AddDate(0, 0, 1), "")
+	if err != nil {
+		return nil, err
+	}
+	return util.GenerateSlots(schedule, dayStart, booked, time.Now()), nil
+}
+
+// providerFreeAt reports whether a job of the service starting at start fits the provider's schedule.
+// The job lasts its estimated duration, as in the conflict check. excludeRequestID is ignored when
+// looking for clashing bookings.
+func providerFreeAt(availabilityRepo interfaces.AvailabilityRepository, providerID, serviceID string, start time.Time, excludeRequestID string) (bool, error) {
+	schedule, err := availabilityRepo.GetSchedule(providerID)
+	if err != nil {
+		return false, err
+	}
+	minutes, err := availabilityRepo.GetEstimatedMinutes(providerID, serviceID)
+	if err != nil {
+		return false, err
+	}
+
+	job := model.TimeRange{Start: start, End: start.Add(util.JobDuration(minutes))}
+	booked, err := availabilityRepo.GetBookedRanges(providerID, start.Add(-24*time.Hour), job.End, excludeRequestID)
+	if err != nil {
+		return false, err
+	}
+	return util.FitsSchedule(schedule, job, booked), nil
+}
+
+// anyProviderFreeAt reports whether at least one active provider of the service has a free slot at start
+func anyProviderFreeAt(availabilityRepo interfaces.AvailabilityRepository, serviceID string, start time.Time, excludeRequestID string) (bool, error) {
+	providerIDs, err := availabilityRepo.GetProviderIDsByServiceID(serviceID)
+	if err != nil {
+		return false, err
+	}
+	for _, providerID := range providerIDs {
+		free, err := providerFreeAt(availabilityRepo, providerID, serviceID, start, excludeRequestID)
+		if err != nil {
+			return false, err
+		}
+		if free {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkScheduleConflicts looks for jobs of the provider that clash with requestID starting at start.
+// Outright overlaps fail with *errs.ScheduleConflictError; jobs closer than the travel buffer are
+// returned as warnings. Callers should hold the provider lock while checking and saving.
+func checkScheduleConflicts(availabilityRepo interfaces.AvailabilityRepository, providerID, serviceID, requestID string, start time.Time) ([]model.ScheduleConflict, error) {
+	minutes, err := availabilityRepo.GetEstimatedMinutes(providerID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	job := model.TimeRange{Start: start, End: start.Add(util.JobDuration(minutes))}
+
+	// No job runs longer than a day, so earlier bookings cannot reach into this one
+	bookings, err := availabilityRepo.GetCommitments(providerID, job.Start.Add(-24*time.Hour-config.TRAVEL_BUFFER), job.End.Add(config.TRAVEL_BUFFER), requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlapping []string
+	warnings := []model.ScheduleConflict{}
+	for _, conflict := range util.FindConflicts(job, bookings, config.TRAVEL_BUFFER) {
+		if conflict.Overlap {
+			overlapping = append(overlapping, conflict.RequestID)
+		} else {
+			warnings = append(warnings, conflict)
+		}
+	}
+	if len(overlapping) > 0 {
+		return nil, &errs.ScheduleConflictError{RequestIDs: overlapping}
+	}
+	return warnings, nil
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+// CreateBookingSeries sets up a recurring booking and materialises its first occurrences. Occurrences
+// keep the wall-clock time of firstTime in the given timezone.
+func (s *HouseholderService) CreateBookingSeries(householderID, serviceName, category, description string, firstTime time.Time, timezone string, rule model.RecurrenceRule) (string, error) {
+	if err := util.ValidateRecurrenceRule(rule, firstTime); err != nil {
+		return "", err
+	}
+	if !util.ValidTimezone(timezone) {
+		return "", errors.New(errs.InvalidTimezone)
+	}
+
+	serviceID, err := s.serviceRepo.GetServiceIdByCategory(category)
+	if err != nil {
+		return "", err
+	}
+	if serviceID == nil {
+		return "", errors.New("service category does not exist")
+	}
+
+	// As for a one-off request, some provider must be able to take the first occurrence
+	free, err := anyProviderFreeAt(s.availabilityRepo, *serviceID, firstTime, "")
+	if err != nil {
+		return "", err
+	}
+	if !free {
+		return "", errors.New(errs.NoFreeSlot)
+	}
+
+	if _, err := s.householderRepo.GetHouseholderByID(householderID); err != nil {
+		return "", err
+	}
+
+	series := &model.BookingSeries{
+		ID:            util.GenerateUUID(),
+		HouseholderID: householderID,
+		ServiceID:     *serviceID,
+		ServiceName:   serviceName,
+		Description:   description,
+		Rule:          rule,
+		FirstTime:     firstTime.UTC(),
+		Timezone:      timezone,
+		Status:        model.SeriesActive,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := s.seriesRepo.SaveSeries(series); err != nil {
+		return "", err
+	}
+	if err := s.generateSeriesOccurrences(series, time.Now()); err != nil {
+		return "", err
+	}
+	return series.ID, nil
+}
+
+// ViewBookingSeries lists the householder's booking series, newest first
+func (s *HouseholderService) ViewBookingSeries(householderID string) ([]model.BookingSeries, error) {
+	return s.seriesRepo.GetSeriesByHouseholderID(householderID)
+}
+
+// GetBookingSeries returns one of the householder's booking series with its next planned occurrences
+func (s *HouseholderService) GetBookingSeries(seriesID, householderID string) (*model.BookingSeries, error) {
+	series, err := s.ownedSeries(seriesID, householderID)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == model.SeriesActive || series.Status == model.SeriesPaused {
+		series.Upcoming = upcomingOccurrences(series, time.Now(), config.SERIES_UPCOMING_PREVIEW)
+	}
+	return series, nil
+}
+
+// SkipSeriesOccurrence drops the occurrence starting at start from the series. An occurrence that has
+// already been materialised is cancelled under the usual cancellation rules.
+func (s *HouseholderService) SkipSeriesOccurrence(seriesID, householderID string, start time.Time) error {
+	series, err := s.ownedSeries(seriesID, householderID)
+	if err != nil {
+		return err
+	}
+	if series.Status == model.SeriesCancelled {
+		return errors.New(errs.SeriesAlreadyCancelled)
+	}
+
+	occurrence, ok := util.SeriesOccurrenceIndex(series.Rule, series.FirstTime, util.LoadUserLocation(series.Timezone), start)
+	if !ok || !start.After(time.Now()) {
+		return errors.New(errs.NotSeriesOccurrence)
+	}
+	if series.IsSkipped(occurrence) {
+		return errors.New(errs.OccurrenceAlreadySkipped)
+	}
+
+	if occurrence < series.GeneratedCount {
+		requestID, err := s.seriesRepo.GetSeriesRequestID(series.ID, occurrence)
+		if err != nil {
+			return err
+		}
+		if requestID != "" {
+			_, err := s.CancelServiceRequest(requestID, householderID, "occurrence skipped by householder")
+			if err != nil && err.Error() != errs.RequestAlreadyCancelled {
+				return err
+			}
+		}
+	}
+	return s.seriesRepo.AddSkippedOccurrence(series.ID, occurrence)
+}
+
+// PauseBookingSeries stops new occurrences from being materialised; booked ones are kept
+func (s *HouseholderService) PauseBookingSeries(seriesID, householderID string) error {
+	series, err := s.ownedSeries(seriesID, householderID)
+	if err != nil {
+		return err
+	}
+	if series.Status != model.SeriesActive {
+		return errors.New(errs.SeriesNotActive)
+	}
+	series.Status = model.SeriesPaused
+	return s.seriesRepo.UpdateSeries(series)
+}
+
+// ResumeBookingSeries restarts a paused series. Occurrences that passed while it was paused are not booked.
+func (s *HouseholderService) ResumeBookingSeries(seriesID, householderID string) error {
+	series, err := s.ownedSeries(seriesID, householderID)
+	if err != nil {
+		return err
+	}
+	if series.Status != model.SeriesPaused {
+		return errors.New(errs.SeriesNotPaused)
+	}
+	series.Status = model.SeriesActive
+	return s.generateSeriesOccurrences(series, time.Now())
+}
+
+// CancelBookingSeries ends the series and cancels its materialised occurrences. Occurrences that would
+// cost a cancellation fee under their policy are left in place for the householder to cancel one by one.
+func (s *HouseholderService) CancelBookingSeries(seriesID, householderID string) error {
+	series, err := s.ownedSeries(seriesID, householderID)
+	if err != nil {
+		return err
+	}
+	if series.Status == model.SeriesCancelled {
+		return errors.New(errs.SeriesAlreadyCancelled)
+	}
+
+	series.Status = model.SeriesCancelled
+	if err := s.seriesRepo.UpdateSeries(series); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	requestIDs, err := s.seriesRepo.GetSeriesRequestIDs(series.ID, now.UTC())
+	if err != nil {
+		return err
+	}
+	for _, requestID := range requestIDs {
+		request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+		if err != nil {
+			return err
+		}
+		terms, _, err := cancellationTerms(s.cancellationRepo, s.serviceRepo, s.serviceRequestRepo, request, false, now)
+		if err != nil {
+			return err
+		}
+		if terms == nil || !terms.Fee.IsZero() {
+			continue
+		}
+		event, err := changeRequestStatus(request, model.StatusCancelled, householderID, "Householder", "booking series cancelled")
+		if err != nil {
+			return err
+		}
+		if err := s.serviceRequestRepo.UpdateServiceRequest(request, event.OldStatus); err != nil {
+			return err
+		}
+		if err := s.requestEventRepo.SaveEvent(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateSeriesOccurrences materialises the occurrences of every active series that fall within the
+// generation horizon. A failing series does not stop the others from being generated.
+func (s *HouseholderService) GenerateSeriesOccurrences() error {
+	seriesList, err := s.seriesRepo.GetSeriesByStatus(model.SeriesActive)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var failed []string
+	for i := range seriesList {
+		if err := s.generateSeriesOccurrences(&seriesList[i], now); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", seriesList[i].ID, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not generate occurrences for %d booking series: %v", len(failed), failed)
+	}
+	return nil
+}
+
+// ownedSeries loads a series and ensures it belongs to the householder
+func (s *HouseholderService) ownedSeries(seriesID, householderID string) (*model.BookingSeries, error) {
+	series, err := s.seriesRepo.GetSeriesByID(seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.HouseholderID != householderID {
+		return nil, errors.New(errs.SeriesNotBelongToHouseholder)
+	}
+	return series, nil
+}
+
+// generateSeriesOccurrences books every occurrence of the series up to the generation horizon that has
+// not been handled yet and saves the series' progress. Past and skipped occurrences are passed over.
+// Only the progress is written back, so changes made to the series meanwhile are not overwritten.
+func (s *HouseholderService) generateSeriesOccurrences(series *model.BookingSeries, now time.Time) error {
+	location := util.LoadUserLocation(series.Timezone)
+	horizon := now.Add(config.SERIES_GENERATION_HORIZON)
+
+	var generateErr error
+	for {
+		start, ok := util.SeriesOccurrence(series.Rule, series.FirstTime, location, series.GeneratedCount)
+		if !ok {
+			series.Status = model.SeriesCompleted
+			break
+		}
+		if start.After(horizon) {
+			break
+		}
+		if start.After(now) && !series.IsSkipped(series.GeneratedCount) {
+			if err := s.createSeriesOccurrence(series, series.GeneratedCount, start); err != nil {
+				generateErr = err
+				break
+			}
+		}
+		series.GeneratedCount++
+	}
+
+	active, err := s.seriesRepo.UpdateSeriesProgress(series.ID, series.GeneratedCount, series.Status == model.SeriesCompleted)
+	if err != nil {
+		return err
+	}
+	if !active {
+		logger.Info("Booking series changed while its occurrences were generated", map[string]interface{}{"seriesID": series.ID})
+	}
+	return generateErr
+}
+
+// createSeriesOccurrence saves the request for one occurrence and, once the series has an adopted
+// provider, pre-approves that provider for it. If the provider cannot take the job the request stays
+// pending so that other providers can quote on it.
+func (s *HouseholderService) createSeriesOccurrence(series *model.BookingSeries, occurrence int, start time.Time) error {
+	householder, err := s.householderRepo.GetHouseholderByID(series.HouseholderID)
+	if err != nil {
+		return err
+	}
+
+	seriesID := series.ID
+	request := model.ServiceRequest{
+		ID:                 GetUniqueID(),
+		ServiceName:        series.ServiceName,
+		HouseholderName:    householder.Name,
+		HouseholderID:      &householder.User.ID,
+		HouseholderAddress: &householder.Address,
+		HouseholderContact: householder.Contact,
+		Description:        series.Description,
+		ServiceID:          series.ServiceID,
+		RequestedTime:      time.Now().UTC(),
+		ScheduledTime:      start.UTC(),
+		Status:             model.StatusPending,
+		SeriesID:           &seriesID,
+		SeriesOccurrence:   occurrence,
+	}
+	if err := s.serviceRequestRepo.SaveServiceRequest(request); err != nil {
+		if err.Error() == errs.SeriesOccurrenceExists {
+			// A concurrent run created this occurrence first
+			return nil
+		}
+		return err
+	}
+	reason := fmt.Sprintf("occurrence %d of booking series %s", occurrence+1, series.ID)
+	if err := s.requestEventRepo.SaveEvent(newRequestEvent(request.ID, "", model.StatusPending, series.HouseholderID, "Householder", reason)); err != nil {
+		return err
+	}
+
+	if series.ProviderID == nil || series.ProviderPrice == nil {
+		return nil
+	}
+	providerID := *series.ProviderID
+	return s.availabilityRepo.WithProviderLock(providerID, func() error {
+		_, err := s.preApproveOccurrence(&request, providerID, *series.ProviderPrice, series.QuoteID)
+		return err
+	})
+}
+
+// adoptSeriesProvider records the provider approved on one occurrence as the provider of the whole
+// series, at the approved quote, and pre-approves them for occurrences still waiting for quotes. Callers
+// hold the provider lock.
+func (s *HouseholderService) adoptSeriesProvider(seriesID, approvedRequestID, providerID string, price model.Money, quoteID string) error {
+	series, err := s.seriesRepo.GetSeriesByID(seriesID)
+	if err != nil {
+		return err
+	}
+	if series.Status == model.SeriesCancelled || (series.ProviderID != nil && *series.ProviderID == providerID) {
+		return nil
+	}
+
+	series.ProviderID = &providerID
+	series.ProviderPrice = &price
+	series.QuoteID = quoteID
+	if err := s.seriesRepo.UpdateSeries(series); err != nil {
+		return err
+	}
+
+	requestIDs, err := s.seriesRepo.GetSeriesRequestIDs(seriesID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	for _, requestID := range requestIDs {
+		if requestID == approvedRequestID {
+			continue
+		}
+		request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+		if err != nil {
+			return err
+		}
+		if request.Status != model.StatusPending {
+			continue
+		}
+		if _, err := s.preApproveOccurrence(request, providerID, price, quoteID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preApproveOccurrence accepts and approves a pending occurrence on behalf of the series' provider at
+// the agreed price, carrying over the quote approved for the series when there is one. It reports false,
+// leaving the request pending, when the provider has no free slot or another job overlaps it. Callers
+// hold the provider lock.
+func (s *HouseholderService) preApproveOccurrence(request *model.ServiceRequest, providerID string, price model.Money, quoteID string) (bool, error) {
+	free, err := providerFreeAt(s.availabilityRepo, providerID, request.ServiceID, request.ScheduledTime, request.ID)
+	if err != nil || !free {
+		return false, err
+	}
+	if _, err := checkScheduleConflicts(s.availabilityRepo, providerID, request.ServiceID, request.ID, request.ScheduledTime); err != nil {
+		var conflictErr *errs.ScheduleConflictError
+		if errors.As(err, &conflictErr) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	provider, err := s.providerRepo.GetProviderDetailByID(providerID, request.ServiceID)
+	if err != nil {
+		return false, err
+	}
+	provider.ServiceProviderID = providerID
+	provider.Price = price
+	provider.Approve = 1
+
+	accepted, err := changeRequestStatus(request, model.StatusAccepted, providerID, "ServiceProvider", "provider pre-approved for booking series")
+	if err != nil {
+		return false, err
+	}
+	approved, err := changeRequestStatus(request, model.StatusApproved, *request.HouseholderID, "Householder", fmt.Sprintf("approved provider %s for booking series", providerID))
+	if err != nil {
+		return false, err
+	}
+	request.ApproveStatus = true
+
+	if err := s.serviceRequestRepo.UpdateServiceRequest(request, approved.OldStatus); err != nil {
+		return false, err
+	}
+	if err := s.providerRepo.SaveServiceProviderDetail(provider, request.ID, request.ServiceID); err != nil {
+		return false, err
+	}
+	for _, event := range []*model.ServiceRequestEvent{accepted, approved} {
+		if err := s.requestEventRepo.SaveEvent(event); err != nil {
+			return false, err
+		}
+	}
+	if quoteID != "" {
+		if err := s.carryOverSeriesQuote(quoteID, request); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// carryOverSeriesQuote records the series' approved quote as the approved quote of an occurrence
+func (s *HouseholderService) carryOverSeriesQuote(quoteID string, request *model.ServiceRequest) error {
+	quote, err := s.quoteRepo.GetQuoteByID(quoteID)
+	if err != nil {
+		return err
+	}
+	quote.ID = util.GenerateUUID()
+	quote.RequestID = request.ID
+	quote.Version = 1
+	quote.ProposedBy = "ServiceProvider"
+	quote.Status = model.QuoteApproved
+	quote.ValidUntil = request.ScheduledTime
+	quote.Note = "carried over from booking series"
+	quote.CreatedAt = time.Now().UTC()
+	return s.quoteRepo.SaveQuote(quote)
+}
+
+// upcomingOccurrences lists up to limit future occurrences of the series that have not been skipped
+func upcomingOccurrences(series *model.BookingSeries, now time.Time, limit int) []model.SeriesOccurrence {
+	location := util.LoadUserLocation(series.Timezone)
+	upcoming := []model.SeriesOccurrence{}
+	for n := 0; len(upcoming) < limit; n++ {
+		start, ok := util.SeriesOccurrence(series.Rule, series.FirstTime, location, n)
+		if !ok {
+			break
+		}
+		if start.After(now) && !series.IsSkipped(n) {
+			upcoming = append(upcoming, model.SeriesOccurrence{Occurrence: n, Start: start})
+		}
+	}
+	return upcoming
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+// cancellationPolicyFor finds the policy covering a provider's booking of a service: the provider's own
+// policy, else the policy of the service's category, else the built-in default
+func cancellationPolicyFor(cancellationRepo interfaces.CancellationRepository, serviceRepo interfaces.ServiceRepository, providerID, serviceID string) (*model.CancellationPolicy, error) {
+	policy, err := cancellationRepo.GetPolicy(model.PolicyScopeProvider, providerID)
+	if err == nil {
+		return policy, nil
+	}
+	if err.Error() != errs.CancellationPolicyNotFound {
+		return nil, err
+	}
+
+	service, err := serviceRepo.GetServiceByID(serviceID)
+	if err != nil && err.Error() != errs.ServiceNotFound {
+		return nil, err
+	}
+	if service != nil && service.Category != "" {
+		policy, err = cancellationRepo.GetPolicy(model.PolicyScopeCategory, service.Category)
+		if err == nil {
+			return policy, nil
+		}
+		if err.Error() != errs.CancellationPolicyNotFound {
+			return nil, err
+		}
+	}
+	return util.DefaultCancellationPolicy(), nil
+}
+
+// cancellationTerms works out what cancelling the request at now costs the householder or, byProvider,
+// the approved provider, along with that provider's ID. Requests waiting for approval cancel for free
+// and requests that can no longer be cancelled have no terms.
+func cancellationTerms(cancellationRepo interfaces.CancellationRepository, serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, request *model.ServiceRequest, byProvider bool, now time.Time) (*model.CancellationTerms, string, error) {
+	if !request.Status.CanTransitionTo(model.StatusCancelled) {
+		return nil, "", nil
+	}
+	if request.Status != model.StatusApproved {
+		return &model.CancellationTerms{Tier: model.CancellationFree, Fee: model.NewMoney(0, model.DefaultCurrency)}, "", nil
+	}
+
+	providerID, err := serviceRequestRepo.GetApprovedProviderIDByRequestID(request.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	approved, err := serviceRequestRepo.GetServiceProviderByRequestID(request.ID, providerID)
+	if err != nil {
+		return nil, "", err
+	}
+	policy, err := cancellationPolicyFor(cancellationRepo, serviceRepo, providerID, request.ServiceID)
+	if err != nil {
+		return nil, "", err
+	}
+	terms := util.EvaluateCancellation(policy, approved.ProviderDetails[0].Price, request.ScheduledTime, now, byProvider)
+	return terms, providerID, nil
+}
+
+// cancellationCharge builds the fee or penalty of a cancellation that costs something, nil if it is free,
+// and a note on it for the request's history
+func cancellationCharge(request *model.ServiceRequest, providerID, actorID, actorRole string, terms *model.CancellationTerms) (*model.CancellationCharge, string) {
+	if terms == nil || (terms.Fee.IsZero() && terms.PenaltyPoints == 0) {
+		return nil, ""
+	}
+	charge := &model.CancellationCharge{
+		ID:            util.GenerateUUID(),
+		RequestID:     request.ID,
+		ProviderID:    providerID,
+		CancelledBy:   actorRole,
+		ActorID:       actorID,
+		PolicyID:      terms.PolicyID,
+		Tier:          terms.Tier,
+		Fee:           terms.Fee,
+		PenaltyPoints: terms.PenaltyPoints,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if terms.PenaltyPoints > 0 {
+		return charge, fmt.Sprintf("%s cancellation, %d penalty points", terms.Tier, terms.PenaltyPoints)
+	}
+	return charge, fmt.Sprintf("%s cancellation, fee %s", terms.Tier, terms.Fee)
+}
+
+// cancelRequest moves the request to Cancelled and stores the charge and history entry in one go
+func cancelRequest(cancellationRepo interfaces.CancellationRepository, request *model.ServiceRequest, providerID, actorID, actorRole, reason string, terms *model.CancellationTerms) error {
+	previous := request.Status
+	event, err := changeRequestStatus(request, model.StatusCancelled, actorID, actorRole, reason)
+	if err != nil {
+		return err
+	}
+	charge, note := cancellationCharge(request, providerID, actorID, actorRole, terms)
+	if note != "" {
+		event.Reason += " (" + note + ")"
+	}
+	cancelled, err := cancellationRepo.SaveCancellation(request, previous, charge, event)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return errors.New(errs.RequestNotCancellable)
+	}
+	return nil
+}
+
+// GetCancellationTerms shows what cancelling a request would cost right now: the householder's fee, or
+// the approved provider's penalty when a provider asks
+func (s *HouseholderService) GetCancellationTerms(requestID, userID, role string) (*model.CancellationTerms, error) {
+	if err := s.authorizeRequestView(requestID, userID, role); err != nil {
+		return nil, err
+	}
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	terms, providerID, err := cancellationTerms(s.cancellationRepo, s.serviceRepo, s.serviceRequestRepo, request, role == "ServiceProvider", time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if terms == nil {
+		return nil, errors.New(errs.RequestNotCancellable)
+	}
+	if role == "ServiceProvider" && providerID != "" && providerID != userID {
+		return nil, errors.New(errs.RequestNotApprovedForProvider)
+	}
+	return terms, nil
+}
+
+// attachCancellationTerms shows the householder what cancelling each of their bookings costs right now
+func (s *HouseholderService) attachCancellationTerms(requests []model.ServiceRequest) error {
+	now := time.Now()
+	for i := range requests {
+		terms, _, err := cancellationTerms(s.cancellationRepo, s.serviceRepo, s.serviceRequestRepo, &requests[i], false, now)
+		if err != nil {
+			return err
+		}
+		requests[i].Cancellation = terms
+	}
+	return nil
+}
+
+// CancelApprovedRequest lets the approved provider drop a booking before it starts. The provider loses
+// reliability points under the booking's cancellation policy, more the closer to the scheduled time.
+func (s *ServiceProviderService) CancelApprovedRequest(providerID, requestID, reason string) (*model.CancellationTerms, error) {
+	request, err := s.approvedRequestForProvider(providerID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != model.StatusApproved {
+		return nil, errors.New(errs.OnlyApprovedRequestCancelled)
+	}
+
+	terms, _, err := cancellationTerms(s.cancellationRepo, s.serviceRepo, s.serviceRequestRepo, request, true, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		reason = "cancelled by provider"
+	}
+	if err := cancelRequest(s.cancellationRepo, request, providerID, providerID, "ServiceProvider", reason, terms); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
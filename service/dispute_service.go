@@ -0,0 +1,384 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/util"
+	"strings"
+	"time"
+)
+
+type DisputeService struct {
+	disputeRepo        interfaces.DisputeRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	requestEventRepo   interfaces.ServiceRequestEventRepository
+	paymentRepo        interfaces.PaymentRepository
+	paymentService     interfaces.PaymentService
+}
+
+// NewDisputeService initializes a new DisputeService; refunds decided on disputes go through the
+// payment service
+func NewDisputeService(disputeRepo interfaces.DisputeRepository, serviceRequestRepo interfaces.ServiceRequestRepository, requestEventRepo interfaces.ServiceRequestEventRepository, paymentRepo interfaces.PaymentRepository, paymentService interfaces.PaymentService) interfaces.DisputeService {
+	return &DisputeService{
+		disputeRepo:        disputeRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		requestEventRepo:   requestEventRepo,
+		paymentRepo:        paymentRepo,
+		paymentService:     paymentService,
+	}
+}
+
+// OpenDispute lets the householder or the approved provider of a request raise a dispute on it, with an
+// optional first piece of evidence. A request has at most one unresolved dispute at a time.
+func (s *DisputeService) OpenDispute(requestID, userID, role, reason, evidence string) (*model.Dispute, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New(errs.InvalidDispute)
+	}
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if !request.ApproveStatus || request.HouseholderID == nil {
+		return nil, errors.New(errs.RequestNotDisputable)
+	}
+	providerID, err := s.serviceRequestRepo.GetApprovedProviderIDByRequestID(requestID)
+	if err != nil {
+		if err.Error() == errs.NoApprovedProviderForRequest {
+			return nil, errors.New(errs.RequestNotDisputable)
+		}
+		return nil, err
+	}
+	switch role {
+	case "Householder":
+		if *request.HouseholderID != userID {
+			return nil, errors.New(errs.RequestNotBelongToHouseholder)
+		}
+	case "ServiceProvider":
+		if providerID != userID {
+			return nil, errors.New(errs.RequestNotApprovedForProvider)
+		}
+	default:
+		return nil, errors.New(errs.NotDisputeParty)
+	}
+
+	// Checked up front for a clear error; the schema enforces it for disputes opened at the same time
+	disputes, err := s.disputeRepo.GetDisputesByRequestID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	for _, dispute := range disputes {
+		if dispute.Status != model.DisputeResolved {
+			return nil, errors.New(errs.DisputeAlreadyOpen)
+		}
+	}
+
+	now := time.Now().UTC()
+	dispute := &model.Dispute{
+		ID:            util.GenerateUUID(),
+		RequestID:     requestID,
+		HouseholderID: *request.HouseholderID,
+		ProviderID:    providerID,
+		OpenedBy:      userID,
+		OpenedByRole:  role,
+		Reason:        reason,
+		Status:        model.DisputeOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.disputeRepo.SaveDispute(dispute); err != nil {
+		return nil, err
+	}
+	if evidence = strings.TrimSpace(evidence); evidence != "" {
+		note, err := s.saveNote(dispute.ID, userID, role, evidence)
+		if err != nil {
+			return nil, err
+		}
+		dispute.Notes = append(dispute.Notes, *note)
+	}
+	event := newRequestEvent(requestID, request.Status, request.Status, userID, role, fmt.Sprintf("dispute %s opened: %s", dispute.ID, reason))
+	if err := s.requestEventRepo.SaveEvent(event); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// GetRequestDisputes lists the disputes raised on a request for its householder, its approved provider
+// or an admin
+func (s *DisputeService) GetRequestDisputes(requestID, userID, role string) ([]model.Dispute, error) {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	switch role {
+	case "Admin":
+	case "Householder":
+		if request.HouseholderID == nil || *request.HouseholderID != userID {
+			return nil, errors.New(errs.RequestNotBelongToHouseholder)
+		}
+	case "ServiceProvider":
+		providerID, err := s.serviceRequestRepo.GetApprovedProviderIDByRequestID(requestID)
+		if err != nil || providerID != userID {
+			return nil, errors.New(errs.RequestNotApprovedForProvider)
+		}
+	default:
+		return nil, errors.New(errs.NotDisputeParty)
+	}
+	return s.disputeRepo.GetDisputesByRequestID(requestID)
+}
+
+// GetDispute returns a dispute with its notes to one of its parties or an admin
+func (s *DisputeService) GetDispute(disputeID, userID, role string) (*model.Dispute, error) {
+	dispute, err := s.disputeForParty(disputeID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Notes, err = s.disputeRepo.GetNotes(disputeID); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// AddNote lets a party respond to a dispute, or an admin ask for more, until it is resolved
+func (s *DisputeService) AddNote(disputeID, userID, role, note string) (*model.DisputeNote, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, errors.New(errs.InvalidDisputeNote)
+	}
+	dispute, err := s.disputeForParty(disputeID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status == model.DisputeResolved {
+		return nil, errors.New(errs.DisputeAlreadyResolved)
+	}
+	return s.saveNote(disputeID, userID, role, note)
+}
+
+// GetDisputeQueue lists the disputes in status, oldest first. Without a status it lists every dispute
+// still waiting for a decision.
+func (s *DisputeService) GetDisputeQueue(status string, limit, offset int) ([]model.Dispute, error) {
+	statuses := []model.DisputeStatus{model.DisputeOpen, model.DisputeUnderReview}
+	switch model.DisputeStatus(status) {
+	case "":
+	case model.DisputeOpen, model.DisputeUnderReview, model.DisputeResolved:
+		statuses = []model.DisputeStatus{model.DisputeStatus(status)}
+	default:
+		return nil, errors.New(errs.InvalidDisputeStatus)
+	}
+	return s.disputeRepo.GetDisputesByStatus(statuses, limit, offset)
+}
+
+// StartReview marks an open dispute as taken up by an admin
+func (s *DisputeService) StartReview(disputeID, adminID string) (*model.Dispute, error) {
+	dispute, err := s.disputeRepo.GetDisputeByID(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status == model.DisputeResolved {
+		return nil, errors.New(errs.DisputeAlreadyResolved)
+	}
+	if !dispute.Status.CanTransitionTo(model.DisputeUnderReview) {
+		return nil, errors.New(errs.DisputeAlreadyUnderReview)
+	}
+
+	fromStatus := dispute.Status
+	dispute.Status = model.DisputeUnderReview
+	dispute.UpdatedAt = time.Now().UTC()
+	updated, err := s.disputeRepo.UpdateDispute(dispute, fromStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New(errs.DisputeAlreadyUnderReview)
+	}
+	if err := s.saveDisputeEvent(dispute, adminID, fmt.Sprintf("dispute %s under review", dispute.ID)); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// ResolveDispute settles a dispute with the admin's decision. Refunds are taken from the request's
+// captured payment; the dispute is marked resolved first, so a second admin acting at the same time
+// cannot refund it again, and is reopened if the refund does not go through. Once the gateway has refunded
+// it stays resolved, even when recording the refund fails.
+func (s *DisputeService) ResolveDispute(disputeID, adminID string, resolution model.DisputeResolution) (*model.Dispute, error) {
+	if err := validateDisputeResolution(resolution); err != nil {
+		return nil, err
+	}
+	dispute, err := s.disputeRepo.GetDisputeByID(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if !dispute.Status.CanTransitionTo(model.DisputeResolved) {
+		return nil, errors.New(errs.DisputeAlreadyResolved)
+	}
+
+	var payment *model.Payment
+	var refund *model.Money
+	var refundErr error
+	if resolution.Outcome == model.DisputeFullRefund || resolution.Outcome == model.DisputePartialRefund {
+		if payment, err = s.refundablePayment(dispute.RequestID); err != nil {
+			return nil, err
+		}
+		remaining, err := payment.Amount.Sub(payment.Refunded)
+		if err != nil {
+			return nil, err
+		}
+		refund = &remaining
+		if resolution.Outcome == model.DisputePartialRefund {
+			refund = resolution.Amount
+		}
+	}
+
+	fromStatus := dispute.Status
+	now := time.Now().UTC()
+	dispute.Status = model.DisputeResolved
+	dispute.Outcome = resolution.Outcome
+	dispute.Refunded = refund
+	dispute.PenaltyPoints = resolution.PenaltyPoints
+	dispute.Resolution = strings.TrimSpace(resolution.Notes)
+	dispute.ResolvedBy = adminID
+	dispute.UpdatedAt = now
+	dispute.ResolvedAt = &now
+	updated, err := s.disputeRepo.UpdateDispute(dispute, fromStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New(errs.DisputeAlreadyResolved)
+	}
+
+	if payment != nil {
+		if s.paymentService == nil {
+			s.reopenDispute(dispute, fromStatus)
+			return nil, errors.New(errs.PaymentsDisabled)
+		}
+		if _, err := s.paymentService.RefundPayment(payment.ID, adminID, refund, "dispute "+dispute.ID); err != nil {
+			var notRecorded *errs.RefundNotRecordedError
+			if !errors.As(err, &notRecorded) {
+				// No money moved, so the dispute can be resolved again
+				s.reopenDispute(dispute, fromStatus)
+				return nil, err
+			}
+			// The refund went through; the dispute stays resolved so it is never refunded twice
+			logger.Error("Refund of resolved dispute not fully recorded", map[string]interface{}{"disputeID": dispute.ID, "paymentID": payment.ID, "error": err.Error()})
+			refundErr = err
+		}
+	}
+
+	note := fmt.Sprintf("dispute %s resolved: %s", dispute.ID, dispute.Outcome)
+	if refund != nil {
+		note += fmt.Sprintf(", refunded %s", refund)
+	}
+	if dispute.PenaltyPoints > 0 {
+		note += fmt.Sprintf(", %d penalty points", dispute.PenaltyPoints)
+	}
+	if dispute.Resolution != "" {
+		note += ": " + dispute.Resolution
+	}
+	if err := s.saveDisputeEvent(dispute, adminID, note); err != nil {
+		return nil, err
+	}
+	if refundErr != nil {
+		return nil, refundErr
+	}
+	return dispute, nil
+}
+
+// validateDisputeResolution checks the outcome carries what it needs: an amount for partial refunds and
+// penalty points for provider penalties
+func validateDisputeResolution(resolution model.DisputeResolution) error {
+	if !resolution.Outcome.IsValid() || resolution.PenaltyPoints < 0 || resolution.PenaltyPoints > config.MAX_DISPUTE_PENALTY_POINTS {
+		return errors.New(errs.InvalidDisputeResolution)
+	}
+	switch resolution.Outcome {
+	case model.DisputePartialRefund:
+		if resolution.Amount == nil || resolution.Amount.Amount <= 0 {
+			return errors.New(errs.InvalidDisputeResolution)
+		}
+	case model.DisputeProviderPenalty:
+		if resolution.PenaltyPoints == 0 || resolution.Amount != nil {
+			return errors.New(errs.InvalidDisputeResolution)
+		}
+	default:
+		if resolution.Amount != nil {
+			return errors.New(errs.InvalidDisputeResolution)
+		}
+	}
+	return nil
+}
+
+// refundablePayment finds the request's captured payment that still has money left to refund
+func (s *DisputeService) refundablePayment(requestID string) (*model.Payment, error) {
+	payments, err := s.paymentRepo.GetPaymentsByRequestID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range payments {
+		if payments[i].Status == model.PaymentCaptured || payments[i].Status == model.PaymentPartiallyRefunded {
+			return &payments[i], nil
+		}
+	}
+	return nil, errors.New(errs.NothingToRefund)
+}
+
+// reopenDispute puts a dispute whose refund failed back where it was, so it can be resolved again
+func (s *DisputeService) reopenDispute(dispute *model.Dispute, status model.DisputeStatus) {
+	dispute.Status = status
+	dispute.Outcome = ""
+	dispute.Refunded = nil
+	dispute.PenaltyPoints = 0
+	dispute.Resolution = ""
+	dispute.ResolvedBy = ""
+	dispute.ResolvedAt = nil
+	dispute.UpdatedAt = time.Now().UTC()
+	if _, err := s.disputeRepo.UpdateDispute(dispute, model.DisputeResolved); err != nil {
+		logger.Error("Error reopening dispute", map[string]interface{}{"disputeID": dispute.ID, "error": err.Error()})
+	}
+}
+
+// disputeForParty loads a dispute and ensures the user is one of its parties or an admin
+func (s *DisputeService) disputeForParty(disputeID, userID, role string) (*model.Dispute, error) {
+	dispute, err := s.disputeRepo.GetDisputeByID(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case role == "Admin":
+	case role == "Householder" && dispute.HouseholderID == userID:
+	case role == "ServiceProvider" && dispute.ProviderID == userID:
+	default:
+		return nil, errors.New(errs.NotDisputeParty)
+	}
+	return dispute, nil
+}
+
+func (s *DisputeService) saveNote(disputeID, userID, role, text string) (*model.DisputeNote, error) {
+	note := &model.DisputeNote{
+		ID:         util.GenerateUUID(),
+		DisputeID:  disputeID,
+		AuthorID:   userID,
+		AuthorRole: role,
+		Note:       text,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := s.disputeRepo.SaveNote(note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// saveDisputeEvent records an admin's step on a dispute in the request's history
+func (s *DisputeService) saveDisputeEvent(dispute *model.Dispute, adminID, reason string) error {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(dispute.RequestID)
+	if err != nil {
+		return err
+	}
+	return s.requestEventRepo.SaveEvent(newRequestEvent(request.ID, request.Status, request.Status, adminID, "Admin", reason))
+}
@@ -3,11 +3,12 @@ package service
 import (
 	"errors"
 	"fmt"
-	"log"
+	"serviceNest/config"
 	"serviceNest/errs"
 	"serviceNest/interfaces"
 	"serviceNest/model"
 	"serviceNest/util"
+	"sort"
 	"time"
 )
 
@@ -18,14 +19,28 @@ type HouseholderService struct {
 	providerRepo       interfaces.ServiceProviderRepository
 	serviceRepo        interfaces.ServiceRepository
 	serviceRequestRepo interfaces.ServiceRequestRepository
+	requestEventRepo   interfaces.ServiceRequestEventRepository
+	ratingRepo         interfaces.RatingRepository
+	serviceSearcher    interfaces.ServiceSearcher
+	availabilityRepo   interfaces.AvailabilityRepository
+	seriesRepo         interfaces.BookingSeriesRepository
+	quoteRepo          interfaces.QuoteRepository
+	cancellationRepo   interfaces.CancellationRepository
 }
 
-func NewHouseholderService(householderRepo interfaces.HouseholderRepository, providerRepo interfaces.ServiceProviderRepository, serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository) interfaces.HouseholderService {
+func NewHouseholderService(householderRepo interfaces.HouseholderRepository, providerRepo interfaces.ServiceProviderRepository, serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, requestEventRepo interfaces.ServiceRequestEventRepository, ratingRepo interfaces.RatingRepository, serviceSearcher interfaces.ServiceSearcher, availabilityRepo interfaces.AvailabilityRepository, seriesRepo interfaces.BookingSeriesRepository, quoteRepo interfaces.QuoteRepository, cancellationRepo interfaces.CancellationRepository) interfaces.HouseholderService {
 	return &HouseholderService{
 		householderRepo:    householderRepo,
 		providerRepo:       providerRepo,
 		serviceRepo:        serviceRepo,
 		serviceRequestRepo: serviceRequestRepo,
+		requestEventRepo:   requestEventRepo,
+		ratingRepo:         ratingRepo,
+		serviceSearcher:    serviceSearcher,
+		availabilityRepo:   availabilityRepo,
+		seriesRepo:         seriesRepo,
+		quoteRepo:          quoteRepo,
+		cancellationRepo:   cancellationRepo,
 	}
 }
 func (s *HouseholderService) ViewStatus(householderID string, limit, offset int, status string) ([]model.ServiceRequest, error) {
@@ -51,42 +66,65 @@ func (s *HouseholderService) CancelAcceptedRequest(requestID, householderID stri
 	}
 
 	// Check if the service request is in "Accepted" status
-	if serviceRequest.Status != "Accepted" {
+	if serviceRequest.Status != model.StatusAccepted {
 		return errors.New(errs.OnlyAcceptedRequestCancelled)
 	}
 
 	// Update the status to "Cancelled"
-	serviceRequest.Status = "Cancelled"
+	event, err := changeRequestStatus(serviceRequest, model.StatusCancelled, householderID, "Householder", "accepted request cancelled by householder")
+	if err != nil {
+		return err
+	}
 
 	// Save the updated service request
-	err = s.serviceRequestRepo.UpdateServiceRequest(serviceRequest)
+	err = s.serviceRequestRepo.UpdateServiceRequest(serviceRequest, event.OldStatus)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return s.requestEventRepo.SaveEvent(event)
 }
 
-// SearchService searches for available service_test providers based on service_test type and proximity
-func (s *HouseholderService) SearchService(householder *model.Householder, serviceType string) ([]model.ServiceProvider, error) {
+// SearchService searches for service providers offering the service type within reach of the
+// householder, nearest first. A radiusKm of zero falls back to the default search radius.
+func (s *HouseholderService) SearchService(householderID string, serviceType string, radiusKm float64) ([]model.ServiceProvider, error) {
+	householder, err := s.householderRepo.GetHouseholderByID(householderID)
+	if err != nil {
+		return nil, err
+	}
+	if householder.Latitude == nil || householder.Longitude == nil {
+		return nil, errors.New(errs.HouseholderLocationUnknown)
+	}
+
 	providers, err := s.providerRepo.GetProvidersByServiceType(serviceType)
 	if err != nil {
 		return nil, err
 	}
 
-	// Example logic: Filter providers by proximity to householder
 	nearbyProviders := []model.ServiceProvider{}
 	for _, provider := range providers {
-		if s.isNearby(householder, &provider) {
+		if !provider.IsActive {
+			continue
+		}
+		if distance, ok := s.isNearby(householder, &provider, radiusKm); ok {
+			provider.DistanceKm = &distance
 			nearbyProviders = append(nearbyProviders, provider)
 		}
 	}
 
+	sort.SliceStable(nearbyProviders, func(i, j int) bool {
+		return *nearbyProviders[i].DistanceKm < *nearbyProviders[j].DistanceKm
+	})
 	return nearbyProviders, nil
+}
 
+// SearchServices runs a typo-tolerant free-text search over service names, descriptions,
+// categories and provider names
+func (s *HouseholderService) SearchServices(query string, limit, offset int) ([]model.ServiceSearchResult, error) {
+	return s.serviceSearcher.Search(query, limit, offset)
 }
 
-func (s *HouseholderService) GetServicesByCategory(category string) ([]model.Service, error) {
+func (s *HouseholderService) GetServicesByCategory(category string, sortBy string) ([]model.Service, error) {
 	// Fetch all services from the service_test repository_test
 	services, err := s.serviceRepo.GetServicesByCategory(category)
 	if err != nil {
@@ -111,7 +149,7 @@ func (s *HouseholderService) GetServicesByCategory(category string) ([]model.Ser
 
 	}
 
-	return filteredServices, nil
+	return s.rankServices(filteredServices, sortBy)
 }
 
 // RequestService allows the householder to request a service_test from a provider
@@ -126,6 +164,15 @@ func (s *HouseholderService) RequestService(householderID string, serviceName st
 		return "", errors.New("service category does not exist")
 	}
 
+	// At least one provider of the service must have a free slot at the requested time
+	free, err := anyProviderFreeAt(s.availabilityRepo, *serviceId, *scheduleTime, "")
+	if err != nil {
+		return "", err
+	}
+	if !free {
+		return "", errors.New(errs.NoFreeSlot)
+	}
+
 	// Generate a unique ID for the service request
 	requestID := GetUniqueID()
 
@@ -134,11 +181,6 @@ func (s *HouseholderService) RequestService(householderID string, serviceName st
 	if err != nil {
 		return "", err
 	}
-	location, err := time.LoadLocation("Asia/Kolkata")
-	if err != nil {
-		log.Printf("Failed to load location: %v", err)
-	}
-	fmt.Println(time.Now().In(location))
 	// Create the service request
 	serviceRequest := model.ServiceRequest{
 		ID:                 requestID,
@@ -149,9 +191,9 @@ func (s *HouseholderService) RequestService(householderID string, serviceName st
 		HouseholderContact: householder.Contact,
 		Description:        description,
 		ServiceID:          *serviceId,
-		RequestedTime:      time.Now().In(location),
-		ScheduledTime:      *scheduleTime,
-		Status:             "Pending",
+		RequestedTime:      time.Now().UTC(),
+		ScheduledTime:      scheduleTime.UTC(),
+		Status:             model.StatusPending,
 		ApproveStatus:      false,
 	}
 	fmt.Print(serviceRequest)
@@ -161,50 +203,116 @@ func (s *HouseholderService) RequestService(householderID string, serviceName st
 		return "", err
 	}
 
+	// Record the creation as the first entry of the request history
+	err = s.requestEventRepo.SaveEvent(&model.ServiceRequestEvent{
+		ID:        util.GenerateUUID(),
+		RequestID: serviceRequest.ID,
+		ActorID:   householderID,
+		ActorRole: "Householder",
+		NewStatus: model.StatusPending,
+		Reason:    "request created",
+		CreatedAt: serviceRequest.RequestedTime,
+	})
+	if err != nil {
+		return "", err
+	}
+
 	return serviceRequest.ID, nil
 
 }
 
 // ViewBookingHistory returns the booking history for a householder
 func (s *HouseholderService) ViewBookingHistory(householderID string, limit, offset int, status string) ([]model.ServiceRequest, error) {
-	return s.serviceRequestRepo.GetServiceRequestsByHouseholderID(householderID, limit, offset, status)
+	requests, err := s.serviceRequestRepo.GetServiceRequestsByHouseholderID(householderID, limit, offset, status)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachCancellationTerms(requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// isNearby returns the distance between householder and provider and whether it lies within both
+// the requested search radius and the provider's own service radius
+func (s *HouseholderService) isNearby(householder *model.Householder, provider *model.ServiceProvider, radiusKm float64) (float64, bool) {
+	if provider.Latitude == nil || provider.Longitude == nil {
+		return 0, false
+	}
+	if radiusKm <= 0 {
+		radiusKm = config.DEFAULT_SEARCH_RADIUS_KM
+	}
+
+	distance := util.HaversineDistance(*householder.Latitude, *householder.Longitude, *provider.Latitude, *provider.Longitude)
+	if distance > radiusKm {
+		return distance, false
+	}
+	if provider.ServiceRadiusKm > 0 && distance > provider.ServiceRadiusKm {
+		return distance, false
+	}
+	return distance, true
 }
 
-// Helper function to determine if a provider is nearby
-func (s *HouseholderService) isNearby(householder *model.Householder, provider *model.ServiceProvider) bool {
-	// TODO Implement proximity logic  based on distance between coordinates
+// GetAvailableServices fetches all available services from the repository_test. When a sort mode is
+// given every service is ranked first and the page is cut from the ranked list.
+func (s *HouseholderService) GetAvailableServices(limit, offset int, sortBy string) ([]model.Service, error) {
+	if sortBy == "" {
+		return s.serviceRepo.GetAllServices(limit, offset)
+	}
 
-	return true
+	services, err := s.serviceRepo.GetAllServices(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	ranked, err := s.rankServices(services, sortBy)
+	if err != nil {
+		return nil, err
+	}
+	return util.ApplyPagination(ranked, limit, offset), nil
 }
 
-// GetAvailableServices fetches all available services from the repository_test
-func (s *HouseholderService) GetAvailableServices(limit, offset int) ([]model.Service, error) {
-	return s.serviceRepo.GetAllServices(limit, offset)
+// rankServices orders services by the requested sort mode, loading ranking signals only when needed
+func (s *HouseholderService) rankServices(services []model.Service, sortBy string) ([]model.Service, error) {
+	if sortBy == "" || len(services) == 0 {
+		return services, nil
+	}
+	signals := &model.RankingSignals{}
+	if sortBy == util.SortRelevance || sortBy == util.SortPopularity {
+		var err error
+		signals, err = s.serviceRepo.GetRankingSignals()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return util.RankServices(services, sortBy, *signals, time.Now()), nil
 }
 
-// CancelServiceRequest allows the householder to cancel a service_test request
-func (s *HouseholderService) CancelServiceRequest(requestID string, householderID string) error {
+// CancelServiceRequest allows the householder to cancel a service_test request. Approved bookings cost
+// the fee their cancellation policy charges at this point, which is recorded and returned.
+func (s *HouseholderService) CancelServiceRequest(requestID string, householderID string, reason string) (*model.CancellationTerms, error) {
 	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if *request.HouseholderID != householderID {
-		return errors.New(errs.RequestNotBelongToHouseholder)
+		return nil, errors.New(errs.RequestNotBelongToHouseholder)
+	}
+	if request.Status == model.StatusCancelled {
+		return nil, fmt.Errorf(errs.RequestAlreadyCancelled)
 	}
 
-	// Check if the scheduled time is less than 4 hours away
-	currentTime := time.Now()
-	fmt.Println(request.ScheduledTime, " ", currentTime, " ", request.ScheduledTime.Sub(currentTime))
-	if request.ScheduledTime.Sub(currentTime) < 4*time.Hour {
-		return fmt.Errorf(errs.RequestCancellationTooLate)
+	terms, providerID, err := cancellationTerms(s.cancellationRepo, s.serviceRepo, s.serviceRequestRepo, request, false, time.Now())
+	if err != nil {
+		return nil, err
 	}
-	if request.Status == "Cancelled" {
-		return fmt.Errorf(errs.RequestAlreadyCancelled)
+	if reason == "" {
+		reason = "cancelled by householder"
 	}
-
-	request.Status = "Cancelled"
-	return s.serviceRequestRepo.UpdateServiceRequest(request)
+	if err := cancelRequest(s.cancellationRepo, request, providerID, householderID, "Householder", reason, terms); err != nil {
+		return nil, err
+	}
+	return terms, nil
 }
 
 // RescheduleServiceRequest allows the householder to reschedule a service_test request
@@ -217,12 +325,25 @@ func (s *HouseholderService) RescheduleServiceRequest(requestID string, newTime
 	if *request.HouseholderID != householderID {
 		return errors.New(errs.RequestNotBelongToHouseholder)
 	}
-	if request.Status != "Pending" && request.Status != "Accepted" {
+	if request.Status != model.StatusPending && request.Status != model.StatusAccepted {
 		return fmt.Errorf(errs.OnlyPendingRequestRescheduled)
 	}
 
-	request.ScheduledTime = newTime
-	return s.serviceRequestRepo.UpdateServiceRequest(request)
+	free, err := anyProviderFreeAt(s.availabilityRepo, request.ServiceID, newTime, request.ID)
+	if err != nil {
+		return err
+	}
+	if !free {
+		return errors.New(errs.NoFreeSlot)
+	}
+
+	request.ScheduledTime = newTime.UTC()
+	return s.serviceRequestRepo.UpdateServiceRequest(request, request.Status)
+}
+
+// GetProviderSlots lists a provider's bookable slots on the given day
+func (s *HouseholderService) GetProviderSlots(providerID string, date time.Time) ([]model.Slot, error) {
+	return providerSlots(s.availabilityRepo, providerID, date)
 }
 
 // ViewServiceRequestStatus returns the status of a specific service_test request
@@ -231,24 +352,41 @@ func (s *HouseholderService) ViewServiceRequestStatus(requestID string) (string,
 	if err != nil {
 		return "", err
 	}
-	return request.Status, nil
+	return string(request.Status), nil
 }
 
-func (s *HouseholderService) AddReview(providerID, householderID, serviceID, comments string, rating float64) error {
-	location, err := time.LoadLocation("Asia/Kolkata")
+func (s *HouseholderService) AddReview(requestID, householderID, comments string, rating float64) error {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return err
+	}
+
+	if request.HouseholderID == nil || *request.HouseholderID != householderID {
+		return errors.New(errs.RequestNotBelongToHouseholder)
+	}
+
+	// Only a job the householder confirmed as complete can be reviewed
+	if request.Status != model.StatusCompleted || !request.CompletionConfirmed {
+		return errors.New(errs.ReviewRequiresConfirmedCompletion)
+	}
+
+	// The review is always for the provider the householder approved on this booking
+	providerID, err := s.serviceRequestRepo.GetApprovedProviderIDByRequestID(requestID)
 	if err != nil {
-		log.Printf("Failed to load location: %v", err)
+		return err
 	}
+	serviceID := request.ServiceID
 
 	// Create the review object
 	review := model.Review{
-		ID:            GetUniqueID(),
+		ID:            util.GenerateUUID(),
+		RequestID:     requestID,
 		ProviderID:    providerID,
 		ServiceID:     serviceID,
 		HouseholderID: householderID,
 		Rating:        rating,
 		Comments:      comments,
-		ReviewDate:    time.Now().In(location),
+		ReviewDate:    time.Now().UTC(),
 	}
 
 	// Save the review in the repository
@@ -258,7 +396,7 @@ func (s *HouseholderService) AddReview(providerID, householderID, serviceID, com
 	}
 
 	// Recalculate and update the provider's rating
-	err = s.providerRepo.UpdateProviderRating(providerID, serviceID, review.Rating)
+	err = s.ratingRepo.RecalculateProviderRating(providerID, serviceID)
 	if err != nil {
 		return errors.New(errs.FailUpdateRating)
 	}
@@ -266,39 +404,109 @@ func (s *HouseholderService) AddReview(providerID, householderID, serviceID, com
 	return nil
 }
 
-func (s *HouseholderService) ApproveServiceRequest(requestID string, providerID string, householderID string) error {
+// ApproveServiceRequest approves a version of the provider's quote on a request; only the latest,
+// provider-proposed version can be approved and a quoteVersion of 0 approves whichever that is. It fails
+// when the provider is already committed to an overlapping job, and returns warnings for jobs within
+// the travel buffer. The request is locked as well as the provider, so the quote cannot be revised while
+// it is being approved.
+func (s *HouseholderService) ApproveServiceRequest(requestID string, providerID string, householderID string, quoteVersion int) ([]model.ScheduleConflict, error) {
+	var warnings []model.ScheduleConflict
+	err := s.availabilityRepo.WithProviderLock(providerID, func() error {
+		return s.quoteRepo.WithRequestLock(requestID, func() error {
+			var err error
+			warnings, err = s.approveServiceRequest(requestID, providerID, householderID, quoteVersion)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func (s *HouseholderService) approveServiceRequest(requestID string, providerID string, householderID string, quoteVersion int) ([]model.ScheduleConflict, error) {
 	// Retrieve the service request by ID
 	serviceRequest, err := s.serviceRequestRepo.GetServiceProviderByRequestID(requestID, providerID)
 	if err != nil {
-		return fmt.Errorf("%v: %v", errs.ServiceRequestNotFound, err)
+		return nil, fmt.Errorf("%v: %v", errs.ServiceRequestNotFound, err)
 	}
 
 	if *serviceRequest.HouseholderID != householderID {
-		return errors.New(errs.RequestNotBelongToHouseholder)
+		return nil, errors.New(errs.RequestNotBelongToHouseholder)
 	}
 	// Check if the request has already been approved
 	if serviceRequest.ApproveStatus {
-		return errors.New(errs.RequestAlreadyApproved)
+		return nil, errors.New(errs.RequestAlreadyApproved)
 	}
 
-	// Set the approval status to true
+	quote, err := s.approvableQuote(requestID, providerID, quoteVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := checkScheduleConflicts(s.availabilityRepo, providerID, serviceRequest.ServiceID, requestID, serviceRequest.ScheduledTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move the request to "Approved" and set the approval status to true
+	reason := fmt.Sprintf("approved provider %s", providerID)
+	if quote != nil {
+		reason = fmt.Sprintf("approved provider %s at quote version %d", providerID, quote.Version)
+	}
+	event, err := changeRequestStatus(serviceRequest, model.StatusApproved, householderID, "Householder", reason)
+	if err != nil {
+		return nil, err
+	}
 	serviceRequest.ApproveStatus = true
-	serviceRequest.Status = "Approved"
+	// The quote is approved only at the version the householder is approving
+	if quote != nil {
+		approved, err := s.quoteRepo.ApproveQuote(quote.ID, quote.Version)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			return nil, errors.New(errs.QuoteVersionNotLatest)
+		}
+	}
+	// Update the service request in the repository first, so a request cancelled or expired in the
+	// meantime is not approved
+	if err := s.serviceRequestRepo.UpdateServiceRequest(serviceRequest, event.OldStatus); err != nil {
+		var transitionErr *errs.StatusTransitionError
+		if errors.As(err, &transitionErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%v: %v", errs.NotUpdateRequest, err)
+	}
 	for _, provider := range serviceRequest.ProviderDetails {
 		if provider.ServiceProviderID == providerID {
 			provider.Approve = 1
 			if err := s.providerRepo.UpdateServiceProviderDetailByRequestID(&provider, requestID); err != nil {
-				return fmt.Errorf(errs.NotUpdateProviderDetails)
+				return nil, fmt.Errorf(errs.NotUpdateProviderDetails)
 			}
 			break
 		}
 	}
-	// Update the service request in the repository
-	if err := s.serviceRequestRepo.UpdateServiceRequest(serviceRequest); err != nil {
-		return fmt.Errorf("%v: %v", errs.NotUpdateRequest, err)
+
+	if err := s.requestEventRepo.SaveEvent(event); err != nil {
+		return nil, err
 	}
 
-	return nil
+	quoteID := ""
+	if quote != nil {
+		quoteID = quote.ID
+		if err := s.quoteRepo.DeclineOpenQuotes(requestID, providerID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Approving one occurrence of a recurring booking pre-approves the provider for the rest of the series
+	if serviceRequest.SeriesID != nil {
+		if err := s.adoptSeriesProvider(*serviceRequest.SeriesID, requestID, providerID, serviceRequest.ProviderDetails[0].Price, quoteID); err != nil {
+			return nil, err
+		}
+	}
+	return warnings, nil
 }
 func (s *HouseholderService) ViewApprovedRequests(householderID string, limit, offset int, sortOrder string) ([]model.ServiceRequest, error) {
 	// Retrieve all service requests for the householder
@@ -310,7 +518,7 @@ func (s *HouseholderService) ViewApprovedRequests(householderID string, limit, o
 	// Filter to only include approved requests
 	var approvedRequests []model.ServiceRequest
 	for _, req := range serviceRequests {
-		if req.ApproveStatus && req.Status != "Cancelled" {
+		if req.ApproveStatus && req.Status != model.StatusCancelled {
 			approvedRequests = append(approvedRequests, req)
 		}
 	}
@@ -318,6 +526,9 @@ func (s *HouseholderService) ViewApprovedRequests(householderID string, limit, o
 	if len(approvedRequests) == 0 {
 		return nil, errors.New(errs.NoApproveRequestFound)
 	}
+	if err := s.attachCancellationTerms(approvedRequests); err != nil {
+		return nil, err
+	}
 
 	return approvedRequests, nil
 }
@@ -329,3 +540,113 @@ func (s *HouseholderService) GetAllServiceCategory() ([]model.Category, error) {
 	}
 	return categories, nil
 }
+
+// GetServiceRequestHistory returns the status history of a request. Householders may only see
+// their own requests and providers only requests they have quoted on; admins see everything.
+func (s *HouseholderService) GetServiceRequestHistory(requestID, userID, role string) ([]model.ServiceRequestEvent, error) {
+	if err := s.authorizeRequestView(requestID, userID, role); err != nil {
+		return nil, err
+	}
+	return s.requestEventRepo.GetEventsByRequestID(requestID)
+}
+
+// authorizeRequestView checks that the user may look into a request: its householder, a provider who
+// quoted on it, or an admin
+func (s *HouseholderService) authorizeRequestView(requestID, userID, role string) error {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return err
+	}
+
+	switch role {
+	case "Admin":
+	case "Householder":
+		if request.HouseholderID == nil || *request.HouseholderID != userID {
+			return errors.New(errs.RequestNotBelongToHouseholder)
+		}
+	case "ServiceProvider":
+		if _, err := s.serviceRequestRepo.GetServiceProviderByRequestID(requestID, userID); err != nil {
+			return errors.New(errs.RequestNotInvolveProvider)
+		}
+	default:
+		return errors.New(errs.RequestNotInvolveProvider)
+	}
+	return nil
+}
+
+// ConfirmServiceCompletion lets the householder confirm a job the provider has marked as finished
+func (s *HouseholderService) ConfirmServiceCompletion(requestID, householderID string) error {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return err
+	}
+
+	if request.HouseholderID == nil || *request.HouseholderID != householderID {
+		return errors.New(errs.RequestNotBelongToHouseholder)
+	}
+	if request.Status != model.StatusCompleted {
+		return errors.New(errs.RequestNotCompleted)
+	}
+	if request.CompletionConfirmed {
+		return errors.New(errs.CompletionAlreadyConfirmed)
+	}
+
+	request.CompletionConfirmed = true
+	if err := s.serviceRequestRepo.UpdateServiceRequest(request, request.Status); err != nil {
+		return fmt.Errorf("%v: %v", errs.NotUpdateRequest, err)
+	}
+	return s.requestEventRepo.SaveEvent(newRequestEvent(request.ID, request.Status, request.Status, householderID, "Householder", "completion confirmed by householder"))
+}
+
+// editableReview loads a review and ensures the householder wrote it and is still inside the edit window
+func (s *HouseholderService) editableReview(reviewID, householderID string) (*model.Review, error) {
+	review, err := s.providerRepo.GetReviewByID(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.HouseholderID != householderID {
+		return nil, errors.New(errs.ReviewNotBelongToHouseholder)
+	}
+	if time.Since(review.ReviewDate) > config.REVIEW_EDIT_WINDOW {
+		return nil, errors.New(errs.ReviewEditWindowExpired)
+	}
+	return review, nil
+}
+
+// UpdateReview lets a householder change the rating and comments of their own review
+func (s *HouseholderService) UpdateReview(reviewID, householderID, comments string, rating float64) error {
+	review, err := s.editableReview(reviewID, householderID)
+	if err != nil {
+		return err
+	}
+
+	updatedAt := time.Now().UTC()
+	review.Comments = comments
+	review.Rating = rating
+	review.UpdatedAt = &updatedAt
+	if err := s.providerRepo.UpdateReview(review); err != nil {
+		return err
+	}
+
+	if err := s.ratingRepo.RecalculateProviderRating(review.ProviderID, review.ServiceID); err != nil {
+		return errors.New(errs.FailUpdateRating)
+	}
+	return nil
+}
+
+// DeleteReview lets a householder remove their own review
+func (s *HouseholderService) DeleteReview(reviewID, householderID string) error {
+	review, err := s.editableReview(reviewID, householderID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.providerRepo.DeleteReview(review.ID); err != nil {
+		return err
+	}
+
+	if err := s.ratingRepo.RecalculateProviderRating(review.ProviderID, review.ServiceID); err != nil {
+		return errors.New(errs.FailUpdateRating)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type InvoiceService struct {
+	invoiceRepo        interfaces.InvoiceRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	quoteRepo          interfaces.QuoteRepository
+	paymentRepo        interfaces.PaymentRepository
+}
+
+// NewInvoiceService initializes a new InvoiceService
+func NewInvoiceService(invoiceRepo interfaces.InvoiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, quoteRepo interfaces.QuoteRepository, paymentRepo interfaces.PaymentRepository) interfaces.InvoiceService {
+	return &InvoiceService{
+		invoiceRepo:        invoiceRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		quoteRepo:          quoteRepo,
+		paymentRepo:        paymentRepo,
+	}
+}
+
+// GetInvoice returns the invoice of a completed request, issuing it the first time it is asked for.
+// Householders see the invoices of their own requests, providers the invoices they issued, and admins
+// every invoice.
+func (s *InvoiceService) GetInvoice(requestID, userID, role string) (*model.Invoice, error) {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != model.StatusCompleted {
+		return nil, errors.New(errs.InvoiceNotAvailable)
+	}
+	providerID, err := s.serviceRequestRepo.GetApprovedProviderIDByRequestID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	switch role {
+	case "Admin":
+	case "Householder":
+		if request.HouseholderID == nil || *request.HouseholderID != userID {
+			return nil, errors.New(errs.RequestNotBelongToHouseholder)
+		}
+	default:
+		if providerID != userID {
+			return nil, errors.New(errs.RequestNotInvolveProvider)
+		}
+	}
+
+	invoice, err := s.invoiceRepo.GetInvoiceByRequestID(requestID)
+	if err != nil {
+		if err.Error() != errs.InvoiceNotFound {
+			return nil, err
+		}
+		if invoice, err = s.issueInvoice(request, providerID); err != nil {
+			return nil, err
+		}
+	}
+
+	payments, err := s.paymentRepo.GetPaymentsByRequestID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range payments {
+		if payment.Status != model.PaymentFailed {
+			invoice.PaymentStatus = payment.Status
+		}
+	}
+	return invoice, nil
+}
+
+// issueInvoice builds and numbers the invoice of a completed request from its approved quote
+func (s *InvoiceService) issueInvoice(request *model.ServiceRequest, providerID string) (*model.Invoice, error) {
+	approved, err := s.serviceRequestRepo.GetServiceProviderByRequestID(request.ID, providerID)
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := s.quoteRepo.GetQuotesByRequestID(request.ID)
+	if err != nil {
+		return nil, err
+	}
+	var quote *model.Quote
+	for i := range quotes {
+		if quotes[i].ProviderID == providerID && quotes[i].Status == model.QuoteApproved {
+			quote = &quotes[i]
+		}
+	}
+
+	invoice, err := util.BuildInvoice(request, approved.ProviderDetails[0], quote, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.invoiceRepo.SaveInvoice(invoice); err != nil {
+		// A concurrent request may have issued the invoice first; the request can only have one
+		if existing, getErr := s.invoiceRepo.GetInvoiceByRequestID(request.ID); getErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return invoice, nil
+}
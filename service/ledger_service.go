@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type LedgerService struct {
+	ledgerRepo interfaces.LedgerRepository
+}
+
+// NewLedgerService initializes a new LedgerService
+func NewLedgerService(ledgerRepo interfaces.LedgerRepository) interfaces.LedgerService {
+	return &LedgerService{ledgerRepo: ledgerRepo}
+}
+
+// PostCompletedJobs posts the earnings of completed jobs to the ledger, splitting each job's approved
+// price between the provider and the platform's commission
+func (s *LedgerService) PostCompletedJobs() error {
+	jobs, err := s.ledgerRepo.GetUnpostedCompletedJobs(config.LEDGER_POSTING_BATCH)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if _, err := s.ledgerRepo.SaveJournal(util.EarningJournal(job, config.PLATFORM_COMMISSION_BASIS_POINTS)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetProviderEarnings summarises what the provider earned in [from, to), per service and per day, week
+// or month in location, together with what the platform owes them now
+func (s *LedgerService) GetProviderEarnings(providerID string, from, to time.Time, period string, location *time.Location) (*model.EarningsSummary, error) {
+	switch period {
+	case "day", "week", "month":
+	default:
+		return nil, errors.New(errs.InvalidEarningsPeriod)
+	}
+	if !from.Before(to) {
+		return nil, errors.New(errs.InvalidDateRange)
+	}
+	journals, err := s.ledgerRepo.GetProviderJournals(providerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.ledgerRepo.GetPayableBalances(providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &model.EarningsSummary{From: from.UTC(), To: to.UTC(), Period: period}
+	summary.Totals, summary.ByService, summary.ByPeriod = util.SummarizeEarnings(providerID, journals, period, location)
+	for _, balance := range balances {
+		summary.Payable = append(summary.Payable, balance.Amount)
+	}
+	return summary, nil
+}
+
+// CreatePayoutBatch pays every provider the platform owes money to their full balance, per currency.
+// Providers who owe the platform after refunds are left out until their balance is positive again. Batches
+// are created one at a time and their journals are posted all together or not at all.
+func (s *LedgerService) CreatePayoutBatch() (batch *model.PayoutBatch, err error) {
+	err = s.ledgerRepo.WithPayoutLock(func() error {
+		balances, err := s.ledgerRepo.GetPayableBalances("")
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		batch = &model.PayoutBatch{ID: util.GenerateUUID(), CreatedAt: now}
+		var journals []*model.LedgerJournal
+		for _, balance := range balances {
+			if balance.Amount.Amount <= 0 {
+				continue
+			}
+			journal := util.PayoutJournal(batch.ID, balance.ProviderID, balance.Amount, now)
+			journals = append(journals, journal)
+			batch.Payouts = append(batch.Payouts, model.ProviderPayout{ProviderID: balance.ProviderID, Amount: balance.Amount, JournalID: journal.ID})
+		}
+		if len(journals) == 0 {
+			return errors.New(errs.NothingToPayOut)
+		}
+		return s.ledgerRepo.SaveJournals(journals)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// GetPayoutBatch rebuilds a payout batch from its journals, e.g. to download its CSV again
+func (s *LedgerService) GetPayoutBatch(batchID string) (*model.PayoutBatch, error) {
+	journals, err := s.ledgerRepo.GetJournalsByReference(model.JournalPayout, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if len(journals) == 0 {
+		return nil, errors.New(errs.PayoutBatchNotFound)
+	}
+	batch := &model.PayoutBatch{ID: batchID, CreatedAt: journals[0].OccurredAt}
+	for _, journal := range journals {
+		for _, entry := range journal.Entries {
+			if entry.Account == model.AccountProviderPayable {
+				batch.Payouts = append(batch.Payouts, model.ProviderPayout{ProviderID: journal.ProviderID, Amount: entry.Amount, JournalID: journal.ID})
+			}
+		}
+	}
+	return batch, nil
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/interfaces"
+	"serviceNest/logger"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type PaymentService struct {
+	paymentRepo        interfaces.PaymentRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	requestEventRepo   interfaces.ServiceRequestEventRepository
+	ledgerRepo         interfaces.LedgerRepository
+	gateway            interfaces.PaymentGateway
+}
+
+// NewPaymentService initializes a new PaymentService that charges through the given gateway
+func NewPaymentService(paymentRepo interfaces.PaymentRepository, serviceRequestRepo interfaces.ServiceRequestRepository, requestEventRepo interfaces.ServiceRequestEventRepository, ledgerRepo interfaces.LedgerRepository, gateway interfaces.PaymentGateway) interfaces.PaymentService {
+	return &PaymentService{
+		paymentRepo:        paymentRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		requestEventRepo:   requestEventRepo,
+		ledgerRepo:         ledgerRepo,
+		gateway:            gateway,
+	}
+}
+
+// CreatePayment starts the householder's payment for an approved request at the approved provider's
+// price. The householder completes it with the gateway using the returned client secret. Jobs that are
+// already completed are charged right away whatever mode is asked for.
+func (s *PaymentService) CreatePayment(requestID, householderID string, mode model.CaptureMode) (*model.Payment, error) {
+	if mode != model.CaptureImmediate && mode != model.CaptureOnCompletion {
+		return nil, errors.New(errs.InvalidCaptureMode)
+	}
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.HouseholderID == nil || *request.HouseholderID != householderID {
+		return nil, errors.New(errs.RequestNotBelongToHouseholder)
+	}
+	switch request.Status {
+	case model.StatusApproved, model.StatusInProgress:
+	case model.StatusCompleted:
+		mode = model.CaptureImmediate
+	default:
+		return nil, errors.New(errs.RequestNotPayable)
+	}
+
+	payments, err := s.paymentRepo.GetPaymentsByRequestID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range payments {
+		if payment.Status != model.PaymentFailed {
+			return nil, errors.New(errs.PaymentAlreadyExists)
+		}
+	}
+
+	providerID, err := s.serviceRequestRepo.GetApprovedProviderIDByRequestID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	approved, err := s.serviceRequestRepo.GetServiceProviderByRequestID(requestID, providerID)
+	if err != nil {
+		return nil, err
+	}
+	// The provider's price on the request follows their quote, so it is the approved quote's total
+	amount := approved.ProviderDetails[0].Price
+	if amount.Amount <= 0 {
+		return nil, errors.New(errs.InvalidPrice)
+	}
+
+	intent, err := s.gateway.CreateIntent(amount, requestID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	payment := &model.Payment{
+		ID:            util.GenerateUUID(),
+		RequestID:     requestID,
+		HouseholderID: householderID,
+		ProviderID:    providerID,
+		Amount:        amount,
+		Refunded:      model.NewMoney(0, amount.Currency),
+		Status:        model.PaymentPending,
+		CaptureMode:   mode,
+		Gateway:       s.gateway.Name(),
+		IntentID:      intent.ID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	// A payment created at the same time wins; the intent made for this one is never used
+	if err := s.paymentRepo.SavePayment(payment); err != nil {
+		if err.Error() == errs.PaymentAlreadyExists {
+			if voidErr := s.gateway.Void(intent.ID); voidErr != nil {
+				logger.Error("Error voiding unused payment intent", map[string]interface{}{"intentID": intent.ID, "error": voidErr.Error()})
+			}
+		}
+		return nil, err
+	}
+	payment.ClientSecret = intent.ClientSecret
+	return payment, nil
+}
+
+// GetRequestPayments lists the payments on a request. Householders see the payments on their own
+// requests, providers the payments made to them, and admins every payment.
+func (s *PaymentService) GetRequestPayments(requestID, userID, role string) ([]model.Payment, error) {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "Householder" && (request.HouseholderID == nil || *request.HouseholderID != userID) {
+		return nil, errors.New(errs.RequestNotBelongToHouseholder)
+	}
+	payments, err := s.paymentRepo.GetPaymentsByRequestID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if role != "ServiceProvider" {
+		return payments, nil
+	}
+
+	var own []model.Payment
+	for _, payment := range payments {
+		if payment.ProviderID == userID {
+			own = append(own, payment)
+		}
+	}
+	return own, nil
+}
+
+// HandleWebhook applies a signed callback from the gateway. The event is recorded in the same transaction
+// as the status change it makes, so redelivered, concurrent or out-of-order events never apply twice.
+// Immediate payments are captured once authorized; a capture that fails here is retried by
+// CapturePendingPayments.
+func (s *PaymentService) HandleWebhook(payload []byte, signature string) error {
+	event, err := s.gateway.VerifyWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+	payment, err := s.paymentRepo.GetPaymentByIntentID(s.gateway.Name(), event.IntentID)
+	if err != nil {
+		return err
+	}
+
+	var next model.PaymentStatus
+	switch event.Type {
+	case model.WebhookPaymentAuthorized:
+		next = model.PaymentAuthorized
+	case model.WebhookPaymentCaptured:
+		next = model.PaymentCaptured
+	case model.WebhookPaymentFailed:
+		next = model.PaymentFailed
+	}
+	// Event types we do not act on, and events the payment has already moved past, are only recorded so
+	// the gateway stops resending them
+	fromStatus := payment.Status
+	changed := payment
+	if next == "" || !payment.Status.CanTransitionTo(next) {
+		changed = nil
+	} else {
+		payment.Status = next
+		payment.UpdatedAt = time.Now().UTC()
+	}
+	_, moved, err := s.paymentRepo.ApplyWebhookEvent(s.gateway.Name(), event, changed, fromStatus)
+	if err != nil {
+		return err
+	}
+	if moved && next == model.PaymentAuthorized && payment.CaptureMode == model.CaptureImmediate {
+		if err := s.capturePayment(payment); err != nil {
+			logger.Error("Error capturing payment", map[string]interface{}{"paymentID": payment.ID, "error": err.Error()})
+		}
+	}
+	return nil
+}
+
+// CapturePendingPayments charges the payments held until completion once the householder has confirmed
+// the job is done, and immediate payments whose capture failed when they were authorized
+func (s *PaymentService) CapturePendingPayments() error {
+	payments, err := s.paymentRepo.GetCapturablePayments()
+	if err != nil {
+		return err
+	}
+	for i := range payments {
+		if err := s.capturePayment(&payments[i]); err != nil {
+			logger.Error("Error capturing payment", map[string]interface{}{"paymentID": payments[i].ID, "error": err.Error()})
+		}
+	}
+	return nil
+}
+
+// RefundPayment returns part or, when amount is nil, the rest of a captured payment to the householder
+// and records the refund in the request's history. Refunds of completed jobs are also taken back from
+// the provider's earnings in the ledger. The refund is reserved on the payment before the gateway is
+// asked to pay it, so concurrent refunds can never add up to more than was paid.
+func (s *PaymentService) RefundPayment(paymentID, adminID string, amount *model.Money, reason string) (*model.Payment, error) {
+	return s.refund(paymentID, adminID, "Admin", amount, reason)
+}
+
+// refund is RefundPayment on behalf of any actor, such as the job settling cancelled requests
+func (s *PaymentService) refund(paymentID, actorID, actorRole string, amount *model.Money, reason string) (*model.Payment, error) {
+	payment, err := s.paymentRepo.GetPaymentByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != model.PaymentCaptured && payment.Status != model.PaymentPartiallyRefunded {
+		return nil, errors.New(errs.PaymentNotRefundable)
+	}
+	remaining, err := payment.Amount.Sub(payment.Refunded)
+	if err != nil {
+		return nil, err
+	}
+	refund := remaining
+	if amount != nil {
+		refund = *amount
+	}
+	if refund.Currency != payment.Amount.Currency {
+		return nil, errors.New(errs.CurrencyMismatch)
+	}
+	if refund.Amount <= 0 || refund.Amount > remaining.Amount {
+		return nil, errors.New(errs.RefundExceedsPayment)
+	}
+
+	reserved, err := s.paymentRepo.ReserveRefund(payment.ID, refund.Amount, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !reserved {
+		// Another refund got there first and left less to refund
+		return nil, errors.New(errs.RefundExceedsPayment)
+	}
+	refundID, err := s.gateway.Refund(payment.IntentID, refund)
+	if err != nil {
+		if releaseErr := s.paymentRepo.ReleaseRefund(payment.ID, refund.Amount, time.Now().UTC()); releaseErr != nil {
+			logger.Error("Error releasing refund", map[string]interface{}{"paymentID": payment.ID, "error": releaseErr.Error()})
+		}
+		return nil, err
+	}
+	if payment, err = s.paymentRepo.GetPaymentByID(payment.ID); err != nil {
+		return nil, &errs.RefundNotRecordedError{PaymentID: paymentID, Err: err}
+	}
+
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(payment.RequestID)
+	if err != nil {
+		return nil, &errs.RefundNotRecordedError{PaymentID: payment.ID, Err: err}
+	}
+	note := fmt.Sprintf("refunded %s of payment %s", refund, payment.ID)
+	if reason != "" {
+		note += ": " + reason
+	}
+	if err := s.requestEventRepo.SaveEvent(newRequestEvent(request.ID, request.Status, request.Status, actorID, actorRole, note)); err != nil {
+		return nil, &errs.RefundNotRecordedError{PaymentID: payment.ID, Err: err}
+	}
+	if request.Status == model.StatusCompleted {
+		journal := util.RefundJournal(payment, request, refund, refundID, config.PLATFORM_COMMISSION_BASIS_POINTS, payment.UpdatedAt)
+		if _, err := s.ledgerRepo.SaveJournal(journal); err != nil {
+			return nil, &errs.RefundNotRecordedError{PaymentID: payment.ID, Err: err}
+		}
+	}
+	return payment, nil
+}
+
+// SettleCancelledPayments settles the payments of cancelled requests: the householder's cancellation fee is
+// captured and the rest of the payment is released or, if it was captured already, refunded. Payments
+// whose settlement fails are retried on the next run.
+func (s *PaymentService) SettleCancelledPayments() error {
+	cancelled, err := s.paymentRepo.GetCancelledPayments()
+	if err != nil {
+		return err
+	}
+	for i := range cancelled {
+		if err := s.settleCancelledPayment(&cancelled[i].Payment, cancelled[i].Fee); err != nil {
+			logger.Error("Error settling payment of cancelled request", map[string]interface{}{"paymentID": cancelled[i].Payment.ID, "error": err.Error()})
+		}
+	}
+	return nil
+}
+
+func (s *PaymentService) settleCancelledPayment(payment *model.Payment, fee model.Money) error {
+	if fee.Amount > payment.Amount.Amount {
+		fee.Amount = payment.Amount.Amount
+	}
+	var note string
+	switch payment.Status {
+	case model.PaymentPending, model.PaymentAuthorized:
+		if payment.Status == model.PaymentPending || fee.IsZero() {
+			if err := s.gateway.Void(payment.IntentID); err != nil {
+				return err
+			}
+			moved, err := s.transitionPayment(payment, model.PaymentVoided)
+			if err != nil || !moved {
+				return err
+			}
+			note = fmt.Sprintf("voided payment %s of cancelled request", payment.ID)
+			break
+		}
+		// Only the fee is captured; the gateway releases the rest of the authorization
+		if err := s.gateway.Capture(payment.IntentID, fee); err != nil {
+			return err
+		}
+		authorized := payment.Amount
+		payment.Amount = fee
+		moved, err := s.transitionPayment(payment, model.PaymentCaptured)
+		if err != nil || !moved {
+			return err
+		}
+		note = fmt.Sprintf("captured cancellation fee %s of payment %s, released the remaining %s of %s", fee, payment.ID, model.NewMoney(authorized.Amount-fee.Amount, fee.Currency), authorized)
+	default:
+		refund := model.NewMoney(payment.Amount.Amount-payment.Refunded.Amount-fee.Amount, payment.Amount.Currency)
+		if refund.Amount <= 0 {
+			return nil
+		}
+		_, err := s.refund(payment.ID, "system", "System", &refund, fmt.Sprintf("request cancelled, cancellation fee %s kept", fee))
+		return err
+	}
+
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(payment.RequestID)
+	if err != nil {
+		return err
+	}
+	return s.requestEventRepo.SaveEvent(newRequestEvent(request.ID, request.Status, request.Status, "system", "System", note))
+}
+
+// capturePayment charges an authorized payment in full
+func (s *PaymentService) capturePayment(payment *model.Payment) error {
+	if err := s.gateway.Capture(payment.IntentID, payment.Amount); err != nil {
+		return err
+	}
+	_, err := s.transitionPayment(payment, model.PaymentCaptured)
+	return err
+}
+
+// transitionPayment moves the payment to next if its status allows it and reports whether this call
+// made the change. A payment another delivery already moved on is left alone.
+func (s *PaymentService) transitionPayment(payment *model.Payment, next model.PaymentStatus) (bool, error) {
+	if !payment.Status.CanTransitionTo(next) {
+		return false, nil
+	}
+	fromStatus := payment.Status
+	payment.Status = next
+	payment.UpdatedAt = time.Now().UTC()
+	return s.paymentRepo.UpdatePayment(payment, fromStatus)
+}
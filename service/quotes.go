@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+// ReviseQuote sends a new quote version in answer to the householder's counter-offer or to correct the
+// provider's own open quote. A revision without line items accepts the householder's counter-offer as is.
+// The request is locked meanwhile, so two revisions cannot both build on the same version.
+func (s *ServiceProviderService) ReviseQuote(providerID, requestID string, revision *model.Quote) (*model.Quote, error) {
+	err := s.quoteRepo.WithRequestLock(requestID, func() error {
+		request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+		if err != nil {
+			return err
+		}
+		latest, err := negotiableQuote(request, s.quoteRepo.GetLatestQuote, providerID)
+		if err != nil {
+			return err
+		}
+
+		if len(revision.LineItems) == 0 {
+			if latest.ProposedBy != "Householder" {
+				return errors.New(errs.InvalidQuote)
+			}
+			revision.LineItems = latest.LineItems
+		}
+		if err := prepareQuoteVersion(revision, requestID, providerID, latest.Version+1, "ServiceProvider", time.Now().UTC()); err != nil {
+			return err
+		}
+		if revision.Total.Currency != latest.Total.Currency {
+			return errors.New(errs.CurrencyMismatch)
+		}
+
+		if err := s.quoteRepo.SaveQuote(revision); err != nil {
+			return err
+		}
+		if err := s.quoteRepo.UpdateQuoteStatus(latest.ID, model.QuoteSuperseded); err != nil {
+			return err
+		}
+		return s.serviceProviderRepo.UpdateQuotedPrice(providerID, requestID, revision.Total)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+// CounterQuote answers the provider's latest quote with the householder's own line items. The provider
+// has to accept or revise the counter-offer before the householder can approve. Like ReviseQuote it
+// holds the request lock while it reads and supersedes the latest version.
+func (s *HouseholderService) CounterQuote(requestID, providerID, householderID string, counter *model.Quote) (*model.Quote, error) {
+	err := s.quoteRepo.WithRequestLock(requestID, func() error {
+		request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+		if err != nil {
+			return err
+		}
+		if request.HouseholderID == nil || *request.HouseholderID != householderID {
+			return errors.New(errs.RequestNotBelongToHouseholder)
+		}
+		latest, err := negotiableQuote(request, s.quoteRepo.GetLatestQuote, providerID)
+		if err != nil {
+			return err
+		}
+		if latest.ProposedBy != "ServiceProvider" {
+			return errors.New(errs.QuoteAwaitingProvider)
+		}
+
+		if err := prepareQuoteVersion(counter, requestID, providerID, latest.Version+1, "Householder", time.Now().UTC()); err != nil {
+			return err
+		}
+		if counter.Total.Currency != latest.Total.Currency {
+			return errors.New(errs.CurrencyMismatch)
+		}
+		if err := s.quoteRepo.SaveQuote(counter); err != nil {
+			return err
+		}
+		return s.quoteRepo.UpdateQuoteStatus(latest.ID, model.QuoteSuperseded)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counter, nil
+}
+
+// GetRequestQuotes returns the quote negotiation history of a request. Providers only see their own
+// quotes; householders and admins see every provider's.
+func (s *HouseholderService) GetRequestQuotes(requestID, userID, role string) ([]model.Quote, error) {
+	if err := s.authorizeRequestView(requestID, userID, role); err != nil {
+		return nil, err
+	}
+	quotes, err := s.quoteRepo.GetQuotesByRequestID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if role != "ServiceProvider" {
+		return quotes, nil
+	}
+
+	var own []model.Quote
+	for _, quote := range quotes {
+		if quote.ProviderID == userID {
+			own = append(own, quote)
+		}
+	}
+	return own, nil
+}
+
+// approvableQuote returns the quote the householder is approving: the latest version, proposed by the
+// provider and still valid. A version of 0 means the latest one. Requests quoted before quotes were
+// versioned have none and are approved at their recorded price.
+func (s *HouseholderService) approvableQuote(requestID, providerID string, version int) (*model.Quote, error) {
+	latest, err := s.quoteRepo.GetLatestQuote(requestID, providerID)
+	if err != nil {
+		if err.Error() == errs.QuoteNotFound && version == 0 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if version != 0 && version != latest.Version {
+		return nil, errors.New(errs.QuoteVersionNotLatest)
+	}
+	if latest.Status != model.QuoteOpen {
+		return nil, errors.New(errs.QuoteNotOpen)
+	}
+	if latest.ProposedBy != "ServiceProvider" {
+		return nil, errors.New(errs.QuoteAwaitingProvider)
+	}
+	if !time.Now().Before(latest.ValidUntil) {
+		return nil, errors.New(errs.QuoteExpired)
+	}
+	return latest, nil
+}
+
+// negotiableQuote returns the latest open quote between the provider and the householder, provided the
+// request is still waiting for approval
+func negotiableQuote(request *model.ServiceRequest, latestQuote func(requestID, providerID string) (*model.Quote, error), providerID string) (*model.Quote, error) {
+	if request.ApproveStatus {
+		return nil, errors.New(errs.RequestAlreadyApproved)
+	}
+	if request.Status != model.StatusAccepted {
+		return nil, errors.New(errs.QuoteNegotiationClosed)
+	}
+	latest, err := latestQuote(request.ID, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if latest.Status != model.QuoteOpen {
+		return nil, errors.New(errs.QuoteNotOpen)
+	}
+	if !time.Now().Before(latest.ValidUntil) {
+		return nil, errors.New(errs.QuoteExpired)
+	}
+	return latest, nil
+}
+
+// prepareQuoteVersion fills in the identity and default validity of a new quote version, then validates
+// it and computes its total
+func prepareQuoteVersion(quote *model.Quote, requestID, providerID string, version int, proposedBy string, now time.Time) error {
+	quote.ID = util.GenerateUUID()
+	quote.RequestID = requestID
+	quote.ProviderID = providerID
+	quote.Version = version
+	quote.ProposedBy = proposedBy
+	quote.Status = model.QuoteOpen
+	quote.CreatedAt = now
+	if quote.ValidUntil.IsZero() {
+		quote.ValidUntil = now.Add(config.DEFAULT_QUOTE_VALIDITY)
+	}
+	quote.ValidUntil = quote.ValidUntil.UTC()
+	return util.PrepareQuote(quote, now)
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+// changeRequestStatus moves the request to next through the status transition table and
+// returns the event describing the change. The caller saves the event once the request
+// itself has been persisted.
+func changeRequestStatus(request *model.ServiceRequest, next model.RequestStatus, actorID, actorRole, reason string) (*model.ServiceRequestEvent, error) {
+	previous := request.Status
+	if err := request.TransitionTo(next); err != nil {
+		return nil, err
+	}
+	return newRequestEvent(request.ID, previous, next, actorID, actorRole, reason), nil
+}
+
+// newRequestEvent builds a history entry for a request
+func newRequestEvent(requestID string, oldStatus, newStatus model.RequestStatus, actorID, actorRole, reason string) *model.ServiceRequestEvent {
+	return &model.ServiceRequestEvent{
+		ID:        util.GenerateUUID(),
+		RequestID: requestID,
+		ActorID:   actorID,
+		ActorRole: actorRole,
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"serviceNest/config"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"time"
+)
+
+type RequestExpiryService struct {
+	serviceRequestRepo  interfaces.ServiceRequestRepository
+	serviceProviderRepo interfaces.ServiceProviderRepository
+	requestEventRepo    interfaces.ServiceRequestEventRepository
+	quoteRepo           interfaces.QuoteRepository
+}
+
+// NewRequestExpiryService initializes a new RequestExpiryService
+func NewRequestExpiryService(serviceRequestRepo interfaces.ServiceRequestRepository, serviceProviderRepo interfaces.ServiceProviderRepository, requestEventRepo interfaces.ServiceRequestEventRepository, quoteRepo interfaces.QuoteRepository) interfaces.RequestExpiryService {
+	return &RequestExpiryService{
+		serviceRequestRepo:  serviceRequestRepo,
+		serviceProviderRepo: serviceProviderRepo,
+		requestEventRepo:    requestEventRepo,
+		quoteRepo:           quoteRepo,
+	}
+}
+
+// ExpireStaleRequests expires pending and accepted requests that were not approved before
+// config.REQUEST_EXPIRY_LEAD ahead of their scheduled time, then declines every quote that can no
+// longer be approved so providers stop waiting on them. Quote versions whose validity has passed are
+// expired as well.
+func (s *RequestExpiryService) ExpireStaleRequests() error {
+	requestIDs, err := s.serviceRequestRepo.GetStaleRequestIDs(time.Now().UTC().Add(config.REQUEST_EXPIRY_LEAD))
+	if err != nil {
+		return err
+	}
+
+	for _, requestID := range requestIDs {
+		request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+		if err != nil {
+			return err
+		}
+		// The request may have been approved or cancelled since it was listed
+		if request.ApproveStatus || !request.Status.CanTransitionTo(model.StatusExpired) {
+			continue
+		}
+		fromStatus := request.Status
+		event, err := changeRequestStatus(request, model.StatusExpired, "system", "System", "not approved before the expiry deadline")
+		if err != nil {
+			return err
+		}
+		expired, err := s.serviceRequestRepo.ExpireRequest(request.ID, fromStatus)
+		if err != nil {
+			return err
+		}
+		if !expired {
+			// Approved, accepted or cancelled while this run was looking at it
+			continue
+		}
+		if err := s.requestEventRepo.SaveEvent(event); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.serviceProviderRepo.DeclineUnapprovedQuotes(); err != nil {
+		return err
+	}
+	if _, err := s.quoteRepo.DeclineStaleQuotes(); err != nil {
+		return err
+	}
+	_, err = s.quoteRepo.ExpireLapsedQuotes(time.Now().UTC())
+	return err
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"serviceNest/logger"
+	"sync"
+	"time"
+)
+
+// Job is a background task the scheduler runs every Interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Scheduler runs background jobs on fixed intervals until it is stopped
+type Scheduler struct {
+	jobs     []Job
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewScheduler(jobs ...Job) *Scheduler {
+	return &Scheduler{jobs: jobs, stop: make(chan struct{})}
+}
+
+// Start runs every job once straight away and then on its interval, each in its own goroutine
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.run(job)
+	}
+}
+
+// Stop tells every job to finish and waits for runs in progress to return. It may be called more than once.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(job Job) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job.Run(); err != nil {
+			logger.Error("scheduled job failed", map[string]interface{}{"job": job.Name, "error": err.Error()})
+		}
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,22 +3,33 @@ package service
 import (
 	"errors"
 	"fmt"
+	"serviceNest/errs"
 	"serviceNest/interfaces"
 	"serviceNest/model"
+	"serviceNest/util"
+	"time"
 )
 
 type ServiceProviderService struct {
 	serviceProviderRepo interfaces.ServiceProviderRepository
 	serviceRequestRepo  interfaces.ServiceRequestRepository
 	serviceRepo         interfaces.ServiceRepository
+	requestEventRepo    interfaces.ServiceRequestEventRepository
+	availabilityRepo    interfaces.AvailabilityRepository
+	quoteRepo           interfaces.QuoteRepository
+	cancellationRepo    interfaces.CancellationRepository
 }
 
 // NewServiceProviderService initializes a new ServiceProviderService
-func NewServiceProviderService(serviceProviderRepo interfaces.ServiceProviderRepository, serviceRequestRepo interfaces.ServiceRequestRepository, serviceRepo interfaces.ServiceRepository) interfaces.ServiceProviderService {
+func NewServiceProviderService(serviceProviderRepo interfaces.ServiceProviderRepository, serviceRequestRepo interfaces.ServiceRequestRepository, serviceRepo interfaces.ServiceRepository, requestEventRepo interfaces.ServiceRequestEventRepository, availabilityRepo interfaces.AvailabilityRepository, quoteRepo interfaces.QuoteRepository, cancellationRepo interfaces.CancellationRepository) interfaces.ServiceProviderService {
 	return &ServiceProviderService{
 		serviceProviderRepo: serviceProviderRepo,
 		serviceRequestRepo:  serviceRequestRepo,
 		serviceRepo:         serviceRepo,
+		requestEventRepo:    requestEventRepo,
+		availabilityRepo:    availabilityRepo,
+		quoteRepo:           quoteRepo,
+		cancellationRepo:    cancellationRepo,
 	}
 }
 
@@ -92,23 +103,62 @@ func (s *ServiceProviderService) RemoveService(providerID, serviceID string) err
 	return nil
 }
 
-func (s *ServiceProviderService) AcceptServiceRequest(providerID, requestID string, estimatedPrice string) error {
+// AcceptServiceRequest records the provider's first quote on a request; later changes go through
+// ReviseQuote. It fails when the job overlaps another job the provider is committed to, and returns
+// warnings for jobs within the travel buffer.
+func (s *ServiceProviderService) AcceptServiceRequest(providerID, requestID string, quote *model.Quote) ([]model.ScheduleConflict, error) {
+	var warnings []model.ScheduleConflict
+	err := s.availabilityRepo.WithProviderLock(providerID, func() error {
+		var err error
+		warnings, err = s.acceptServiceRequest(providerID, requestID, quote)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func (s *ServiceProviderService) acceptServiceRequest(providerID, requestID string, quote *model.Quote) ([]model.ScheduleConflict, error) {
 	serviceRequest, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if serviceRequest.ApproveStatus {
-		return fmt.Errorf("service request has already been approved")
+		return nil, fmt.Errorf("service request has already been approved")
+	}
+
+	if _, err := s.quoteRepo.GetLatestQuote(requestID, providerID); err == nil {
+		return nil, errors.New(errs.QuoteAlreadySubmitted)
+	} else if err.Error() != errs.QuoteNotFound {
+		return nil, err
+	}
+	if err := prepareQuoteVersion(quote, requestID, providerID, 1, "ServiceProvider", time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	estimatedPrice := quote.Total
+
+	warnings, err := checkScheduleConflicts(s.availabilityRepo, providerID, serviceRequest.ServiceID, requestID, serviceRequest.ScheduledTime)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update the service request status to "Accepted"
-	serviceRequest.Status = "Accepted"
+	// Update the service request status to "Accepted"; further providers may quote on
+	// a request that another provider has already accepted
+	var event *model.ServiceRequestEvent
+	fromStatus := serviceRequest.Status
+	if serviceRequest.Status != model.StatusAccepted {
+		event, err = changeRequestStatus(serviceRequest, model.StatusAccepted, providerID, "ServiceProvider", "accepted by provider")
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Get the ServiceProvider details
 	provider, err := s.serviceProviderRepo.GetProviderDetailByID(providerID, serviceRequest.ServiceID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	provider.ServiceProviderID = providerID
@@ -126,15 +176,28 @@ func (s *ServiceProviderService) AcceptServiceRequest(providerID, requestID stri
 	})
 
 	// Save the updated service request
-	err = s.serviceRequestRepo.UpdateServiceRequest(serviceRequest)
+	err = s.serviceRequestRepo.UpdateServiceRequest(serviceRequest, fromStatus)
+	if err != nil {
+		return nil, err
+	}
 
 	err = s.serviceProviderRepo.SaveServiceProviderDetail(provider, requestID, serviceRequest.ServiceID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	if err := s.quoteRepo.SaveQuote(quote); err != nil {
+		return nil, err
+	}
+
+	if event != nil {
+		if err := s.requestEventRepo.SaveEvent(event); err != nil {
+			return nil, err
+		}
+	}
+	return warnings, nil
 }
+
 func (s *ServiceProviderService) GetServiceRequestByID(requestID string) (*model.ServiceRequest, error) {
 	return s.serviceRequestRepo.GetServiceRequestByID(requestID)
 }
@@ -147,13 +210,19 @@ func (s *ServiceProviderService) DeclineServiceRequest(providerID, requestID str
 		return err
 	}
 
-	if request.Status != "Pending" {
+	if request.Status != model.StatusPending {
 		return fmt.Errorf("service request is not pending")
 	}
 
 	// Decline the service_test request
-	request.Status = "Declined"
-	return s.serviceRequestRepo.UpdateServiceRequest(request)
+	event, err := changeRequestStatus(request, model.StatusDeclined, providerID, "ServiceProvider", "declined by provider")
+	if err != nil {
+		return err
+	}
+	if err := s.serviceRequestRepo.UpdateServiceRequest(request, event.OldStatus); err != nil {
+		return err
+	}
+	return s.requestEventRepo.SaveEvent(event)
 }
 
 // UpdateAvailability updates the provider's availability status
@@ -169,6 +238,57 @@ func (s *ServiceProviderService) UpdateAvailability(providerID string, availabil
 	return s.serviceProviderRepo.UpdateServiceProvider(provider)
 }
 
+// GetSchedule returns the provider's slot length, weekly working hours and upcoming time off
+func (s *ServiceProviderService) GetSchedule(providerID string) (*model.ProviderSchedule, error) {
+	return s.availabilityRepo.GetSchedule(providerID)
+}
+
+// UpdateSchedule replaces the provider's slot length, schedule timezone and weekly working hours. Without
+// a timezone the hours are read in the provider's own timezone.
+func (s *ServiceProviderService) UpdateSchedule(providerID string, slotMinutes int, timezone string, hours []model.WorkingHours) error {
+	if slotMinutes < 15 || slotMinutes > 480 {
+		return errors.New(errs.InvalidSlotLength)
+	}
+	if timezone != "" && !util.ValidTimezone(timezone) {
+		return errors.New(errs.InvalidTimezone)
+	}
+	if err := util.ValidateWorkingHours(hours); err != nil {
+		return err
+	}
+	return s.availabilityRepo.SaveSchedule(providerID, slotMinutes, timezone, hours)
+}
+
+// AddTimeOff blocks the provider's calendar between start and end
+func (s *ServiceProviderService) AddTimeOff(providerID string, start, end time.Time, reason string) (string, error) {
+	if !end.After(start) {
+		return "", errors.New(errs.InvalidTimeOffRange)
+	}
+	timeOff := &model.TimeOff{
+		ID:         util.GenerateUUID(),
+		ProviderID: providerID,
+		Start:      start,
+		End:        end,
+		Reason:     reason,
+	}
+	if err := s.availabilityRepo.AddTimeOff(timeOff); err != nil {
+		return "", err
+	}
+	return timeOff.ID, nil
+}
+
+// RemoveTimeOff deletes one of the provider's time off blocks
+func (s *ServiceProviderService) RemoveTimeOff(providerID, timeOffID string) error {
+	return s.availabilityRepo.DeleteTimeOff(providerID, timeOffID)
+}
+
+// UpdateServiceRadius sets the maximum distance, in kilometres, the provider travels for a job
+func (s *ServiceProviderService) UpdateServiceRadius(providerID string, radiusKm float64) error {
+	if radiusKm <= 0 {
+		return errors.New(errs.InvalidServiceRadius)
+	}
+	return s.serviceProviderRepo.UpdateServiceRadius(providerID, radiusKm)
+}
+
 //// ViewServices returns all services offered by a specific service_test provider
 //func (s *ServiceProviderService) ViewServices(providerID string) ([]model.Service, error) {
 //	provider, err := s.serviceProviderRepo.GetProviderByID(providerID)
@@ -213,3 +333,75 @@ func (s *ServiceProviderService) GetReviews(providerID string, limit, offset int
 	}
 	return reviews, nil
 }
+
+// approvedRequestForProvider loads a request and ensures the provider is the one the householder approved
+func (s *ServiceProviderService) approvedRequestForProvider(providerID, requestID string) (*model.ServiceRequest, error) {
+	providerRequest, err := s.serviceRequestRepo.GetServiceProviderByRequestID(requestID, providerID)
+	if err != nil {
+		return nil, errors.New(errs.RequestNotApprovedForProvider)
+	}
+	if len(providerRequest.ProviderDetails) == 0 || providerRequest.ProviderDetails[0].Approve != 1 {
+		return nil, errors.New(errs.RequestNotApprovedForProvider)
+	}
+	return s.serviceRequestRepo.GetServiceRequestByID(requestID)
+}
+
+// StartServiceRequest checks the approved provider in on the job and records the actual start time
+func (s *ServiceProviderService) StartServiceRequest(providerID, requestID string) error {
+	request, err := s.approvedRequestForProvider(providerID, requestID)
+	if err != nil {
+		return err
+	}
+
+	event, err := changeRequestStatus(request, model.StatusInProgress, providerID, "ServiceProvider", "job started by provider")
+	if err != nil {
+		return err
+	}
+	startTime := time.Now().UTC()
+	request.ActualStartTime = &startTime
+
+	if err := s.serviceRequestRepo.UpdateServiceRequest(request, event.OldStatus); err != nil {
+		return err
+	}
+	return s.requestEventRepo.SaveEvent(event)
+}
+
+// CompleteServiceRequest checks the approved provider out of the job and records the actual end time.
+// The householder still has to confirm the completion.
+func (s *ServiceProviderService) CompleteServiceRequest(providerID, requestID string) error {
+	request, err := s.approvedRequestForProvider(providerID, requestID)
+	if err != nil {
+		return err
+	}
+
+	event, err := changeRequestStatus(request, model.StatusCompleted, providerID, "ServiceProvider", "job finished by provider")
+	if err != nil {
+		return err
+	}
+	endTime := time.Now().UTC()
+	request.ActualEndTime = &endTime
+
+	if err := s.serviceRequestRepo.UpdateServiceRequest(request, event.OldStatus); err != nil {
+		return err
+	}
+	return s.requestEventRepo.SaveEvent(event)
+}
+
+// ReplyToReview posts the provider's single public reply on one of their reviews
+func (s *ServiceProviderService) ReplyToReview(providerID, reviewID, comments string) error {
+	review, err := s.serviceProviderRepo.GetReviewByID(reviewID)
+	if err != nil {
+		return err
+	}
+	if review.ProviderID != providerID {
+		return errors.New(errs.ReviewNotBelongToProvider)
+	}
+	if review.Reply != nil {
+		return errors.New(errs.ReviewAlreadyReplied)
+	}
+
+	return s.serviceProviderRepo.AddReviewReply(reviewID, model.ReviewReply{
+		Comments:  comments,
+		ReplyDate: time.Now().UTC(),
+	})
+}
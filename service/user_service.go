@@ -3,21 +3,27 @@ package service
 import (
 	"errors"
 	"fmt"
+	"serviceNest/config"
 	"serviceNest/errs"
 	"serviceNest/interfaces"
+	"serviceNest/logger"
 	"serviceNest/model"
-	"serviceNest/repository"
 	"serviceNest/util"
+	"time"
 )
 
 type UserService struct {
-	otpRepo  *repository.OtpRepository
-	userRepo interfaces.UserRepository
+	otpStore    interfaces.OtpStore
+	userRepo    interfaces.UserRepository
+	geocoder    interfaces.Geocoder
+	sessionRepo interfaces.SessionRepository
 }
 
-func NewUserService(userRepo interfaces.UserRepository, otpRepo *repository.OtpRepository) interfaces.UserService {
+func NewUserService(userRepo interfaces.UserRepository, otpStore interfaces.OtpStore, geocoder interfaces.Geocoder, sessionRepo interfaces.SessionRepository) interfaces.UserService {
 	return &UserService{userRepo: userRepo,
-		otpRepo: otpRepo}
+		otpStore:    otpStore,
+		geocoder:    geocoder,
+		sessionRepo: sessionRepo}
 }
 
 // View User
@@ -39,7 +45,7 @@ func (s *UserService) ViewProfileByID(userID string) (*model.User, error) {
 	return user, nil
 }
 
-func (s *UserService) UpdateUser(userID string, newEmail, newPassword, newAddress, newPhone *string) error {
+func (s *UserService) UpdateUser(userID string, newEmail, newPassword, newAddress, newPhone, newTimezone *string) error {
 	user, err := s.userRepo.GetUserByID(userID)
 	if err != nil {
 		return fmt.Errorf("could not find user: %v", err)
@@ -58,10 +64,12 @@ func (s *UserService) UpdateUser(userID string, newEmail, newPassword, newAddres
 	}
 
 	// Update password
+	passwordChanged := false
 	if newPassword != nil {
 		if err := util.ValidatePassword(*newPassword); err != nil {
 			return err
 		}
+		passwordChanged = *newPassword != user.Password
 		user.Password = *newPassword
 	}
 
@@ -76,12 +84,23 @@ func (s *UserService) UpdateUser(userID string, newEmail, newPassword, newAddres
 	if newAddress != nil {
 		user.Address = *newAddress
 	}
+	// Update preferred timezone
+	if newTimezone != nil {
+		if !util.ValidTimezone(*newTimezone) {
+			return errors.New(errs.InvalidTimezone)
+		}
+		user.Timezone = *newTimezone
+	}
 
 	// Save the updated user back to the repository_test
 	if err := s.userRepo.UpdateUser(user); err != nil {
 		return fmt.Errorf("could not update user: %v", err)
 	}
 
+	// A new password logs the user out everywhere
+	if passwordChanged {
+		return revokeSessions(s.sessionRepo, userID, "password changed")
+	}
 	return nil
 }
 
@@ -116,33 +135,112 @@ func (s *UserService) ForgetPasword(email string, answer string, updatedPassword
 		return err
 	}
 
-	return nil
+	return s.revokeSessionsByEmail(email)
 
 }
 
-func (s *UserService) GenerateOtp(email string) error {
+// GenerateOtp mails a new OTP for purpose to the user, replacing the one sent before for the same purpose
+func (s *UserService) GenerateOtp(email string, purpose model.OtpPurpose) error {
+	if !purpose.IsValid() {
+		return errors.New(errs.InvalidOtpPurpose)
+	}
 	_, err := s.userRepo.GetUserByEmail(email)
 	if err != nil {
 		return err
 	}
-	otp, err := s.otpRepo.GenerateOTP()
+	otp, err := util.GenerateOTP()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	err = s.otpStore.SaveOTP(&model.Otp{
+		Email:     util.ThrottleKey(email),
+		Purpose:   purpose,
+		CodeHash:  util.HashOTP(email, purpose, otp),
+		CreatedAt: now,
+		ExpiresAt: now.Add(config.OTP_TTL),
+	})
 	if err != nil {
 		return err
 	}
-	s.otpRepo.SaveOTP(email, otp)
 
-	return util.SendOTPEmail(email, otp)
+	return util.SendOTPEmail(email, otp, purpose)
+}
+
+// VerifyOtp uses up the OTP sent to email for purpose; OTPs sent for another purpose do not match
+func (s *UserService) VerifyOtp(email string, purpose model.OtpPurpose, otp string) error {
+	valid, err := s.otpStore.ConsumeOTP(util.ThrottleKey(email), purpose, util.HashOTP(email, purpose, otp), time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New(errs.InvalidOtp)
+	}
+	return nil
+}
+
+// PurgeExpiredOtps deletes the OTPs that expired without being used
+func (s *UserService) PurgeExpiredOtps() error {
+	deleted, err := s.otpStore.DeleteExpiredOTPs(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.Info("Deleted expired OTPs", map[string]interface{}{"count": deleted})
+	}
+	return nil
 }
 
 func (s *UserService) VerifyAndUpdatePassword(email, otp string, password string) error {
-	valid := s.otpRepo.ValidateOTP(email, otp)
-	if valid == false {
-		return errors.New("Invalid Otp")
+	if err := s.VerifyOtp(email, model.OtpPasswordReset, otp); err != nil {
+		return err
 	}
 	err := s.userRepo.UpdatePassword(email, password)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return s.revokeSessionsByEmail(email)
+}
+
+// revokeSessionsByEmail logs a user out everywhere after their password was reset
+func (s *UserService) revokeSessionsByEmail(email string) error {
+	user, err := s.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	return revokeSessions(s.sessionRepo, user.ID, "password reset")
+}
+
+// UpdateLocation sets the user's coordinates. Explicit coordinates win; otherwise the pincode is geocoded.
+func (s *UserService) UpdateLocation(userID string, pincode *string, latitude, longitude *float64) error {
+	code := ""
+	if pincode != nil {
+		code = *pincode
+	}
+
+	if latitude != nil && longitude != nil {
+		if !util.ValidCoordinates(*latitude, *longitude) {
+			return errors.New(errs.InvalidCoordinates)
+		}
+		return s.userRepo.UpdateUserLocation(userID, code, *latitude, *longitude)
+	}
+
+	if code == "" {
+		return errors.New(errs.LocationRequired)
+	}
+	lat, lng, err := s.geocoder.Geocode(code)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.UpdateUserLocation(userID, code, lat, lng)
+}
+
+// GetUserTimezone returns the user's preferred IANA timezone, or "" if none is set
+func (s *UserService) GetUserTimezone(userID string) (string, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Timezone, nil
 }
@@ -493,7 +493,7 @@ func TestUpdateUserHandler(t *testing.T) {
 		}
 
 		// Mock UpdateUser service call
-		mockUserService.EXPECT().UpdateUser(userID, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockUserService.EXPECT().UpdateUser(userID, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 		// Call the handler
 		userController.UpdateUserHandler(rr, req)
@@ -612,7 +612,7 @@ func TestUpdateUserHandler(t *testing.T) {
 		}
 
 		// Mock UpdateUser to return an error
-		mockUserService.EXPECT().UpdateUser("1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("db update failed")).Times(1)
+		mockUserService.EXPECT().UpdateUser("1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("db update failed")).Times(1)
 
 		// Call the handler
 		userController.UpdateUserHandler(rr, req)
@@ -6,24 +6,41 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"serviceNest/middlewares"
+	"serviceNest/model"
 	"serviceNest/util"
 	"testing"
 )
 
+// stubAuthService reports the sessions in revoked as revoked
+type stubAuthService struct {
+	revoked map[string]bool
+}
+
+func (s *stubAuthService) StartSession(user *model.User) (*model.TokenPair, error) { return nil, nil }
+func (s *stubAuthService) RefreshSession(refreshToken string) (*model.TokenPair, error) {
+	return nil, nil
+}
+func (s *stubAuthService) EndSession(sessionID string) error { return nil }
+func (s *stubAuthService) IsSessionRevoked(sessionID string) (bool, error) {
+	return s.revoked[sessionID], nil
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "userID123", r.Context().Value("userID"))
 		assert.Equal(t, "Householder", r.Context().Value("role"))
+		assert.Equal(t, "session123", r.Context().Value("sessionID"))
 		w.WriteHeader(http.StatusOK)
 	})
+	authService := &stubAuthService{revoked: map[string]bool{"revokedSession": true}}
 
 	t.Run("Valid Token", func(t *testing.T) {
-		tokenString, _ := util.GenerateJWT("userID123", "Householder") // Implement this in your util
+		tokenString, _ := util.GenerateJWT("userID123", "Householder", "session123")
 		req := httptest.NewRequest(http.MethodGet, "/test", nil)
 		req.Header.Set("Authorization", "Bearer "+tokenString)
 
 		rr := httptest.NewRecorder()
-		middleware := middlewares.AuthMiddleware(handler)
+		middleware := middlewares.AuthMiddleware(authService)(handler)
 
 		middleware.ServeHTTP(rr, req)
 
@@ -33,7 +50,7 @@ func TestAuthMiddleware(t *testing.T) {
 	t.Run("Missing Token", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/test", nil)
 		rr := httptest.NewRecorder()
-		middleware := middlewares.AuthMiddleware(handler)
+		middleware := middlewares.AuthMiddleware(authService)(handler)
 
 		middleware.ServeHTTP(rr, req)
 
@@ -44,7 +61,19 @@ func TestAuthMiddleware(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/test", nil)
 		req.Header.Set("Authorization", "Bearer invalidToken")
 		rr := httptest.NewRecorder()
-		middleware := middlewares.AuthMiddleware(handler)
+		middleware := middlewares.AuthMiddleware(authService)(handler)
+
+		middleware.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("Revoked Session", func(t *testing.T) {
+		tokenString, _ := util.GenerateJWT("userID123", "Householder", "revokedSession")
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+tokenString)
+		rr := httptest.NewRecorder()
+		middleware := middlewares.AuthMiddleware(authService)(handler)
 
 		middleware.ServeHTTP(rr, req)
 
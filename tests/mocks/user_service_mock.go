@@ -64,17 +64,32 @@ func (mr *MockUserServiceMockRecorder) CreateUser(user interface{}) *gomock.Call
 }
 
 // UpdateUser mocks base method.
-func (m *MockUserService) UpdateUser(userID string, newEmail, newPassword, newAddress, newPhone *string) error {
+func (m *MockUserService) UpdateUser(userID string, newEmail, newPassword, newAddress, newPhone, newTimezone *string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", userID, newEmail, newPassword, newAddress, newPhone)
+	ret := m.ctrl.Call(m, "UpdateUser", userID, newEmail, newPassword, newAddress, newPhone, newTimezone)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockUserServiceMockRecorder) UpdateUser(userID, newEmail, newPassword, newAddress, newPhone interface{}) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) UpdateUser(userID, newEmail, newPassword, newAddress, newPhone, newTimezone interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), userID, newEmail, newPassword, newAddress, newPhone)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), userID, newEmail, newPassword, newAddress, newPhone, newTimezone)
+}
+
+// GetUserTimezone mocks base method.
+func (m *MockUserService) GetUserTimezone(userID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserTimezone", userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTimezone indicates an expected call of GetUserTimezone.
+func (mr *MockUserServiceMockRecorder) GetUserTimezone(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTimezone", reflect.TypeOf((*MockUserService)(nil).GetUserTimezone), userID)
 }
 
 // ViewProfileByID mocks base method.
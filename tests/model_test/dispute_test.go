@@ -0,0 +1,36 @@
+package model_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"testing"
+)
+
+func TestDisputeStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     model.DisputeStatus
+		to       model.DisputeStatus
+		expected bool
+	}{
+		{"Open to UnderReview", model.DisputeOpen, model.DisputeUnderReview, true},
+		{"Open to Resolved", model.DisputeOpen, model.DisputeResolved, true},
+		{"UnderReview to Resolved", model.DisputeUnderReview, model.DisputeResolved, true},
+		{"UnderReview to Open", model.DisputeUnderReview, model.DisputeOpen, false},
+		{"Resolved to Open", model.DisputeResolved, model.DisputeOpen, false},
+		{"Resolved to Resolved", model.DisputeResolved, model.DisputeResolved, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestDisputeOutcomeIsValid(t *testing.T) {
+	assert.True(t, model.DisputeFullRefund.IsValid())
+	assert.True(t, model.DisputeDismissed.IsValid())
+	assert.False(t, model.DisputeOutcome("refund").IsValid())
+	assert.False(t, model.DisputeOutcome("").IsValid())
+}
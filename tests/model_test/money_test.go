@@ -0,0 +1,75 @@
+package model_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		amount   string
+		currency string
+		expected int64
+	}{
+		{"499", "INR", 49900},
+		{"499.5", "INR", 49950},
+		{" 0.05 ", "USD", 5},
+		{"-12.30", "INR", -1230},
+		{"1500", "JPY", 1500},
+	}
+	for _, c := range cases {
+		money, err := model.ParseMoney(c.amount, c.currency)
+		assert.NoError(t, err, c.amount)
+		assert.Equal(t, model.NewMoney(c.expected, c.currency), money, c.amount)
+	}
+
+	for _, amount := range []string{"", "1.234", "abc", "1e3", "1,000"} {
+		_, err := model.ParseMoney(amount, "INR")
+		assert.Error(t, err, amount)
+	}
+	_, err := model.ParseMoney("1.5", "JPY")
+	assert.Error(t, err)
+	_, err = model.ParseMoney("10", "XYZ")
+	assert.Error(t, err)
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	assert.Equal(t, "499.50", model.NewMoney(49950, "INR").Decimal())
+	assert.Equal(t, "0.05", model.NewMoney(5, "USD").Decimal())
+	assert.Equal(t, "-1.20", model.NewMoney(-120, "INR").Decimal())
+	assert.Equal(t, "1500", model.NewMoney(1500, "JPY").Decimal())
+}
+
+func TestMoneyAddRejectsMixedCurrencies(t *testing.T) {
+	sum, err := model.NewMoney(1000, "INR").Add(model.NewMoney(250, "INR"))
+	assert.NoError(t, err)
+	assert.Equal(t, model.NewMoney(1250, "INR"), sum)
+
+	_, err = model.NewMoney(1000, "INR").Add(model.NewMoney(250, "USD"))
+	assert.Error(t, err)
+}
+
+func TestMoneyJSON(t *testing.T) {
+	encoded, err := json.Marshal(model.NewMoney(49950, "INR"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount": "499.50", "amount_minor": 49950, "currency": "INR"}`, string(encoded))
+
+	inputs := map[string]model.Money{
+		`{"amount": "499.50", "currency": "USD"}`:    model.NewMoney(49950, "USD"),
+		`{"amount": 0.1}`:                            model.NewMoney(10, "INR"),
+		`{"amount_minor": 49950, "currency": "INR"}`: model.NewMoney(49950, "INR"),
+		`499.5`:    model.NewMoney(49950, "INR"),
+		`"499.50"`: model.NewMoney(49950, "INR"),
+	}
+	for input, expected := range inputs {
+		var money model.Money
+		assert.NoError(t, json.Unmarshal([]byte(input), &money), input)
+		assert.Equal(t, expected, money, input)
+	}
+
+	var money model.Money
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": "0.001", "currency": "INR"}`), &money))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": "1", "currency": "XYZ"}`), &money))
+}
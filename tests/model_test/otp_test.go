@@ -0,0 +1,15 @@
+package model_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"testing"
+)
+
+func TestOtpPurposeIsValid(t *testing.T) {
+	assert.True(t, model.OtpPasswordReset.IsValid())
+	assert.True(t, model.OtpEmailVerification.IsValid())
+	assert.True(t, model.OtpLogin.IsValid())
+	assert.False(t, model.OtpPurpose("signup").IsValid())
+	assert.False(t, model.OtpPurpose("").IsValid())
+}
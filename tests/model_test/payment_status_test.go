@@ -0,0 +1,33 @@
+package model_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"testing"
+)
+
+func TestPaymentStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     model.PaymentStatus
+		to       model.PaymentStatus
+		expected bool
+	}{
+		{"Pending to Authorized", model.PaymentPending, model.PaymentAuthorized, true},
+		{"Authorized to Captured", model.PaymentAuthorized, model.PaymentCaptured, true},
+		{"Captured to PartiallyRefunded", model.PaymentCaptured, model.PaymentPartiallyRefunded, true},
+		{"PartiallyRefunded to Refunded", model.PaymentPartiallyRefunded, model.PaymentRefunded, true},
+		{"Pending to Failed", model.PaymentPending, model.PaymentFailed, true},
+		{"Pending to Captured", model.PaymentPending, model.PaymentCaptured, false},
+		{"Authorized to Authorized", model.PaymentAuthorized, model.PaymentAuthorized, false},
+		{"Captured to Failed", model.PaymentCaptured, model.PaymentFailed, false},
+		{"Failed to Authorized", model.PaymentFailed, model.PaymentAuthorized, false},
+		{"Refunded to PartiallyRefunded", model.PaymentRefunded, model.PaymentPartiallyRefunded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
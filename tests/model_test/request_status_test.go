@@ -0,0 +1,56 @@
+package model_test
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"testing"
+)
+
+func TestRequestStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     model.RequestStatus
+		to       model.RequestStatus
+		expected bool
+	}{
+		{"Pending to Accepted", model.StatusPending, model.StatusAccepted, true},
+		{"Accepted to Approved", model.StatusAccepted, model.StatusApproved, true},
+		{"Approved to InProgress", model.StatusApproved, model.StatusInProgress, true},
+		{"InProgress to Completed", model.StatusInProgress, model.StatusCompleted, true},
+		{"Pending to Expired", model.StatusPending, model.StatusExpired, true},
+		{"Pending to Approved", model.StatusPending, model.StatusApproved, false},
+		{"Cancelled to Approved", model.StatusCancelled, model.StatusApproved, false},
+		{"Cancelled to Accepted", model.StatusCancelled, model.StatusAccepted, false},
+		{"Completed to Cancelled", model.StatusCompleted, model.StatusCancelled, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestServiceRequestTransitionTo(t *testing.T) {
+	request := &model.ServiceRequest{ID: "1", Status: model.StatusCancelled}
+
+	err := request.TransitionTo(model.StatusApproved)
+
+	var transitionErr *errs.StatusTransitionError
+	assert.True(t, errors.As(err, &transitionErr))
+	assert.Equal(t, "Cancelled", transitionErr.From)
+	assert.Equal(t, "Approved", transitionErr.To)
+	assert.Equal(t, model.StatusCancelled, request.Status)
+
+	request.Status = model.StatusPending
+	assert.NoError(t, request.TransitionTo(model.StatusAccepted))
+	assert.Equal(t, model.StatusAccepted, request.Status)
+}
+
+func TestRequestStatusIsTerminal(t *testing.T) {
+	assert.True(t, model.StatusCompleted.IsTerminal())
+	assert.True(t, model.StatusDeclined.IsTerminal())
+	assert.False(t, model.StatusPending.IsTerminal())
+}
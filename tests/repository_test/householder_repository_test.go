@@ -67,10 +67,10 @@ func TestGetHouseholderByID(t *testing.T) {
 	}
 
 	// Set up the expectation for the SELECT query
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "address", "contact"}).
-		AddRow(expectedHouseholder.ID, expectedHouseholder.Name, expectedHouseholder.Email, expectedHouseholder.Password, expectedHouseholder.Role, expectedHouseholder.Address, expectedHouseholder.Contact)
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "address", "contact", "pincode", "latitude", "longitude"}).
+		AddRow(expectedHouseholder.ID, expectedHouseholder.Name, expectedHouseholder.Email, expectedHouseholder.Password, expectedHouseholder.Role, expectedHouseholder.Address, expectedHouseholder.Contact, nil, nil, nil)
 
-	mock.ExpectQuery("SELECT id, name, email, password, role, address, contact FROM users WHERE id = ?").
+	mock.ExpectQuery("SELECT id, name, email, password, role, address, contact, pincode, latitude, longitude FROM users WHERE id = ?").
 		WithArgs(expectedHouseholder.ID).
 		WillReturnRows(rows)
 
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"regexp"
+	"serviceNest/config"
 	"serviceNest/model"
 	"serviceNest/repository"
 	"serviceNest/tests/mocks"
@@ -221,7 +222,7 @@ func TestSaveServiceProviderDetail(t *testing.T) {
 		Name:              "John Doe",
 		Contact:           "1234567890",
 		Address:           "123 Main St",
-		Price:             "100",
+		Price:             model.NewMoney(10000, "INR"),
 		Rating:            4.5,
 		Approve:           true,
 	}
@@ -234,7 +235,7 @@ func TestSaveServiceProviderDetail(t *testing.T) {
 
 	// Mock the insert into service_provider_details
 	mock.ExpectExec("INSERT INTO service_provider_details").
-		WithArgs(sqlmock.AnyArg(), requestID, provider.ServiceProviderID, provider.Name, provider.Contact, provider.Address, provider.Price, provider.Rating, provider.Approve).
+		WithArgs(sqlmock.AnyArg(), requestID, provider.ServiceProviderID, provider.Name, provider.Contact, provider.Address, provider.Price.Amount, provider.Price.Currency, provider.Rating, provider.Approve).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = repo.SaveServiceProviderDetail(provider, requestID)
@@ -504,15 +505,10 @@ func TestGetProvidersByServiceType_Success(t *testing.T) {
 	}
 
 	// Expect the query and return rows
-	query := regexp.QuoteMeta(`
-		SELECT sp.user_id, sp.rating, sp.availability, sp.is_active
-		FROM service_providers sp
-		INNER JOIN service_providers_services sps ON sp.user_id = sps.service_provider_id
-		INNER JOIN services s ON sps.service_id = s.id
-		WHERE s.name = ?`)
-	rows := sqlmock.NewRows([]string{"user_id", "rating", "availability", "is_active"}).
-		AddRow("provider1", 4.5, true, true).
-		AddRow("provider2", 4.0, false, true)
+	query := regexp.QuoteMeta(config.ProvidersByServiceTypeQuery())
+	rows := sqlmock.NewRows([]string{"user_id", "name", "address", "contact", "latitude", "longitude", "rating", "availability", "is_active", "service_radius_km"}).
+		AddRow("provider1", "", "", "", nil, nil, 4.5, true, true, nil).
+		AddRow("provider2", "", "", "", nil, nil, 4.0, false, true, nil)
 	mock.ExpectQuery(query).WithArgs("Plumbing").WillReturnRows(rows)
 
 	// Call the function
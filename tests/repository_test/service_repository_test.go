@@ -67,13 +67,13 @@ func TestSaveService(t *testing.T) {
 		ID:          "service123",
 		Name:        "Service A",
 		Description: "Description A",
-		Price:       100.0,
+		Price:       model.NewMoney(10000, "INR"),
 		ProviderID:  "provider123",
 		Category:    "Category A",
 	}
 
 	mock.ExpectExec("INSERT INTO services").
-		WithArgs(service.ID, service.Name, service.Description, service.Price, service.ProviderID, service.Category).
+		WithArgs(service.ID, service.Name, service.Description, service.Price.Amount, service.Price.Currency, service.ProviderID, service.Category).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	err = repo.SaveService(service)
@@ -136,7 +136,7 @@ func TestGetServiceByName_Success(t *testing.T) {
 		ID:          "service123",
 		Name:        "Plumbing",
 		Description: "Fix water issues",
-		Price:       100.0,
+		Price:       model.NewMoney(10000, "INR"),
 		ProviderID:  "provider123",
 		Category:    "Home",
 	}
@@ -144,7 +144,7 @@ func TestGetServiceByName_Success(t *testing.T) {
 	// Mock the query result
 	query := regexp.QuoteMeta("SELECT id, name, description, price, provider_id, category FROM services WHERE name = ?")
 	rows := sqlmock.NewRows([]string{"id", "name", "description", "price", "provider_id", "category"}).
-		AddRow(expectedService.ID, expectedService.Name, expectedService.Description, expectedService.Price, expectedService.ProviderID, expectedService.Category)
+		AddRow(expectedService.ID, expectedService.Name, expectedService.Description, expectedService.Price.Amount, expectedService.Price.Currency, expectedService.ProviderID, expectedService.Category)
 	mock.ExpectQuery(query).WithArgs("Plumbing").WillReturnRows(rows)
 
 	// Call the function
@@ -218,9 +218,9 @@ func TestUpdateService_Success(t *testing.T) {
 
 	// Prepare mock expectation
 
-	query := regexp.QuoteMeta("UPDATE services SET name = ?, description = ?, price = ? WHERE provider_id = ? AND id = ?")
+	query := regexp.QuoteMeta("UPDATE services SET name = ?, description = ?, price = ?, estimated_minutes = ? WHERE provider_id = ? AND id = ?")
 	mock.ExpectExec(query).
-		WithArgs("ServiceName", "ServiceDescription", 100.0, "provider1", "service1").
+		WithArgs("ServiceName", "ServiceDescription", 100.0, 0, "provider1", "service1").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Create the service to update
@@ -228,7 +228,7 @@ func TestUpdateService_Success(t *testing.T) {
 		ID:          "service1",
 		Name:        "ServiceName",
 		Description: "ServiceDescription",
-		Price:       100.0,
+		Price:       model.NewMoney(10000, "INR"),
 	}
 
 	// Call the method
@@ -249,9 +249,9 @@ func TestUpdateService_NoRowsAffected(t *testing.T) {
 	repo := repository.NewServiceRepository(db)
 
 	// Prepare mock expectation
-	query := regexp.QuoteMeta("UPDATE services SET name = ?, description = ?, price = ? WHERE provider_id = ? AND id = ?")
+	query := regexp.QuoteMeta("UPDATE services SET name = ?, description = ?, price = ?, estimated_minutes = ? WHERE provider_id = ? AND id = ?")
 	mock.ExpectExec(query).
-		WithArgs("ServiceName", "ServiceDescription", 100.0, "provider1", "service1").
+		WithArgs("ServiceName", "ServiceDescription", 100.0, 0, "provider1", "service1").
 		WillReturnResult(sqlmock.NewResult(1, 0))
 
 	// Create the service to update
@@ -259,7 +259,7 @@ func TestUpdateService_NoRowsAffected(t *testing.T) {
 		ID:          "service1",
 		Name:        "ServiceName",
 		Description: "ServiceDescription",
-		Price:       100.0,
+		Price:       model.NewMoney(10000, "INR"),
 	}
 
 	// Call the method
@@ -279,9 +279,9 @@ func TestUpdateService_Error(t *testing.T) {
 	// Create the repository with the mock database
 	repo := repository.NewServiceRepository(db)
 	// Prepare mock expectation
-	query := regexp.QuoteMeta("UPDATE services SET name = ?, description = ?, price = ? WHERE provider_id = ? AND id = ?")
+	query := regexp.QuoteMeta("UPDATE services SET name = ?, description = ?, price = ?, estimated_minutes = ? WHERE provider_id = ? AND id = ?")
 	mock.ExpectExec(query).
-		WithArgs("ServiceName", "ServiceDescription", 100.0, "provider1", "service1").
+		WithArgs("ServiceName", "ServiceDescription", 100.0, 0, "provider1", "service1").
 		WillReturnError(errors.New("some database error"))
 
 	// Create the service to update
@@ -289,7 +289,7 @@ func TestUpdateService_Error(t *testing.T) {
 		ID:          "service1",
 		Name:        "ServiceName",
 		Description: "ServiceDescription",
-		Price:       100.0,
+		Price:       model.NewMoney(10000, "INR"),
 	}
 
 	// Call the method
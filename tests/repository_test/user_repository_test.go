@@ -122,7 +122,7 @@ func TestUpdateUser(t *testing.T) {
 
 	// Mock Exec for updating user
 	mock.ExpectExec("UPDATE users").
-		WithArgs("John Updated", "john@example.com", "new_hashed_password", "admin", "123 New St", "0987654321", "123").
+		WithArgs("John Updated", "john@example.com", "new_hashed_password", "admin", "123 New St", "0987654321", nil, "123").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Create updated user
@@ -154,8 +154,8 @@ func TestGetUserByID(t *testing.T) {
 	repo := repository.NewUserRepository(db)
 
 	// Mock row returned by query
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "address", "contact"}).
-		AddRow("123", "John Doe", "john@example.com", "hashed_password", "user", "123 Main St", "1234567890")
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "address", "contact", "pincode", "latitude", "longitude", "timezone"}).
+		AddRow("123", "John Doe", "john@example.com", "hashed_password", "user", "123 Main St", "1234567890", "560001", 12.9763, 77.6033, "Asia/Kolkata")
 
 	// Expect the query with the provided user ID
 	mock.ExpectQuery("SELECT id, name, email, password").
@@ -0,0 +1,45 @@
+package response_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"serviceNest/response"
+	"testing"
+	"time"
+)
+
+type booking struct {
+	ID        string     `json:"id"`
+	Scheduled time.Time  `json:"scheduled"`
+	Finished  *time.Time `json:"finished,omitempty"`
+}
+
+func TestSuccessResponseRendersTimesInLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	scheduled := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	data := []booking{{ID: "1", Scheduled: scheduled, Finished: &scheduled}}
+
+	recorder := httptest.NewRecorder()
+	response.SuccessResponse(response.WithLocation(recorder, berlin), data, "ok", http.StatusOK)
+
+	var body struct {
+		Data []map[string]string `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	assert.Equal(t, "2024-06-01T10:00:00+02:00", body.Data[0]["scheduled"])
+	assert.Equal(t, "2024-06-01T10:00:00+02:00", body.Data[0]["finished"])
+
+	// The caller's data is left untouched
+	assert.Equal(t, time.UTC, data[0].Scheduled.Location())
+}
+
+func TestSuccessResponseWithoutLocationKeepsTimes(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	scheduled := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	response.SuccessResponse(recorder, map[string]interface{}{"scheduled": scheduled}, "ok", http.StatusOK)
+
+	assert.Contains(t, recorder.Body.String(), "2024-06-01T08:00:00Z")
+}
@@ -137,7 +137,7 @@ func TestUpdateUser(t *testing.T) {
 			tt.mockGetUserByID(mockUserRepo)
 			tt.mockGetUserByEmail(mockUserRepo)
 			tt.mockUpdateUser(mockUserRepo)
-			err := userService.UpdateUser(userID, tt.newEmail, tt.newPassword, tt.newAddress, tt.newPhone)
+			err := userService.UpdateUser(userID, tt.newEmail, tt.newPassword, tt.newAddress, tt.newPhone, nil)
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
@@ -0,0 +1,75 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func TestEvaluateCancellationTiers(t *testing.T) {
+	policy := &model.CancellationPolicy{ID: "pol1", Scope: model.PolicyScopeCategory, FreeWindowHours: 24,
+		LateFeeBasisPoints: 2500, NoShowFeeBasisPoints: 10000, ProviderLatePenalty: 2, ProviderNoShowPenalty: 4}
+	price := model.NewMoney(49999, "INR")
+	scheduled := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+
+	free := util.EvaluateCancellation(policy, price, scheduled, scheduled.Add(-25*time.Hour), false)
+	assert.Equal(t, model.CancellationFree, free.Tier)
+	assert.True(t, free.Fee.IsZero())
+	assert.Equal(t, scheduled.Add(-24*time.Hour), *free.FreeUntil)
+	assert.Equal(t, "pol1", free.PolicyID)
+
+	// 25% of 49999 is 12499.75, rounded to the nearest minor unit
+	late := util.EvaluateCancellation(policy, price, scheduled, scheduled.Add(-2*time.Hour), false)
+	assert.Equal(t, model.CancellationLate, late.Tier)
+	assert.Equal(t, model.NewMoney(12500, "INR"), late.Fee)
+	assert.Zero(t, late.PenaltyPoints)
+
+	noShow := util.EvaluateCancellation(policy, price, scheduled, scheduled.Add(time.Minute), false)
+	assert.Equal(t, model.CancellationNoShow, noShow.Tier)
+	assert.Equal(t, price, noShow.Fee)
+}
+
+func TestEvaluateCancellationByProvider(t *testing.T) {
+	policy := &model.CancellationPolicy{FreeWindowHours: 4, LateFeeBasisPoints: 5000, ProviderLatePenalty: 3, ProviderNoShowPenalty: 5}
+	price := model.NewMoney(10000, "INR")
+	scheduled := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+
+	late := util.EvaluateCancellation(policy, price, scheduled, scheduled.Add(-time.Hour), true)
+	assert.Equal(t, 3, late.PenaltyPoints)
+	assert.True(t, late.Fee.IsZero())
+
+	// Providers always lose some reliability, even inside the free window
+	early := util.EvaluateCancellation(policy, price, scheduled, scheduled.Add(-48*time.Hour), true)
+	assert.Equal(t, model.CancellationFree, early.Tier)
+	assert.Equal(t, config.PROVIDER_CANCELLATION_PENALTY, early.PenaltyPoints)
+}
+
+func TestDefaultCancellationPolicy(t *testing.T) {
+	policy := util.DefaultCancellationPolicy()
+	scheduled := time.Date(2026, 10, 20, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, model.CancellationFree, util.EvaluateCancellation(policy, model.NewMoney(10000, "INR"), scheduled, scheduled.Add(-config.CANCELLATION_NOTICE-time.Minute), false).Tier)
+	assert.Equal(t, model.CancellationLate, util.EvaluateCancellation(policy, model.NewMoney(10000, "INR"), scheduled, scheduled.Add(-time.Hour), false).Tier)
+}
+
+func TestValidateCancellationPolicy(t *testing.T) {
+	valid := &model.CancellationPolicy{Scope: model.PolicyScopeCategory, ScopeID: "Plumbing", FreeWindowHours: 24, LateFeeBasisPoints: 5000, NoShowFeeBasisPoints: 10000}
+	assert.NoError(t, util.ValidateCancellationPolicy(valid))
+
+	for _, policy := range []model.CancellationPolicy{
+		{Scope: model.PolicyScopeDefault, ScopeID: "x"},
+		{Scope: model.PolicyScopeProvider},
+		{Scope: model.PolicyScopeProvider, ScopeID: "p1", FreeWindowHours: -1},
+		{Scope: model.PolicyScopeProvider, ScopeID: "p1", LateFeeBasisPoints: 10001},
+		{Scope: model.PolicyScopeProvider, ScopeID: "p1", ProviderNoShowPenalty: -2},
+	} {
+		err := util.ValidateCancellationPolicy(&policy)
+		if assert.Error(t, err) {
+			assert.Equal(t, errs.InvalidCancellationPolicy, err.Error())
+		}
+	}
+}
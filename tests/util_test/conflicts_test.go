@@ -0,0 +1,37 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func TestFindConflicts(t *testing.T) {
+	bookings := []model.Booking{
+		{RequestID: "overlap", Start: at(3, 9, 30), End: at(3, 10, 30)},
+		{RequestID: "tight", Start: at(3, 11, 15), End: at(3, 12, 0)},
+		{RequestID: "clear", Start: at(3, 13, 0), End: at(3, 14, 0)},
+	}
+	job := model.TimeRange{Start: at(3, 10, 0), End: at(3, 11, 0)}
+
+	conflicts := util.FindConflicts(job, bookings, 30*time.Minute)
+
+	assert.Equal(t, []model.ScheduleConflict{
+		{RequestID: "overlap", Start: at(3, 9, 30), End: at(3, 10, 30), Overlap: true},
+		{RequestID: "tight", Start: at(3, 11, 15), End: at(3, 12, 0), Overlap: false},
+	}, conflicts)
+}
+
+func TestFindConflictsBackToBackWithoutBuffer(t *testing.T) {
+	bookings := []model.Booking{{RequestID: "next", Start: at(3, 11, 0), End: at(3, 12, 0)}}
+	job := model.TimeRange{Start: at(3, 10, 0), End: at(3, 11, 0)}
+
+	assert.Empty(t, util.FindConflicts(job, bookings, 0))
+}
+
+func TestJobDuration(t *testing.T) {
+	assert.Equal(t, 90*time.Minute, util.JobDuration(90))
+	assert.Equal(t, 60*time.Minute, util.JobDuration(0))
+}
@@ -0,0 +1,50 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+)
+
+func TestFakePaymentGateway_WebhookSignature(t *testing.T) {
+	gateway := repository.NewFakePaymentGateway("webhook-secret")
+	intent, err := gateway.CreateIntent(model.NewMoney(49950, "INR"), "request-1")
+	assert.NoError(t, err)
+
+	payload, signature, err := gateway.Authorize(intent.ID)
+	assert.NoError(t, err)
+
+	event, err := gateway.VerifyWebhook(payload, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, model.WebhookPaymentAuthorized, event.Type)
+	assert.Equal(t, intent.ID, event.IntentID)
+
+	_, err = gateway.VerifyWebhook(append(payload, ' '), signature)
+	assert.EqualError(t, err, errs.InvalidWebhookSignature)
+
+	other := repository.NewFakePaymentGateway("another-secret")
+	_, err = other.VerifyWebhook(payload, signature)
+	assert.EqualError(t, err, errs.InvalidWebhookSignature)
+}
+
+func TestFakePaymentGateway_CaptureAndRefund(t *testing.T) {
+	gateway := repository.NewFakePaymentGateway("webhook-secret")
+	intent, err := gateway.CreateIntent(model.NewMoney(10000, "INR"), "request-1")
+	assert.NoError(t, err)
+
+	assert.EqualError(t, gateway.Capture(intent.ID, intent.Amount), errs.PaymentNotCapturable)
+
+	_, _, err = gateway.Authorize(intent.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, gateway.Capture(intent.ID, intent.Amount))
+	assert.EqualError(t, gateway.Capture(intent.ID, intent.Amount), errs.PaymentNotCapturable)
+
+	_, err = gateway.Refund(intent.ID, model.NewMoney(4000, "INR"))
+	assert.NoError(t, err)
+	_, err = gateway.Refund(intent.ID, model.NewMoney(6001, "INR"))
+	assert.EqualError(t, err, errs.RefundExceedsPayment)
+	_, err = gateway.Refund(intent.ID, model.NewMoney(6000, "USD"))
+	assert.EqualError(t, err, errs.CurrencyMismatch)
+}
@@ -0,0 +1,23 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/util"
+	"testing"
+)
+
+func TestHaversineDistance(t *testing.T) {
+	// Connaught Place, New Delhi to Fort, Mumbai is roughly 1166 km as the crow flies
+	distance := util.HaversineDistance(28.6328, 77.2197, 18.9388, 72.8354)
+	assert.InDelta(t, 1166, distance, 5)
+
+	assert.InDelta(t, 0, util.HaversineDistance(12.97, 77.59, 12.97, 77.59), 1e-9)
+	assert.InDelta(t, util.HaversineDistance(0, 0, 1, 1), util.HaversineDistance(1, 1, 0, 0), 1e-9)
+}
+
+func TestValidCoordinates(t *testing.T) {
+	assert.True(t, util.ValidCoordinates(12.97, 77.59))
+	assert.True(t, util.ValidCoordinates(-90, 180))
+	assert.False(t, util.ValidCoordinates(91, 0))
+	assert.False(t, util.ValidCoordinates(0, -181))
+}
@@ -0,0 +1,31 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+)
+
+func TestInMemoryServiceSearcher_Search(t *testing.T) {
+	searcher := repository.NewInMemoryServiceSearcher([]model.Service{
+		{ID: "1", Name: "Pipe repair", Category: "Plumbing", ProviderName: "Ravi"},
+		{ID: "2", Name: "Wall painting", Category: "Painting", ProviderName: "Asha"},
+		{ID: "3", Name: "Plumber visit", Category: "Plumbing", ProviderName: "Kiran"},
+	})
+
+	results, err := searcher.Search("plumbr", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "3", results[0].Service.ID)
+
+	results, err = searcher.Search("plumbr", 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "1", results[0].Service.ID)
+
+	results, err = searcher.Search("asha", 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "<em>Asha</em>", results[0].Highlights["provider_name"])
+}
@@ -0,0 +1,67 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/util"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitInclusiveTax(t *testing.T) {
+	taxable, tax := util.SplitInclusiveTax(model.NewMoney(11800, "INR"), 1800)
+	assert.Equal(t, model.NewMoney(10000, "INR"), taxable)
+	assert.Equal(t, model.NewMoney(1800, "INR"), tax)
+
+	// 49950 / 1.18 = 42330.5..., rounded half up
+	taxable, tax = util.SplitInclusiveTax(model.NewMoney(49950, "INR"), 1800)
+	assert.Equal(t, int64(42331), taxable.Amount)
+	assert.Equal(t, int64(7619), tax.Amount)
+
+	taxable, tax = util.SplitInclusiveTax(model.NewMoney(500, "JPY"), 0)
+	assert.Equal(t, int64(500), taxable.Amount)
+	assert.True(t, tax.IsZero())
+}
+
+func TestInvoiceNumber(t *testing.T) {
+	assert.Equal(t, "SN-3F2A9C1B-000042", util.InvoiceNumber("3f2a9c1b-77aa-4c1e-9d2e-000000000000", 42))
+	assert.Equal(t, "SN-P1-1234567", util.InvoiceNumber("p1", 1234567))
+}
+
+func TestBuildInvoice(t *testing.T) {
+	householderID := "h1"
+	address := "12 MG Road"
+	request := &model.ServiceRequest{ID: "r1", HouseholderID: &householderID, HouseholderName: "Asha", HouseholderAddress: &address,
+		ServiceName: "Pipe repair", ScheduledTime: time.Date(2026, 10, 1, 4, 30, 0, 0, time.UTC)}
+	provider := model.ServiceProviderDetails{ServiceProviderID: "p1", Name: "Ravi", Price: model.NewMoney(11800, "INR")}
+	now := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+
+	// Requests approved before quotes existed are invoiced at the provider's price
+	invoice, err := util.BuildInvoice(request, provider, nil, now)
+	assert.NoError(t, err)
+	assert.Len(t, invoice.LineItems, 1)
+	assert.Equal(t, model.LineLabour, invoice.LineItems[0].Kind)
+	assert.Equal(t, model.NewMoney(11800, "INR"), invoice.Total)
+	assert.Equal(t, int64(1800), invoice.Tax.Amount)
+	assert.Equal(t, "12 MG Road", invoice.HouseholderAddress)
+
+	quote := &model.Quote{ID: "q1", Version: 2, Total: model.NewMoney(15000, "INR"), LineItems: []model.QuoteLineItem{
+		{Kind: model.LineLabour, Amount: model.NewMoney(10000, "INR")},
+		{Kind: model.LineParts, Description: "Washer <kit>", Amount: model.NewMoney(5000, "INR")},
+	}}
+	invoice, err = util.BuildInvoice(request, provider, quote, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "q1", invoice.QuoteID)
+	assert.Len(t, invoice.LineItems, 2)
+	assert.Equal(t, model.NewMoney(15000, "INR"), invoice.Total)
+
+	invoice.Number = "SN-P1-000001"
+	document, err := util.RenderInvoiceHTML(invoice, time.FixedZone("IST", 5*3600+1800))
+	assert.NoError(t, err)
+	html := string(document)
+	assert.True(t, strings.Contains(html, "SN-P1-000001"))
+	assert.True(t, strings.Contains(html, "Washer &lt;kit&gt;"))
+	assert.True(t, strings.Contains(html, "01 Oct 2026 10:00 IST"))
+	assert.True(t, strings.Contains(html, "GST @ 18% (included)"))
+}
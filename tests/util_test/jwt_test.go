@@ -15,7 +15,7 @@ func TestGenerateJWT(t *testing.T) {
 	role := "Householder"
 
 	// Generate JWT
-	tokenString, err := util.GenerateJWT(userID, role)
+	tokenString, err := util.GenerateJWT(userID, role, "testSessionID")
 
 	// Assert no error occurred
 	assert.NoError(t, err, "expected no error when generating JWT")
@@ -33,11 +33,28 @@ func TestGenerateJWT(t *testing.T) {
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		assert.Equal(t, userID, claims["user_id"], "expected the user ID in the claims to match")
 		assert.Equal(t, role, claims["role"], "expected the role in the claims to match")
+		assert.Equal(t, "testSessionID", claims["sid"], "expected the session ID in the claims to match")
 	} else {
 		t.Error("failed to extract claims from token")
 	}
 }
 
+func TestGenerateRefreshToken(t *testing.T) {
+	first, err := util.GenerateRefreshToken()
+	assert.NoError(t, err)
+	second, err := util.GenerateRefreshToken()
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second, "expected every refresh token to be unique")
+}
+
+func TestHashToken(t *testing.T) {
+	assert.Equal(t, util.HashToken("token"), util.HashToken("token"))
+	assert.NotEqual(t, util.HashToken("token"), util.HashToken("other"))
+	assert.Len(t, util.HashToken("token"), 64)
+}
+
 func TestVerifyJWT_InvalidToken(t *testing.T) {
 	// Create an invalid token string (just random text)
 	invalidToken := "this.is.an.invalid.token"
@@ -71,7 +88,7 @@ func TestVerifyJWT_ValidToken(t *testing.T) {
 	// Create a valid token
 	userID := "validUserID"
 	role := "Admin"
-	tokenString, err := util.GenerateJWT(userID, role)
+	tokenString, err := util.GenerateJWT(userID, role, "testSessionID")
 
 	assert.NoError(t, err, "expected no error when generating a valid token")
 
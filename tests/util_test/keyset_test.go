@@ -0,0 +1,189 @@
+package util_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+)
+
+// writeKeySet writes the PEM files and the keyset file for configs into a temporary directory
+func writeKeySet(t *testing.T, configs []model.SigningKeyConfig, pems map[string]*pem.Block) string {
+	dir := t.TempDir()
+	for name, block := range pems {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(block), 0600))
+	}
+	data, err := json.Marshal(configs)
+	require.NoError(t, err)
+	path := filepath.Join(dir, "keyset.json")
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func ed25519PrivatePEM(t *testing.T) (*pem.Block, ed25519.PublicKey) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	return &pem.Block{Type: "PRIVATE KEY", Bytes: der}, public
+}
+
+func rsaKeyPEMs(t *testing.T) (private *pem.Block, public *pem.Block) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}
+}
+
+func TestKeySetSignsWithActiveKeyAndVerifiesRetiringKey(t *testing.T) {
+	edPEM, _ := ed25519PrivatePEM(t)
+	rsaPrivate, rsaPublic := rsaKeyPEMs(t)
+
+	// The RSA key signed tokens before the Ed25519 key became active
+	oldPath := writeKeySet(t, []model.SigningKeyConfig{
+		{ID: "2026-04", Algorithm: "RS256", Status: model.SigningKeyActive, PrivateKeyFile: "old.pem"},
+	}, map[string]*pem.Block{"old.pem": rsaPrivate})
+	require.NoError(t, util.ReloadKeySet(oldPath))
+	defer util.SetKeySet(nil)
+	oldToken, err := util.GenerateJWT("user1", "Householder", "session1")
+	require.NoError(t, err)
+
+	path := writeKeySet(t, []model.SigningKeyConfig{
+		{ID: "2026-10", Algorithm: "EdDSA", Status: model.SigningKeyActive, PrivateKeyFile: "active.pem"},
+		{ID: "2026-04", Algorithm: "RS256", Status: model.SigningKeyRetiring, PublicKeyFile: "retiring.pem"},
+	}, map[string]*pem.Block{"active.pem": edPEM, "retiring.pem": rsaPublic})
+	require.NoError(t, util.ReloadKeySet(path))
+
+	tokenString, err := util.GenerateJWT("user1", "Householder", "session2")
+	require.NoError(t, err)
+	token, err := util.VerifyJWT(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", token.Method.Alg())
+	assert.Equal(t, "2026-10", token.Header["kid"])
+	assert.Equal(t, "session2", token.Claims.(jwt.MapClaims)["sid"])
+
+	// Tokens of the retiring key stay valid until they expire
+	token, err = util.VerifyJWT(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-04", token.Header["kid"])
+}
+
+func TestKeySetStillAcceptsHS256Tokens(t *testing.T) {
+	hsToken, err := util.GenerateJWT("user1", "Admin", "session1")
+	require.NoError(t, err)
+
+	edPEM, _ := ed25519PrivatePEM(t)
+	path := writeKeySet(t, []model.SigningKeyConfig{
+		{ID: "k1", Algorithm: "EdDSA", Status: model.SigningKeyActive, PrivateKeyFile: "k1.pem"},
+	}, map[string]*pem.Block{"k1.pem": edPEM})
+	require.NoError(t, util.ReloadKeySet(path))
+	defer util.SetKeySet(nil)
+
+	if os.Getenv("SECRET") == "" {
+		// Without a secret HS256 tokens could be forged, so they are refused once a keyset is loaded
+		_, err = util.VerifyJWT(hsToken)
+		assert.Error(t, err)
+		return
+	}
+	_, err = util.VerifyJWT(hsToken)
+	assert.NoError(t, err)
+}
+
+func TestKeySetRejectsUnknownKid(t *testing.T) {
+	signing, _ := ed25519PrivatePEM(t)
+	other, _ := ed25519PrivatePEM(t)
+	path := writeKeySet(t, []model.SigningKeyConfig{
+		{ID: "k1", Algorithm: "EdDSA", Status: model.SigningKeyActive, PrivateKeyFile: "k1.pem"},
+	}, map[string]*pem.Block{"k1.pem": signing})
+	require.NoError(t, util.ReloadKeySet(path))
+	defer util.SetKeySet(nil)
+
+	// A token signed with a key that is not in the keyset
+	otherPath := writeKeySet(t, []model.SigningKeyConfig{
+		{ID: "k2", Algorithm: "EdDSA", Status: model.SigningKeyActive, PrivateKeyFile: "k2.pem"},
+	}, map[string]*pem.Block{"k2.pem": other})
+	otherKeys, err := util.LoadKeySet(otherPath)
+	require.NoError(t, err)
+	util.SetKeySet(otherKeys)
+	foreign, err := util.GenerateJWT("user1", "Admin", "session1")
+	require.NoError(t, err)
+
+	require.NoError(t, util.ReloadKeySet(path))
+	_, err = util.VerifyJWT(foreign)
+	assert.Error(t, err)
+}
+
+func TestLoadKeySetValidation(t *testing.T) {
+	edPEM, _ := ed25519PrivatePEM(t)
+	rsaPrivate, rsaPublic := rsaKeyPEMs(t)
+	pems := map[string]*pem.Block{"ed.pem": edPEM, "rsa.pem": rsaPrivate, "rsa.pub": rsaPublic}
+
+	tests := []struct {
+		name    string
+		configs []model.SigningKeyConfig
+	}{
+		{"no active key", []model.SigningKeyConfig{
+			{ID: "k1", Algorithm: "EdDSA", Status: model.SigningKeyRetiring, PrivateKeyFile: "ed.pem"},
+		}},
+		{"two active keys", []model.SigningKeyConfig{
+			{ID: "k1", Algorithm: "EdDSA", Status: model.SigningKeyActive, PrivateKeyFile: "ed.pem"},
+			{ID: "k2", Algorithm: "RS256", Status: model.SigningKeyActive, PrivateKeyFile: "rsa.pem"},
+		}},
+		{"duplicate kid", []model.SigningKeyConfig{
+			{ID: "k1", Algorithm: "EdDSA", Status: model.SigningKeyActive, PrivateKeyFile: "ed.pem"},
+			{ID: "k1", Algorithm: "RS256", Status: model.SigningKeyRetiring, PublicKeyFile: "rsa.pub"},
+		}},
+		{"algorithm does not match key", []model.SigningKeyConfig{
+			{ID: "k1", Algorithm: "RS256", Status: model.SigningKeyActive, PrivateKeyFile: "ed.pem"},
+		}},
+		{"unsupported algorithm", []model.SigningKeyConfig{
+			{ID: "k1", Algorithm: "HS256", Status: model.SigningKeyActive, PrivateKeyFile: "ed.pem"},
+		}},
+		{"active key without private key", []model.SigningKeyConfig{
+			{ID: "k1", Algorithm: "RS256", Status: model.SigningKeyActive, PublicKeyFile: "rsa.pub"},
+		}},
+		{"missing kid", []model.SigningKeyConfig{
+			{Algorithm: "EdDSA", Status: model.SigningKeyActive, PrivateKeyFile: "ed.pem"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := util.LoadKeySet(writeKeySet(t, tt.configs, pems))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCurrentJWKS(t *testing.T) {
+	assert.Empty(t, util.CurrentJWKS().Keys, "the HS256 secret is never published")
+
+	edPEM, edPublic := ed25519PrivatePEM(t)
+	_, rsaPublic := rsaKeyPEMs(t)
+	path := writeKeySet(t, []model.SigningKeyConfig{
+		{ID: "ed", Algorithm: "EdDSA", Status: model.SigningKeyActive, PrivateKeyFile: "ed.pem"},
+		{ID: "rsa", Algorithm: "RS256", Status: model.SigningKeyRetiring, PublicKeyFile: "rsa.pub"},
+	}, map[string]*pem.Block{"ed.pem": edPEM, "rsa.pub": rsaPublic})
+	require.NoError(t, util.ReloadKeySet(path))
+	defer util.SetKeySet(nil)
+
+	keys := util.CurrentJWKS().Keys
+	require.Len(t, keys, 2)
+	assert.Equal(t, model.JWK{KeyType: "OKP", KeyID: "ed", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519",
+		X: jwt.EncodeSegment(edPublic)}, keys[0])
+	assert.Equal(t, "RSA", keys[1].KeyType)
+	assert.Equal(t, "RS256", keys[1].Algorithm)
+	assert.Equal(t, "AQAB", keys[1].Exponent)
+	assert.NotEmpty(t, keys[1].Modulus)
+}
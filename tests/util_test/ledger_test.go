@@ -0,0 +1,92 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func TestCommission(t *testing.T) {
+	assert.Equal(t, model.NewMoney(1000, "INR"), util.Commission(model.NewMoney(10000, "INR"), 1000))
+	// 10% of 49995 is 4999.5, rounded half up
+	assert.Equal(t, int64(5000), util.Commission(model.NewMoney(49995, "INR"), 1000).Amount)
+	assert.True(t, util.Commission(model.NewMoney(10000, "INR"), 0).IsZero())
+}
+
+func TestLedgerJournalsBalance(t *testing.T) {
+	job := model.CompletedJob{RequestID: "r1", ProviderID: "p1", ServiceID: "s1", ServiceName: "Plumbing",
+		Price: model.NewMoney(49995, "INR"), CompletedAt: time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)}
+	earning := util.EarningJournal(job, 1000)
+	assert.True(t, earning.Balanced())
+	assert.Equal(t, model.JournalEarning, earning.Kind)
+	assert.Equal(t, "r1", earning.Reference)
+
+	payment := &model.Payment{ID: "pay1", ProviderID: "p1", Amount: job.Price}
+	request := &model.ServiceRequest{ID: "r1", ServiceID: "s1", ServiceName: "Plumbing"}
+	refund := util.RefundJournal(payment, request, model.NewMoney(10001, "INR"), "re_1", 1000, time.Now())
+	assert.True(t, refund.Balanced())
+
+	payout := util.PayoutJournal("b1", "p1", model.NewMoney(35000, "INR"), time.Now())
+	assert.True(t, payout.Balanced())
+
+	unbalanced := &model.LedgerJournal{Currency: "INR", Entries: []model.LedgerEntry{
+		{Account: model.AccountProviderPayable, Amount: model.NewMoney(100, "INR")},
+		{Account: model.AccountPlatformBank, Amount: model.NewMoney(-100, "USD")},
+	}}
+	assert.False(t, unbalanced.Balanced())
+}
+
+func TestPeriodStart(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	// 2026-10-07 20:00 UTC is Thursday 2026-10-08 01:30 in IST
+	at := time.Date(2026, 10, 7, 20, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 8, 0, 0, 0, 0, ist), util.PeriodStart(at, "day", ist))
+	assert.Equal(t, time.Date(2026, 10, 5, 0, 0, 0, 0, ist), util.PeriodStart(at, "week", ist))
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, ist), util.PeriodStart(at, "month", ist))
+}
+
+func TestSummarizeEarnings(t *testing.T) {
+	october := time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC)
+	november := time.Date(2026, 11, 3, 10, 0, 0, 0, time.UTC)
+	journals := []model.LedgerJournal{
+		*util.EarningJournal(model.CompletedJob{RequestID: "r1", ProviderID: "p1", ServiceID: "s1", ServiceName: "Plumbing",
+			Price: model.NewMoney(10000, "INR"), CompletedAt: october}, 1000),
+		*util.EarningJournal(model.CompletedJob{RequestID: "r2", ProviderID: "p1", ServiceID: "s2", ServiceName: "Cleaning",
+			Price: model.NewMoney(20000, "INR"), CompletedAt: november}, 1000),
+		*util.RefundJournal(&model.Payment{ProviderID: "p1"}, &model.ServiceRequest{ID: "r1", ServiceID: "s1", ServiceName: "Plumbing"},
+			model.NewMoney(5000, "INR"), "re_1", 1000, november),
+		*util.PayoutJournal("b1", "p1", model.NewMoney(9000, "INR"), november),
+	}
+
+	totals, byService, byPeriod := util.SummarizeEarnings("p1", journals, "month", time.UTC)
+	assert.Len(t, totals, 1)
+	assert.Equal(t, int64(30000), totals[0].Gross.Amount)
+	assert.Equal(t, int64(2500), totals[0].Commission.Amount)
+	assert.Equal(t, int64(5000), totals[0].Refunds.Amount)
+	assert.Equal(t, int64(22500), totals[0].Net.Amount)
+	assert.Equal(t, int64(9000), totals[0].PaidOut.Amount)
+
+	assert.Len(t, byService, 2)
+	assert.Equal(t, "Cleaning", byService[0].ServiceName)
+	assert.Equal(t, int64(18000), byService[0].Net.Amount)
+	assert.Equal(t, "Plumbing", byService[1].ServiceName)
+	assert.Equal(t, int64(4500), byService[1].Net.Amount)
+	assert.Equal(t, int64(5000), byService[1].Refunds.Amount)
+
+	assert.Len(t, byPeriod, 2)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), byPeriod[0].PeriodStart)
+	assert.Equal(t, int64(9000), byPeriod[0].Net.Amount)
+	assert.Equal(t, int64(13500), byPeriod[1].Net.Amount)
+	assert.Equal(t, int64(9000), byPeriod[1].PaidOut.Amount)
+}
+
+func TestPayoutBatchCSV(t *testing.T) {
+	batch := &model.PayoutBatch{ID: "b1", Payouts: []model.ProviderPayout{
+		{ProviderID: "p1", Amount: model.NewMoney(123450, "INR"), JournalID: "j1"},
+	}}
+	document, err := util.PayoutBatchCSV(batch)
+	assert.NoError(t, err)
+	assert.Equal(t, "batch_id,provider_id,currency,amount,amount_minor,journal_id\nb1,p1,INR,1234.50,123450,j1\n", string(document))
+}
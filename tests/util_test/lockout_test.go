@@ -0,0 +1,48 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"os"
+	"serviceNest/config"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	base, max := 30*time.Second, time.Hour
+
+	assert.Zero(t, util.LockoutDuration(0, 5, base, max))
+	assert.Zero(t, util.LockoutDuration(4, 5, base, max))
+	assert.Equal(t, 30*time.Second, util.LockoutDuration(5, 5, base, max))
+	assert.Equal(t, time.Minute, util.LockoutDuration(6, 5, base, max))
+	assert.Equal(t, 4*time.Minute, util.LockoutDuration(8, 5, base, max))
+	// 30s doubled seven times is 64 minutes, past the cap
+	assert.Equal(t, time.Hour, util.LockoutDuration(12, 5, base, max))
+	assert.Equal(t, time.Hour, util.LockoutDuration(1000, 5, base, max))
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest("POST", "/login", nil)
+	req.RemoteAddr = "203.0.113.7:52100"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 10.0.0.1")
+
+	os.Unsetenv(config.TRUST_PROXY_HEADERS_ENV)
+	assert.Equal(t, "203.0.113.7", util.ClientIP(req), "X-Forwarded-For is ignored unless the proxy is trusted")
+
+	os.Setenv(config.TRUST_PROXY_HEADERS_ENV, "true")
+	defer os.Unsetenv(config.TRUST_PROXY_HEADERS_ENV)
+	assert.Equal(t, "10.0.0.1", util.ClientIP(req), "the entry the trusted proxy appended is used")
+
+	os.Setenv(config.TRUSTED_PROXY_HOPS_ENV, "2")
+	defer os.Unsetenv(config.TRUSTED_PROXY_HOPS_ENV)
+	assert.Equal(t, "198.51.100.1", util.ClientIP(req))
+
+	os.Setenv(config.TRUSTED_PROXY_HOPS_ENV, "3")
+	assert.Equal(t, "203.0.113.7", util.ClientIP(req), "a chain shorter than the trusted hops falls back to the peer")
+}
+
+func TestThrottleKey(t *testing.T) {
+	assert.Equal(t, "user@example.com", util.ThrottleKey("  User@Example.COM "))
+}
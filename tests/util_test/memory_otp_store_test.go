@@ -0,0 +1,71 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/config"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func newTestOtp(email string, purpose model.OtpPurpose, code string, now time.Time) *model.Otp {
+	return &model.Otp{Email: email, Purpose: purpose, CodeHash: util.HashOTP(email, purpose, code),
+		CreatedAt: now, ExpiresAt: now.Add(config.OTP_TTL)}
+}
+
+func TestMemoryOtpStoreConsumesOnce(t *testing.T) {
+	store := repository.NewMemoryOtpStore()
+	now := time.Now()
+	assert.NoError(t, store.SaveOTP(newTestOtp("a@example.com", model.OtpPasswordReset, "123456", now)))
+
+	hash := util.HashOTP("a@example.com", model.OtpPasswordReset, "123456")
+	valid, err := store.ConsumeOTP("a@example.com", model.OtpPasswordReset, hash, now)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, _ = store.ConsumeOTP("a@example.com", model.OtpPasswordReset, hash, now)
+	assert.False(t, valid, "an OTP works only once")
+}
+
+func TestMemoryOtpStoreScopesByPurpose(t *testing.T) {
+	store := repository.NewMemoryOtpStore()
+	now := time.Now()
+	assert.NoError(t, store.SaveOTP(newTestOtp("a@example.com", model.OtpEmailVerification, "123456", now)))
+
+	valid, _ := store.ConsumeOTP("a@example.com", model.OtpPasswordReset, util.HashOTP("a@example.com", model.OtpPasswordReset, "123456"), now)
+	assert.False(t, valid)
+	valid, _ = store.ConsumeOTP("a@example.com", model.OtpEmailVerification, util.HashOTP("a@example.com", model.OtpEmailVerification, "123456"), now)
+	assert.True(t, valid)
+}
+
+func TestMemoryOtpStoreInvalidatesAfterMaxAttempts(t *testing.T) {
+	store := repository.NewMemoryOtpStore()
+	now := time.Now()
+	assert.NoError(t, store.SaveOTP(newTestOtp("a@example.com", model.OtpPasswordReset, "123456", now)))
+
+	wrong := util.HashOTP("a@example.com", model.OtpPasswordReset, "000000")
+	for i := 0; i < config.MAX_OTP_ATTEMPTS; i++ {
+		valid, err := store.ConsumeOTP("a@example.com", model.OtpPasswordReset, wrong, now)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+	}
+	valid, _ := store.ConsumeOTP("a@example.com", model.OtpPasswordReset, util.HashOTP("a@example.com", model.OtpPasswordReset, "123456"), now)
+	assert.False(t, valid, "the right code no longer works once the attempts are used up")
+}
+
+func TestMemoryOtpStoreExpiryAndSweep(t *testing.T) {
+	store := repository.NewMemoryOtpStore()
+	now := time.Now()
+	assert.NoError(t, store.SaveOTP(newTestOtp("a@example.com", model.OtpPasswordReset, "123456", now)))
+	assert.NoError(t, store.SaveOTP(newTestOtp("b@example.com", model.OtpLogin, "654321", now.Add(time.Minute))))
+
+	later := now.Add(config.OTP_TTL)
+	valid, _ := store.ConsumeOTP("a@example.com", model.OtpPasswordReset, util.HashOTP("a@example.com", model.OtpPasswordReset, "123456"), later)
+	assert.False(t, valid, "expired OTPs are refused")
+
+	deleted, err := store.DeleteExpiredOTPs(later.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
@@ -0,0 +1,34 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/config"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+)
+
+func TestGenerateOTP(t *testing.T) {
+	otp, err := util.GenerateOTP()
+	assert.NoError(t, err)
+	assert.Len(t, otp, config.OTP_LENGTH)
+	for _, digit := range otp {
+		assert.True(t, digit >= '0' && digit <= '9', "expected only digits in %q", otp)
+	}
+}
+
+func TestHashOTPIsScopedToEmailAndPurpose(t *testing.T) {
+	hash := util.HashOTP("a@example.com", model.OtpPasswordReset, "123456")
+	assert.Equal(t, hash, util.HashOTP("a@example.com", model.OtpPasswordReset, "123456"))
+	assert.NotEqual(t, hash, util.HashOTP("a@example.com", model.OtpLogin, "123456"))
+	assert.NotEqual(t, hash, util.HashOTP("b@example.com", model.OtpPasswordReset, "123456"))
+	assert.NotContains(t, hash, "123456")
+}
+
+func TestHashOTPNormalizesEmail(t *testing.T) {
+	assert.Equal(t, util.HashOTP("a@example.com", model.OtpLogin, "123456"), util.HashOTP(" A@Example.com", model.OtpLogin, "123456"))
+}
+
+func TestHashOTPIsNotAPlainHash(t *testing.T) {
+	assert.NotEqual(t, util.HashToken("password_reset:a@example.com:123456"), util.HashOTP("a@example.com", model.OtpPasswordReset, "123456"))
+}
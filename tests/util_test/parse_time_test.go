@@ -176,3 +176,16 @@ func TestParseTimeAll(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNullableTime(t *testing.T) {
+	parsed, err := util.ParseNullableTime(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, parsed)
+
+	parsed, err = util.ParseNullableTime([]uint8("2023-09-08 14:30:00"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 9, 8, 14, 30, 0, 0, time.UTC), *parsed)
+
+	_, err = util.ParseNullableTime([]uint8("not a time"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func TestPrepareQuote(t *testing.T) {
+	now := time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC)
+	quote := &model.Quote{
+		ValidUntil: now.Add(time.Hour),
+		LineItems: []model.QuoteLineItem{
+			{Kind: model.LineLabour, Amount: model.NewMoney(50000, "INR")},
+			{Kind: model.LineParts, Amount: model.NewMoney(12050, "INR")},
+			{Kind: model.LineVisitFee, Amount: model.NewMoney(9900, "INR")},
+		},
+	}
+	assert.NoError(t, util.PrepareQuote(quote, now))
+	assert.Equal(t, model.NewMoney(71950, "INR"), quote.Total)
+
+	labour := func(amount int64, currency string) model.QuoteLineItem {
+		return model.QuoteLineItem{Kind: model.LineLabour, Amount: model.NewMoney(amount, currency)}
+	}
+
+	invalid := []*model.Quote{
+		{ValidUntil: now.Add(time.Hour)},
+		{ValidUntil: now.Add(time.Hour), LineItems: []model.QuoteLineItem{{Kind: "travel", Amount: model.NewMoney(100, "INR")}}},
+		{ValidUntil: now.Add(time.Hour), LineItems: []model.QuoteLineItem{labour(0, "INR")}},
+		{ValidUntil: now.Add(time.Hour), LineItems: []model.QuoteLineItem{labour(100, "XYZ")}},
+		{ValidUntil: now.Add(time.Hour), LineItems: []model.QuoteLineItem{labour(100, "INR"), labour(100, "USD")}},
+		{ValidUntil: now, LineItems: []model.QuoteLineItem{labour(100, "INR")}},
+	}
+	for _, q := range invalid {
+		assert.Error(t, util.PrepareQuote(q, now))
+	}
+}
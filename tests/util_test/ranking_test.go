@@ -0,0 +1,97 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func TestBayesianRating(t *testing.T) {
+	newcomer := util.BayesianRating(5, 1)
+	veteran := util.BayesianRating(4.8, 200)
+
+	assert.Greater(t, veteran, newcomer)
+	assert.InDelta(t, 3.5, util.BayesianRating(0, 0), 1e-9)
+}
+
+func TestRankServicesByRelevance(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	services := []model.Service{
+		{ID: "1", ProviderID: "newcomer", AvgRating: 5, RatingCount: 1},
+		{ID: "1", ProviderID: "veteran", AvgRating: 4.8, RatingCount: 200},
+		{ID: "1", ProviderID: "average", AvgRating: 3.5, RatingCount: 40},
+	}
+	signals := model.RankingSignals{
+		LastReviewDates: map[string]time.Time{
+			model.ServiceKey("newcomer", "1"): now,
+			model.ServiceKey("veteran", "1"):  now.Add(-24 * time.Hour),
+		},
+		ProviderJobs: map[string]model.ProviderJobStats{
+			"veteran": {ApprovedJobs: 210, CompletedJobs: 205},
+		},
+	}
+
+	ranked := util.RankServices(services, util.SortRelevance, signals, now)
+
+	assert.Equal(t, "veteran", ranked[0].ProviderID)
+	assert.Equal(t, "newcomer", ranked[1].ProviderID)
+	assert.Equal(t, "average", ranked[2].ProviderID)
+}
+
+func TestRelevanceScorePenalisesCancellations(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	service := model.Service{ID: "1", ProviderID: "p1", AvgRating: 4.5, RatingCount: 20}
+	reliable := model.RankingSignals{ProviderJobs: map[string]model.ProviderJobStats{"p1": {ApprovedJobs: 20, CompletedJobs: 18}}}
+	cancelling := model.RankingSignals{ProviderJobs: map[string]model.ProviderJobStats{"p1": {ApprovedJobs: 20, CompletedJobs: 18, PenaltyPoints: 6}}}
+
+	assert.Greater(t, util.RelevanceScore(service, reliable, now), util.RelevanceScore(service, cancelling, now))
+}
+
+func TestRankServicesByRelevanceKeepsVeteranWithOlderReviews(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	services := []model.Service{
+		{ID: "1", ProviderID: "newcomer", AvgRating: 5, RatingCount: 1},
+		{ID: "1", ProviderID: "veteran", AvgRating: 4.8, RatingCount: 200},
+	}
+	signals := model.RankingSignals{
+		LastReviewDates: map[string]time.Time{
+			model.ServiceKey("newcomer", "1"): now,
+			model.ServiceKey("veteran", "1"):  now.Add(-180 * 24 * time.Hour),
+		},
+	}
+
+	ranked := util.RankServices(services, util.SortRelevance, signals, now)
+
+	assert.Equal(t, "veteran", ranked[0].ProviderID)
+}
+
+func TestRankServicesByRatingUsesBayesianRating(t *testing.T) {
+	services := []model.Service{
+		{ID: "a", AvgRating: 5, RatingCount: 1},
+		{ID: "b", AvgRating: 4.6, RatingCount: 80},
+	}
+
+	ranked := util.RankServices(services, util.SortRating, model.RankingSignals{}, time.Now())
+
+	assert.Equal(t, "b", ranked[0].ID)
+}
+
+func TestRankServicesByPrice(t *testing.T) {
+	services := []model.Service{
+		{ID: "a", Price: model.NewMoney(30000, "INR")},
+		{ID: "b", Price: model.NewMoney(10000, "INR")},
+		{ID: "c", Price: model.NewMoney(20000, "INR")},
+	}
+
+	ranked := util.RankServices(services, util.SortPrice, model.RankingSignals{}, time.Now())
+
+	assert.Equal(t, []string{"b", "c", "a"}, []string{ranked[0].ID, ranked[1].ID, ranked[2].ID})
+}
+
+func TestIsValidServiceSort(t *testing.T) {
+	assert.True(t, util.IsValidServiceSort(""))
+	assert.True(t, util.IsValidServiceSort("popularity"))
+	assert.False(t, util.IsValidServiceSort("cheapest"))
+}
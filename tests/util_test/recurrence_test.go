@@ -0,0 +1,67 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func TestSeriesOccurrenceWeeklyKeepsWallClockAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	// Saturday before the switch to summer time on 31 March 2024
+	first := time.Date(2024, time.March, 23, 10, 0, 0, 0, berlin)
+	rule := model.RecurrenceRule{Frequency: model.FrequencyWeekly, Count: 3}
+
+	second, ok := util.SeriesOccurrence(rule, first, berlin, 1)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.March, 30, 9, 0, 0, 0, time.UTC), second)
+
+	third, ok := util.SeriesOccurrence(rule, first, berlin, 2)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.April, 6, 8, 0, 0, 0, time.UTC), third)
+
+	_, ok = util.SeriesOccurrence(rule, first, berlin, 3)
+	assert.False(t, ok)
+}
+
+func TestSeriesOccurrenceMonthlyClampsToMonthEnd(t *testing.T) {
+	first := time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+	until := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+	rule := model.RecurrenceRule{Frequency: model.FrequencyMonthly, Until: &until}
+
+	february, ok := util.SeriesOccurrence(rule, first, time.UTC, 1)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC), february)
+
+	march, ok := util.SeriesOccurrence(rule, first, time.UTC, 2)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC), march)
+
+	_, ok = util.SeriesOccurrence(rule, first, time.UTC, 3)
+	assert.False(t, ok)
+}
+
+func TestSeriesOccurrenceIndex(t *testing.T) {
+	first := time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC)
+	rule := model.RecurrenceRule{Frequency: model.FrequencyBiweekly, Count: 4}
+
+	n, ok := util.SeriesOccurrenceIndex(rule, first, time.UTC, time.Date(2024, time.July, 1, 9, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, 2, n)
+
+	_, ok = util.SeriesOccurrenceIndex(rule, first, time.UTC, time.Date(2024, time.June, 10, 9, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestValidateRecurrenceRule(t *testing.T) {
+	first := time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC)
+	before := first.Add(-time.Hour)
+
+	assert.NoError(t, util.ValidateRecurrenceRule(model.RecurrenceRule{Frequency: model.FrequencyWeekly, Count: 4}, first))
+	assert.Error(t, util.ValidateRecurrenceRule(model.RecurrenceRule{Frequency: "daily", Count: 4}, first))
+	assert.Error(t, util.ValidateRecurrenceRule(model.RecurrenceRule{Frequency: model.FrequencyWeekly}, first))
+	assert.Error(t, util.ValidateRecurrenceRule(model.RecurrenceRule{Frequency: model.FrequencyMonthly, Until: &before}, first))
+}
@@ -0,0 +1,80 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/util"
+	"strings"
+	"testing"
+)
+
+func TestTokenMatch(t *testing.T) {
+	assert.Equal(t, 1.0, util.TokenMatch("plumber", "plumber"))
+	assert.Equal(t, 0.9, util.TokenMatch("plumb", "plumbing"))
+	assert.Equal(t, 0.6, util.TokenMatch("plumbr", "plumber"))
+	assert.Equal(t, 0.6, util.TokenMatch("plumbr", "plumbing"))
+	assert.Equal(t, 0.6, util.TokenMatch("electrcian", "electrician"))
+	assert.Equal(t, 0.0, util.TokenMatch("car", "cat"))
+	assert.Equal(t, 0.0, util.TokenMatch("plumbr", "painter"))
+}
+
+func TestScoreServicesTypoTolerance(t *testing.T) {
+	services := []model.Service{
+		{ID: "1", Name: "Pipe repair", Category: "Plumbing", ProviderName: "Ravi"},
+		{ID: "2", Name: "Wall painting", Category: "Painting", ProviderName: "Asha"},
+		{ID: "3", Name: "Plumber visit", Category: "Plumbing", ProviderName: "Kiran"},
+	}
+
+	results := util.ScoreServices("plumbr", services)
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, "3", results[0].Service.ID)
+	assert.Equal(t, "<em>Plumber</em> visit", results[0].Highlights["name"])
+	assert.Equal(t, "<em>Plumbing</em>", results[1].Highlights["category"])
+}
+
+func TestScoreServicesProviderNameAndSnippet(t *testing.T) {
+	description := "We cover every part of the house. " +
+		"Our team has fifteen years of experience with residential and commercial buildings across the city, " +
+		"and handles deep cleaning of kitchens and bathrooms on short notice."
+	services := []model.Service{{ID: "1", Name: "Home care", Category: "Cleaning", ProviderName: "Sparkle Cleaners", Description: description}}
+
+	results := util.ScoreServices("sparkle", services)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "<em>Sparkle</em> Cleaners", results[0].Highlights["provider_name"])
+
+	results = util.ScoreServices("kitchens", services)
+	assert.Len(t, results, 1)
+	snippet := results[0].Highlights["description"]
+	assert.Contains(t, snippet, "<em>kitchens</em>")
+	assert.True(t, len([]rune(snippet)) < len([]rune(description)))
+}
+
+func TestHighlightEscapesHTML(t *testing.T) {
+	services := []model.Service{{ID: "1", Name: "Plumber <script>alert(1)</script> & co"}}
+
+	results := util.ScoreServices("plumber", services)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "<em>Plumber</em> &lt;script&gt;alert(1)&lt;/script&gt; &amp; co", results[0].Highlights["name"])
+}
+
+func TestFullTextBooleanQuery(t *testing.T) {
+	assert.Equal(t, ">plmuber pl lm mu ub be er", util.FullTextBooleanQuery("plmuber"))
+	assert.Equal(t, ">ac ac >repair re ep pa ai ir", util.FullTextBooleanQuery("ac repair"))
+	assert.Equal(t, ">tap ta ap >tap", util.FullTextBooleanQuery("tap tap"))
+	assert.Equal(t, "", util.FullTextBooleanQuery("a i"))
+}
+
+func TestFullTextBooleanQueryKeepsTyposAnywhere(t *testing.T) {
+	for _, query := range []string{"lpumber", "plmuber", "plumbre", "pumber"} {
+		assert.Greater(t, util.TokenMatch(query, "plumber"), 0.0, query)
+
+		shared := false
+		for _, term := range strings.Fields(util.FullTextBooleanQuery(query)) {
+			if !strings.HasPrefix(term, ">") && strings.Contains("plumber", term) {
+				shared = true
+			}
+		}
+		assert.True(t, shared, query)
+	}
+}
@@ -0,0 +1,106 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func mondaySchedule() *model.ProviderSchedule {
+	return &model.ProviderSchedule{
+		ProviderID:  "provider1",
+		Available:   true,
+		SlotMinutes: 60,
+		WorkingHours: []model.WorkingHours{
+			{Weekday: time.Monday, Start: "09:00", End: "12:00"},
+			{Weekday: time.Monday, Start: "14:00", End: "16:30"},
+		},
+	}
+}
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, time.June, day, hour, minute, 0, 0, util.ScheduleLocation(mondaySchedule()))
+}
+
+func hourFrom(start time.Time) model.TimeRange {
+	return model.TimeRange{Start: start, End: start.Add(time.Hour)}
+}
+
+func TestGenerateSlots(t *testing.T) {
+	schedule := mondaySchedule()
+	schedule.TimeOff = []model.TimeOff{{Start: at(3, 14, 0), End: at(3, 15, 0)}}
+	booked := []model.TimeRange{{Start: at(3, 10, 0), End: at(3, 11, 0)}}
+
+	// 3 June 2024 is a Monday
+	slots := util.GenerateSlots(schedule, at(3, 0, 0), booked, at(1, 0, 0))
+
+	assert.Len(t, slots, 5)
+	var free []int
+	for _, slot := range slots {
+		if slot.Available {
+			free = append(free, slot.Start.Hour())
+		}
+	}
+	assert.Equal(t, []int{9, 11, 15}, free)
+
+	assert.Empty(t, util.GenerateSlots(schedule, at(4, 0, 0), nil, at(1, 0, 0)))
+}
+
+func TestGenerateSlotsMarksPastAndUnavailable(t *testing.T) {
+	schedule := mondaySchedule()
+	slots := util.GenerateSlots(schedule, at(3, 0, 0), nil, at(3, 10, 30))
+	assert.False(t, slots[0].Available)
+	assert.True(t, slots[2].Available)
+
+	schedule.Available = false
+	for _, slot := range util.GenerateSlots(schedule, at(3, 0, 0), nil, at(1, 0, 0)) {
+		assert.False(t, slot.Available)
+	}
+}
+
+func TestFitsSchedule(t *testing.T) {
+	schedule := mondaySchedule()
+	booked := []model.TimeRange{{Start: at(3, 10, 0), End: at(3, 11, 0)}}
+
+	assert.True(t, util.FitsSchedule(schedule, hourFrom(at(3, 9, 0)), booked))
+	assert.False(t, util.FitsSchedule(schedule, hourFrom(at(3, 9, 30)), booked))
+	assert.False(t, util.FitsSchedule(schedule, hourFrom(at(3, 11, 30)), booked))
+	assert.False(t, util.FitsSchedule(schedule, hourFrom(at(3, 13, 0)), booked))
+	assert.True(t, util.FitsSchedule(schedule, hourFrom(at(3, 15, 30)), booked))
+
+	// Without published hours the whole day is open apart from bookings
+	schedule.WorkingHours = nil
+	assert.True(t, util.FitsSchedule(schedule, hourFrom(at(4, 20, 0)), booked))
+}
+
+func TestFitsScheduleUsesJobLength(t *testing.T) {
+	schedule := mondaySchedule()
+	booked := []model.TimeRange{{Start: at(3, 10, 0), End: at(3, 11, 0)}}
+
+	// A two hour job starting at 9:00 runs into the 10:00 booking even though its first slot is free
+	assert.False(t, util.FitsSchedule(schedule, model.TimeRange{Start: at(3, 9, 0), End: at(3, 11, 0)}, booked))
+}
+
+func TestWorkingHoursAreInScheduleTimezone(t *testing.T) {
+	schedule := mondaySchedule()
+	schedule.Timezone = "Europe/London"
+	london, _ := time.LoadLocation("Europe/London")
+
+	slots := util.GenerateSlots(schedule, time.Date(2024, time.June, 3, 0, 0, 0, 0, london), nil, at(1, 0, 0))
+
+	assert.Equal(t, time.Date(2024, time.June, 3, 9, 0, 0, 0, london).UTC(), slots[0].Start.UTC())
+	assert.True(t, util.FitsSchedule(schedule, hourFrom(time.Date(2024, time.June, 3, 15, 0, 0, 0, london)), nil))
+	assert.False(t, util.FitsSchedule(schedule, hourFrom(time.Date(2024, time.June, 3, 11, 0, 0, 0, time.UTC)), nil))
+}
+
+func TestValidateWorkingHours(t *testing.T) {
+	assert.NoError(t, util.ValidateWorkingHours(mondaySchedule().WorkingHours))
+	assert.Error(t, util.ValidateWorkingHours([]model.WorkingHours{{Weekday: time.Monday, Start: "12:00", End: "09:00"}}))
+	assert.Error(t, util.ValidateWorkingHours([]model.WorkingHours{{Weekday: 7, Start: "09:00", End: "10:00"}}))
+	assert.Error(t, util.ValidateWorkingHours([]model.WorkingHours{
+		{Weekday: time.Monday, Start: "09:00", End: "12:00"},
+		{Weekday: time.Monday, Start: "11:00", End: "13:00"},
+	}))
+}
@@ -0,0 +1,35 @@
+package util_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/util"
+	"testing"
+	"time"
+)
+
+func TestParseUserTime(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	assert.NoError(t, err)
+	want := time.Date(2024, 6, 1, 4, 30, 0, 0, time.UTC)
+
+	parsed, err := util.ParseUserTime("2024-06-01T10:00:00+05:30", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, want, parsed)
+	assert.Equal(t, time.UTC, parsed.Location())
+
+	// Zone-less legacy input is read in the user's timezone
+	parsed, err = util.ParseUserTime("2024-06-01 10:00", kolkata)
+	assert.NoError(t, err)
+	assert.Equal(t, want, parsed)
+
+	_, err = util.ParseUserTime("01/06/2024 10:00", kolkata)
+	assert.Error(t, err)
+}
+
+func TestValidTimezone(t *testing.T) {
+	assert.True(t, util.ValidTimezone("Europe/Berlin"))
+	assert.False(t, util.ValidTimezone(""))
+	assert.False(t, util.ValidTimezone("Mars/Olympus"))
+	assert.Equal(t, "America/New_York", util.LoadUserLocation("America/New_York").String())
+	assert.Equal(t, util.DefaultLocation(), util.LoadUserLocation("Mars/Olympus"))
+}
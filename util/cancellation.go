@@ -0,0 +1,80 @@
+package util
+
+import (
+	"errors"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"time"
+)
+
+// DefaultCancellationPolicy is the policy applied where neither the provider nor the category has one
+func DefaultCancellationPolicy() *model.CancellationPolicy {
+	return &model.CancellationPolicy{
+		ID:                    string(model.PolicyScopeDefault),
+		Scope:                 model.PolicyScopeDefault,
+		FreeWindowHours:       int(config.CANCELLATION_NOTICE / time.Hour),
+		LateFeeBasisPoints:    config.DEFAULT_LATE_CANCELLATION_FEE_BASIS_POINTS,
+		NoShowFeeBasisPoints:  config.DEFAULT_NO_SHOW_FEE_BASIS_POINTS,
+		ProviderLatePenalty:   config.DEFAULT_PROVIDER_LATE_CANCELLATION_PENALTY,
+		ProviderNoShowPenalty: config.DEFAULT_PROVIDER_NO_SHOW_PENALTY,
+	}
+}
+
+// ValidateCancellationPolicy checks the scope and that fees are between 0% and 100%
+func ValidateCancellationPolicy(policy *model.CancellationPolicy) error {
+	if policy.Scope != model.PolicyScopeProvider && policy.Scope != model.PolicyScopeCategory {
+		return errors.New(errs.InvalidCancellationPolicy)
+	}
+	if policy.ScopeID == "" || policy.FreeWindowHours < 0 || policy.FreeWindowHours > config.MAX_CANCELLATION_FREE_WINDOW_HOURS {
+		return errors.New(errs.InvalidCancellationPolicy)
+	}
+	for _, fee := range []int{policy.LateFeeBasisPoints, policy.NoShowFeeBasisPoints} {
+		if fee < 0 || fee > 10000 {
+			return errors.New(errs.InvalidCancellationPolicy)
+		}
+	}
+	if policy.ProviderLatePenalty < 0 || policy.ProviderNoShowPenalty < 0 {
+		return errors.New(errs.InvalidCancellationPolicy)
+	}
+	return nil
+}
+
+// EvaluateCancellation works out what cancelling a booking of the given price, scheduled at scheduled,
+// costs at now under policy. Householders pay a share of the price; providers, byProvider, lose
+// reliability points and always lose at least config.PROVIDER_CANCELLATION_PENALTY.
+func EvaluateCancellation(policy *model.CancellationPolicy, price model.Money, scheduled, now time.Time, byProvider bool) *model.CancellationTerms {
+	freeUntil := scheduled.UTC().Add(-time.Duration(policy.FreeWindowHours) * time.Hour)
+	terms := &model.CancellationTerms{
+		PolicyID:  policy.ID,
+		Scope:     policy.Scope,
+		Tier:      model.CancellationFree,
+		Fee:       model.NewMoney(0, price.Currency),
+		FreeUntil: &freeUntil,
+	}
+
+	feeRate, penalty := 0, config.PROVIDER_CANCELLATION_PENALTY
+	switch {
+	case !now.Before(scheduled):
+		terms.Tier = model.CancellationNoShow
+		feeRate, penalty = policy.NoShowFeeBasisPoints, policy.ProviderNoShowPenalty
+	case now.After(freeUntil):
+		terms.Tier = model.CancellationLate
+		feeRate, penalty = policy.LateFeeBasisPoints, policy.ProviderLatePenalty
+	}
+
+	if byProvider {
+		if penalty < config.PROVIDER_CANCELLATION_PENALTY {
+			penalty = config.PROVIDER_CANCELLATION_PENALTY
+		}
+		terms.PenaltyPoints = penalty
+		return terms
+	}
+	terms.Fee = ApplyBasisPoints(price, feeRate)
+	return terms
+}
+
+// ApplyBasisPoints returns rate basis points of amount, rounded half up to the nearest minor unit
+func ApplyBasisPoints(amount model.Money, rate int) model.Money {
+	return model.NewMoney((amount.Amount*int64(rate)*2+10000)/20000, amount.Currency)
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"serviceNest/config"
+	"serviceNest/model"
+	"time"
+)
+
+// JobDuration converts an estimated duration in minutes, falling back to the default job length
+func JobDuration(minutes int) time.Duration {
+	if minutes <= 0 {
+		return time.Duration(config.DEFAULT_JOB_MINUTES) * time.Minute
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// FindConflicts returns every booking that overlaps job or leaves less than buffer between them
+func FindConflicts(job model.TimeRange, bookings []model.Booking, buffer time.Duration) []model.ScheduleConflict {
+	var conflicts []model.ScheduleConflict
+	for _, booking := range bookings {
+		booked := model.TimeRange{Start: booking.Start, End: booking.End}
+		padded := model.TimeRange{Start: booking.Start.Add(-buffer), End: booking.End.Add(buffer)}
+		if !job.Overlaps(padded) {
+			continue
+		}
+		conflicts = append(conflicts, model.ScheduleConflict{
+			RequestID: booking.RequestID,
+			Start:     booking.Start,
+			End:       booking.End,
+			Overlap:   job.Overlaps(booked),
+		})
+	}
+	return conflicts
+}
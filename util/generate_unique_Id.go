@@ -6,6 +6,8 @@ import (
 	"math/rand"
 	"net/smtp"
 	"os"
+	"serviceNest/config"
+	"serviceNest/model"
 )
 
 func GenerateUniqueID() string {
@@ -41,8 +43,15 @@ func sendEmail(to, subject, body string) error {
 	return nil
 }
 
-func SendOTPEmail(to, otp string) error {
-	subject := "Your OTP Code For Service Nest"
-	body := fmt.Sprintf("Your OTP for verification is: %s. It is valid for 5 minutes.", otp)
+// otpEmailSubjects names what each kind of OTP is for in the email it is sent in
+var otpEmailSubjects = map[model.OtpPurpose]string{
+	model.OtpPasswordReset:     "Your Password Reset OTP For Service Nest",
+	model.OtpEmailVerification: "Your Email Verification OTP For Service Nest",
+	model.OtpLogin:             "Your Login OTP For Service Nest",
+}
+
+func SendOTPEmail(to, otp string, purpose model.OtpPurpose) error {
+	subject := otpEmailSubjects[purpose]
+	body := fmt.Sprintf("Your OTP for verification is: %s. It is valid for %d minutes.", otp, int(config.OTP_TTL.Minutes()))
 	return sendEmail(to, subject, body)
 }
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"database/sql"
+	"math"
+)
+
+const earthRadiusKm = 6371.0
+
+// HaversineDistance returns the great-circle distance in kilometres between two coordinates
+func HaversineDistance(lat1, lng1, lat2, lng2 float64) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// ValidCoordinates reports whether latitude and longitude are within their valid ranges
+func ValidCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// NullableFloat converts a nullable DOUBLE column into a *float64, returning nil for NULL
+func NullableFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
@@ -0,0 +1,134 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"strings"
+	"time"
+)
+
+// BuildInvoice prepares the invoice of a completed request from the approved provider's details and the
+// approved quote. Requests approved before quotes were versioned have no quote and are invoiced as a
+// single labour line at the provider's price. Prices include tax, which the invoice breaks out.
+func BuildInvoice(request *model.ServiceRequest, provider model.ServiceProviderDetails, quote *model.Quote, now time.Time) (*model.Invoice, error) {
+	invoice := &model.Invoice{
+		ID:              GenerateUUID(),
+		RequestID:       request.ID,
+		ProviderID:      provider.ServiceProviderID,
+		HouseholderName: request.HouseholderName,
+		ProviderName:    provider.Name,
+		ProviderAddress: provider.Address,
+		ProviderContact: provider.Contact,
+		ServiceName:     request.ServiceName,
+		ScheduledTime:   request.ScheduledTime.UTC(),
+		StartedAt:       request.ActualStartTime,
+		CompletedAt:     request.ActualEndTime,
+		TaxLabel:        config.INVOICE_TAX_LABEL,
+		TaxRate:         config.INVOICE_TAX_RATE_BASIS_POINTS,
+		IssuedAt:        now.UTC(),
+	}
+	if request.HouseholderID != nil {
+		invoice.HouseholderID = *request.HouseholderID
+	}
+	if request.HouseholderAddress != nil {
+		invoice.HouseholderAddress = *request.HouseholderAddress
+	}
+
+	if quote != nil {
+		invoice.QuoteID = quote.ID
+		invoice.QuoteVersion = quote.Version
+		invoice.LineItems = quote.LineItems
+		invoice.Total = quote.Total
+	} else {
+		invoice.LineItems = []model.QuoteLineItem{{Kind: model.LineLabour, Description: request.ServiceName, Amount: provider.Price}}
+		invoice.Total = provider.Price
+	}
+	if invoice.Total.Amount <= 0 {
+		return nil, errors.New(errs.InvalidPrice)
+	}
+	invoice.Taxable, invoice.Tax = SplitInclusiveTax(invoice.Total, invoice.TaxRate)
+	return invoice, nil
+}
+
+// SplitInclusiveTax splits a tax-inclusive total into its taxable value and the tax charged at rate basis
+// points, rounding the taxable value half up to the nearest minor unit
+func SplitInclusiveTax(total model.Money, rate int) (taxable, tax model.Money) {
+	divisor := int64(10000 + rate)
+	taxableAmount := (total.Amount*10000*2 + divisor) / (2 * divisor)
+	return model.NewMoney(taxableAmount, total.Currency), model.NewMoney(total.Amount-taxableAmount, total.Currency)
+}
+
+// InvoiceNumber formats the sequence-th invoice of a provider, e.g. "SN-3F2A9C1B-000042"
+func InvoiceNumber(providerID string, sequence int64) string {
+	prefix := strings.ToUpper(strings.ReplaceAll(providerID, "-", ""))
+	if len(prefix) > 8 {
+		prefix = prefix[:8]
+	}
+	return fmt.Sprintf("SN-%s-%06d", prefix, sequence)
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"percent": func(basisPoints int) string {
+		return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%d.%02d", basisPoints/100, basisPoints%100), "0"), ".")
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Invoice.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-top: 1em; }
+th, td { border-bottom: 1px solid #ddd; padding: 6px; text-align: left; }
+td.amount, th.amount { text-align: right; }
+.parties { display: flex; justify-content: space-between; margin-top: 1em; }
+</style>
+</head>
+<body>
+<h1>Tax invoice / Receipt</h1>
+<p>Invoice number <strong>{{.Invoice.Number}}</strong><br>Issued {{.IssuedAt}}</p>
+<div class="parties">
+<div><h3>Service provider</h3>{{.Invoice.ProviderName}}<br>{{.Invoice.ProviderAddress}}<br>{{.Invoice.ProviderContact}}</div>
+<div><h3>Billed to</h3>{{.Invoice.HouseholderName}}<br>{{.Invoice.HouseholderAddress}}</div>
+</div>
+<p>{{.Invoice.ServiceName}}, scheduled {{.ScheduledTime}}{{if .CompletedAt}}, completed {{.CompletedAt}}{{end}}<br>Request {{.Invoice.RequestID}}{{if .Invoice.QuoteID}}, quote version {{.Invoice.QuoteVersion}}{{end}}</p>
+<table>
+<tr><th>Item</th><th>Description</th><th class="amount">Amount ({{.Invoice.Total.Currency}})</th></tr>
+{{range .Invoice.LineItems}}<tr><td>{{.Kind}}</td><td>{{.Description}}</td><td class="amount">{{.Amount.Decimal}}</td></tr>
+{{end}}<tr><td colspan="2">Taxable value</td><td class="amount">{{.Invoice.Taxable.Decimal}}</td></tr>
+<tr><td colspan="2">{{.Invoice.TaxLabel}} @ {{percent .Invoice.TaxRate}}% (included)</td><td class="amount">{{.Invoice.Tax.Decimal}}</td></tr>
+<tr><th colspan="2">Total</th><th class="amount">{{.Invoice.Total.Decimal}}</th></tr>
+</table>
+{{if .Invoice.PaymentStatus}}<p>Payment status: {{.Invoice.PaymentStatus}}</p>{{end}}
+</body>
+</html>
+`))
+
+// RenderInvoiceHTML renders the invoice as a printable HTML document with times shown in location
+func RenderInvoiceHTML(invoice *model.Invoice, location *time.Location) ([]byte, error) {
+	const layout = "02 Jan 2006 15:04 MST"
+	data := struct {
+		Invoice       *model.Invoice
+		IssuedAt      string
+		ScheduledTime string
+		CompletedAt   string
+	}{
+		Invoice:       invoice,
+		IssuedAt:      invoice.IssuedAt.In(location).Format(layout),
+		ScheduledTime: invoice.ScheduledTime.In(location).Format(layout),
+	}
+	if invoice.CompletedAt != nil {
+		data.CompletedAt = invoice.CompletedAt.In(location).Format(layout)
+	}
+
+	var buf bytes.Buffer
+	if err := invoiceTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
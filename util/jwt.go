@@ -1,33 +1,47 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"github.com/golang-jwt/jwt"
 	"os"
+	"serviceNest/config"
+	"serviceNest/errs"
 	"time"
 )
 
 var jwtSecret = []byte(os.Getenv("SECRET"))
 
-func GenerateJWT(userID string, role string) (string, error) {
+// GenerateJWT issues a short-lived access token for a session of the user. It is signed with the active
+// key of the keyset, named by the kid header, or with the HS256 secret when no keyset is loaded.
+func GenerateJWT(userID string, role string, sessionID string) (string, error) {
 	claims := jwt.MapClaims{}
 	claims["authorized"] = true
 	claims["user_id"] = userID
 	claims["role"] = role
-	claims["exp"] = time.Now().Add(time.Hour * 24).Unix() // 1 day expiration
+	claims["sid"] = sessionID
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(config.ACCESS_TOKEN_TTL).Unix()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	keys := loadedKeySet()
+	if keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString(jwtSecret)
+	}
+	active := keys.Active()
+	token := jwt.NewWithClaims(active.Method, claims)
+	token.Header["kid"] = active.ID
+	return token.SignedString(active.privateKey)
 }
 
-// VerifyJWT verifies the given JWT token
+// VerifyJWT verifies the given JWT token. Asymmetric tokens are checked against the keyset key named by
+// their kid; HS256 tokens are still accepted with the secret so tokens issued before the keyset was
+// loaded keep working, until the secret is removed.
 func VerifyJWT(tokenString string) (*jwt.Token, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return jwtSecret, nil
-	})
+	token, err := jwt.Parse(tokenString, verificationKey)
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid token")
@@ -35,3 +49,37 @@ func VerifyJWT(tokenString string) (*jwt.Token, error) {
 
 	return token, nil
 }
+
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	keys := loadedKeySet()
+	if token.Method == jwt.SigningMethodHS256 {
+		if keys != nil && len(jwtSecret) == 0 {
+			return nil, errors.New("invalid signing method")
+		}
+		return jwtSecret, nil
+	}
+	if keys == nil {
+		return nil, errors.New("invalid signing method")
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := keys.Key(kid)
+	if !ok || key.Method != token.Method {
+		return nil, errors.New(errs.UnknownSigningKey)
+	}
+	return key.publicKey, nil
+}
+
+// GenerateRefreshToken returns a random opaque refresh token
+func GenerateRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken returns the hex SHA-256 of a token, which is what gets stored in its place
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
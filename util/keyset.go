@@ -0,0 +1,248 @@
+package util
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"sync"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing keys
+const minRSAKeyBits = 2048
+
+// SigningKey is a loaded key of the keyset. Keys that only verify have no private half.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	Status     model.SigningKeyStatus
+	privateKey interface{}
+	publicKey  interface{}
+}
+
+// KeySet holds the keys tokens are signed and verified with
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+	jwks   model.JWKSet
+}
+
+var currentKeySet struct {
+	sync.RWMutex
+	keys *KeySet
+}
+
+// SetKeySet makes tokens be signed with the active key of keys and verified by kid against all of them.
+// A nil keyset goes back to the HS256 secret.
+func SetKeySet(keys *KeySet) {
+	currentKeySet.Lock()
+	defer currentKeySet.Unlock()
+	currentKeySet.keys = keys
+}
+
+func loadedKeySet() *KeySet {
+	currentKeySet.RLock()
+	defer currentKeySet.RUnlock()
+	return currentKeySet.keys
+}
+
+// ReloadKeySet reads the keyset file again and switches to it. The keys in use are kept when the file is
+// invalid.
+func ReloadKeySet(path string) error {
+	keys, err := LoadKeySet(path)
+	if err != nil {
+		return err
+	}
+	SetKeySet(keys)
+	return nil
+}
+
+// LoadKeySet reads a keyset file: a JSON array of model.SigningKeyConfig with exactly one active key.
+// Keys are rotated by adding the new key as "next", promoting it to "active" once verifiers have fetched
+// it, keeping the old key as "retiring" until its tokens have expired, and then removing it.
+func LoadKeySet(path string) (*KeySet, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var configs []model.SigningKeyConfig
+	if err := json.Unmarshal(file, &configs); err != nil {
+		return nil, fmt.Errorf("%s: %v", errs.InvalidSigningKeySet, err)
+	}
+	return NewKeySet(configs, filepath.Dir(path))
+}
+
+// NewKeySet loads the keys described by configs, reading relative key files from dir
+func NewKeySet(configs []model.SigningKeyConfig, dir string) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*SigningKey, len(configs)), jwks: model.JWKSet{Keys: []model.JWK{}}}
+	for _, config := range configs {
+		key, err := loadSigningKey(config, dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: %v", errs.InvalidSigningKeySet, config.ID, err)
+		}
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("%s: duplicate kid %q", errs.InvalidSigningKeySet, key.ID)
+		}
+		if key.Status == model.SigningKeyActive {
+			if set.active != nil {
+				return nil, fmt.Errorf("%s: more than one active key", errs.InvalidSigningKeySet)
+			}
+			set.active = key
+		}
+		set.keys[key.ID] = key
+		set.jwks.Keys = append(set.jwks.Keys, publicJWK(key))
+	}
+	if set.active == nil {
+		return nil, fmt.Errorf("%s: no active key", errs.InvalidSigningKeySet)
+	}
+	return set, nil
+}
+
+// Key returns the key with the given kid
+func (s *KeySet) Key(kid string) (*SigningKey, bool) {
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// Active returns the key new tokens are signed with
+func (s *KeySet) Active() *SigningKey {
+	return s.active
+}
+
+// JWKS returns the public keys of the keyset
+func (s *KeySet) JWKS() model.JWKSet {
+	return s.jwks
+}
+
+// CurrentJWKS returns the public keys tokens are verified with; it is empty while tokens are signed with
+// the HS256 secret, which is never published
+func CurrentJWKS() model.JWKSet {
+	if keys := loadedKeySet(); keys != nil {
+		return keys.JWKS()
+	}
+	return model.JWKSet{Keys: []model.JWK{}}
+}
+
+func loadSigningKey(config model.SigningKeyConfig, dir string) (*SigningKey, error) {
+	if config.ID == "" {
+		return nil, fmt.Errorf("kid is required")
+	}
+	key := &SigningKey{ID: config.ID, Status: config.Status}
+	switch config.Status {
+	case model.SigningKeyActive, model.SigningKeyNext, model.SigningKeyRetiring:
+	default:
+		return nil, fmt.Errorf("status must be active, next or retiring")
+	}
+	switch config.Algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		key.Method = jwt.SigningMethodRS256
+	case jwt.SigningMethodEdDSA.Alg():
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("alg must be RS256 or EdDSA")
+	}
+
+	if config.PrivateKeyFile != "" {
+		block, err := readPEM(config.PrivateKeyFile, dir)
+		if err != nil {
+			return nil, err
+		}
+		if key.privateKey, err = parsePrivateKey(block); err != nil {
+			return nil, err
+		}
+		key.publicKey = publicKeyOf(key.privateKey)
+	} else if config.PublicKeyFile != "" {
+		block, err := readPEM(config.PublicKeyFile, dir)
+		if err != nil {
+			return nil, err
+		}
+		if key.publicKey, err = parsePublicKey(block); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf("a private or public key file is required")
+	}
+	if key.Status != model.SigningKeyRetiring && key.privateKey == nil {
+		return nil, fmt.Errorf("%s keys need a private key file", key.Status)
+	}
+
+	switch public := key.publicKey.(type) {
+	case *rsa.PublicKey:
+		if key.Method != jwt.SigningMethodRS256 {
+			return nil, fmt.Errorf("RSA key used with %s", config.Algorithm)
+		}
+		if public.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA keys need at least %d bits", minRSAKeyBits)
+		}
+	case ed25519.PublicKey:
+		if key.Method != jwt.SigningMethodEdDSA {
+			return nil, fmt.Errorf("Ed25519 key used with %s", config.Algorithm)
+		}
+	default:
+		return nil, fmt.Errorf("only RSA and Ed25519 keys are supported")
+	}
+	return key, nil
+}
+
+func readPEM(file, dir string) (*pem.Block, error) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s holds no PEM block", file)
+	}
+	return block, nil
+}
+
+func parsePrivateKey(block *pem.Block) (interface{}, error) {
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
+
+func parsePublicKey(block *pem.Block) (interface{}, error) {
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func publicKeyOf(privateKey interface{}) interface{} {
+	switch private := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return &private.PublicKey
+	case ed25519.PrivateKey:
+		return private.Public()
+	}
+	return nil
+}
+
+func publicJWK(key *SigningKey) model.JWK {
+	jwk := model.JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Method.Alg()}
+	switch public := key.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
@@ -0,0 +1,217 @@
+package util
+
+import (
+	"bytes"
+	"encoding/csv"
+	"serviceNest/model"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Commission returns the platform's share of amount at rate basis points, rounded half up
+func Commission(amount model.Money, rate int) model.Money {
+	return ApplyBasisPoints(amount, rate)
+}
+
+// EarningJournal splits the price of a completed job into the provider's earning and the platform's
+// commission
+func EarningJournal(job model.CompletedJob, commissionRate int) *model.LedgerJournal {
+	commission := Commission(job.Price, commissionRate)
+	return &model.LedgerJournal{
+		ID:          GenerateUUID(),
+		Kind:        model.JournalEarning,
+		Reference:   job.RequestID,
+		ProviderID:  job.ProviderID,
+		RequestID:   job.RequestID,
+		ServiceID:   job.ServiceID,
+		ServiceName: job.ServiceName,
+		Currency:    job.Price.Currency,
+		OccurredAt:  job.CompletedAt.UTC(),
+		Entries: []model.LedgerEntry{
+			{Account: model.AccountHouseholderReceipts, Amount: job.Price},
+			{Account: model.AccountProviderPayable, ProviderID: job.ProviderID, Amount: negate(job.Price.Amount-commission.Amount, job.Price.Currency)},
+			{Account: model.AccountPlatformCommission, Amount: negate(commission.Amount, job.Price.Currency)},
+		},
+	}
+}
+
+// RefundJournal takes a refund of a completed job back from the provider and the platform in the same
+// proportion the job's price was split
+func RefundJournal(payment *model.Payment, request *model.ServiceRequest, refund model.Money, refundID string, commissionRate int, now time.Time) *model.LedgerJournal {
+	commission := Commission(refund, commissionRate)
+	return &model.LedgerJournal{
+		ID:          GenerateUUID(),
+		Kind:        model.JournalRefund,
+		Reference:   refundID,
+		ProviderID:  payment.ProviderID,
+		RequestID:   request.ID,
+		ServiceID:   request.ServiceID,
+		ServiceName: request.ServiceName,
+		Currency:    refund.Currency,
+		OccurredAt:  now.UTC(),
+		Entries: []model.LedgerEntry{
+			{Account: model.AccountProviderPayable, ProviderID: payment.ProviderID, Amount: model.NewMoney(refund.Amount-commission.Amount, refund.Currency)},
+			{Account: model.AccountPlatformCommission, Amount: commission},
+			{Account: model.AccountHouseholderReceipts, Amount: negate(refund.Amount, refund.Currency)},
+		},
+	}
+}
+
+// PayoutJournal pays a provider out of the platform's bank account as part of a payout batch
+func PayoutJournal(batchID, providerID string, amount model.Money, now time.Time) *model.LedgerJournal {
+	return &model.LedgerJournal{
+		ID:         GenerateUUID(),
+		Kind:       model.JournalPayout,
+		Reference:  batchID,
+		ProviderID: providerID,
+		Currency:   amount.Currency,
+		OccurredAt: now.UTC(),
+		Entries: []model.LedgerEntry{
+			{Account: model.AccountProviderPayable, ProviderID: providerID, Amount: amount},
+			{Account: model.AccountPlatformBank, Amount: negate(amount.Amount, amount.Currency)},
+		},
+	}
+}
+
+func negate(amount int64, currency string) model.Money {
+	return model.NewMoney(-amount, currency)
+}
+
+// PeriodStart returns the start, in location, of the day, week (starting Monday) or month containing t
+func PeriodStart(t time.Time, period string, location *time.Location) time.Time {
+	local := t.In(location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	switch period {
+	case "day":
+		return day
+	case "week":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, location)
+	}
+}
+
+// SummarizeEarnings adds up a provider's journals into totals per currency, per service and per period.
+// Gross is the price of the provider's completed jobs and Net what they keep after commission and
+// refunds; payouts only count towards the totals and periods.
+func SummarizeEarnings(providerID string, journals []model.LedgerJournal, period string, location *time.Location) (totals []model.EarningsTotals, byService []model.ServiceEarnings, byPeriod []model.PeriodEarnings) {
+	type serviceKey struct{ serviceID, currency string }
+	type periodKey struct {
+		start    time.Time
+		currency string
+	}
+	totalsByCurrency := map[string]*model.EarningsTotals{}
+	services := map[serviceKey]*model.ServiceEarnings{}
+	periods := map[periodKey]*model.PeriodEarnings{}
+
+	for _, journal := range journals {
+		delta := journalEarnings(providerID, journal)
+
+		if totalsByCurrency[journal.Currency] == nil {
+			totalsByCurrency[journal.Currency] = newEarningsTotals(journal.Currency)
+		}
+		addEarnings(totalsByCurrency[journal.Currency], delta)
+
+		start := PeriodStart(journal.OccurredAt, period, location)
+		pk := periodKey{start, journal.Currency}
+		if periods[pk] == nil {
+			periods[pk] = &model.PeriodEarnings{PeriodStart: start, EarningsTotals: *newEarningsTotals(journal.Currency)}
+		}
+		addEarnings(&periods[pk].EarningsTotals, delta)
+
+		if journal.Kind == model.JournalPayout {
+			continue
+		}
+		sk := serviceKey{journal.ServiceID, journal.Currency}
+		if services[sk] == nil {
+			services[sk] = &model.ServiceEarnings{ServiceID: journal.ServiceID, ServiceName: journal.ServiceName, EarningsTotals: *newEarningsTotals(journal.Currency)}
+		}
+		addEarnings(&services[sk].EarningsTotals, delta)
+	}
+
+	for _, total := range totalsByCurrency {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+	for _, service := range services {
+		byService = append(byService, *service)
+	}
+	sort.Slice(byService, func(i, j int) bool {
+		if byService[i].ServiceName != byService[j].ServiceName {
+			return byService[i].ServiceName < byService[j].ServiceName
+		}
+		return byService[i].Currency < byService[j].Currency
+	})
+	for _, p := range periods {
+		byPeriod = append(byPeriod, *p)
+	}
+	sort.Slice(byPeriod, func(i, j int) bool {
+		if !byPeriod[i].PeriodStart.Equal(byPeriod[j].PeriodStart) {
+			return byPeriod[i].PeriodStart.Before(byPeriod[j].PeriodStart)
+		}
+		return byPeriod[i].Currency < byPeriod[j].Currency
+	})
+	return totals, byService, byPeriod
+}
+
+// journalEarnings reads what one journal means for the provider. Credits to the provider's payable
+// account are earnings and debits are refunds or payouts.
+func journalEarnings(providerID string, journal model.LedgerJournal) model.EarningsTotals {
+	delta := *newEarningsTotals(journal.Currency)
+	for _, entry := range journal.Entries {
+		credit := -entry.Amount.Amount
+		switch entry.Account {
+		case model.AccountProviderPayable:
+			if entry.ProviderID != providerID {
+				continue
+			}
+			if journal.Kind == model.JournalPayout {
+				delta.PaidOut.Amount -= credit
+				continue
+			}
+			delta.Net.Amount += credit
+			if journal.Kind == model.JournalEarning {
+				delta.Gross.Amount += credit
+			}
+		case model.AccountPlatformCommission:
+			delta.Commission.Amount += credit
+			if journal.Kind == model.JournalEarning {
+				delta.Gross.Amount += credit
+			}
+		case model.AccountHouseholderReceipts:
+			if journal.Kind == model.JournalRefund {
+				delta.Refunds.Amount += credit
+			}
+		}
+	}
+	return delta
+}
+
+func newEarningsTotals(currency string) *model.EarningsTotals {
+	zero := model.NewMoney(0, currency)
+	return &model.EarningsTotals{Currency: currency, Gross: zero, Commission: zero, Refunds: zero, Net: zero, PaidOut: zero}
+}
+
+func addEarnings(total *model.EarningsTotals, delta model.EarningsTotals) {
+	total.Gross.Amount += delta.Gross.Amount
+	total.Commission.Amount += delta.Commission.Amount
+	total.Refunds.Amount += delta.Refunds.Amount
+	total.Net.Amount += delta.Net.Amount
+	total.PaidOut.Amount += delta.PaidOut.Amount
+}
+
+// PayoutBatchCSV renders a payout batch as the CSV file handed to the bank
+func PayoutBatchCSV(batch *model.PayoutBatch) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	rows := [][]string{{"batch_id", "provider_id", "currency", "amount", "amount_minor", "journal_id"}}
+	for _, payout := range batch.Payouts {
+		rows = append(rows, []string{batch.ID, payout.ProviderID, payout.Amount.Currency, payout.Amount.Decimal(),
+			strconv.FormatInt(payout.Amount.Amount, 10), payout.JournalID})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
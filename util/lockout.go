@@ -0,0 +1,58 @@
+package util
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"serviceNest/config"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LockoutDuration returns how long an account or IP address is locked out after its failures-th failed
+// attempt: nothing below the threshold, then base doubling with every further failure up to max
+func LockoutDuration(failures, threshold int, base, max time.Duration) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+	lockout := base
+	for i := threshold; i < failures; i++ {
+		lockout *= 2
+		if lockout >= max {
+			return max
+		}
+	}
+	return lockout
+}
+
+// ClientIP returns the IP address a request came from. X-Forwarded-For is only used when the app is
+// configured to run behind a proxy, and then only the entry added by the outermost trusted proxy, since
+// clients can prepend anything to it.
+func ClientIP(r *http.Request) string {
+	if os.Getenv(config.TRUST_PROXY_HEADERS_ENV) == "true" {
+		hops := 1
+		if configured, err := strconv.Atoi(os.Getenv(config.TRUSTED_PROXY_HOPS_ENV)); err == nil && configured > 0 {
+			hops = configured
+		}
+		var entries []string
+		for _, header := range r.Header.Values("X-Forwarded-For") {
+			entries = append(entries, strings.Split(header, ",")...)
+		}
+		if len(entries) >= hops {
+			if entry := strings.TrimSpace(entries[len(entries)-hops]); entry != "" {
+				return entry
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ThrottleKey normalizes an email so differently written forms of it share one failure count
+func ThrottleKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,43 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"os"
+	"serviceNest/config"
+	"serviceNest/model"
+	"strings"
+)
+
+// GenerateOTP returns a random numeric code of config.OTP_LENGTH digits
+func GenerateOTP() (string, error) {
+	var otp strings.Builder
+	for i := 0; i < config.OTP_LENGTH; i++ {
+		num, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		otp.WriteString(num.String())
+	}
+	return otp.String(), nil
+}
+
+var otpSecret = []byte(os.Getenv(config.OTP_SECRET_ENV))
+
+// HashOTP returns the hash an OTP is stored as: an HMAC keyed with the server's OTP secret, as a plain
+// hash of a six digit code is reversed by trying them all. The normalized email and purpose are hashed
+// with the code, so the same code sent to different emails or for different purposes hashes differently.
+func HashOTP(email string, purpose model.OtpPurpose, otp string) string {
+	mac := hmac.New(sha256.New, otpSecret)
+	mac.Write([]byte(fmt.Sprintf("%s:%s:%s", purpose, ThrottleKey(email), otp)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// OTPSecretConfigured reports whether OTPs are hashed with a server-side secret
+func OTPSecretConfigured() bool {
+	return len(otpSecret) > 0
+}
@@ -21,3 +21,15 @@ func ParseTime(data []uint8) (time.Time, error) {
 	}
 	return parsedTime, nil
 }
+
+// ParseNullableTime converts a nullable DATETIME column into a *time.Time, returning nil for NULL.
+func ParseNullableTime(data []uint8) (*time.Time, error) {
+	if data == nil {
+		return nil, nil
+	}
+	parsedTime, err := ParseTime(data)
+	if err != nil {
+		return nil, err
+	}
+	return &parsedTime, nil
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"errors"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"time"
+)
+
+// PrepareQuote validates the line items and validity of a quote and fills in its total. All line items
+// must be in the same currency.
+func PrepareQuote(quote *model.Quote, now time.Time) error {
+	if len(quote.LineItems) == 0 {
+		return errors.New(errs.InvalidQuote)
+	}
+	total := model.NewMoney(0, quote.LineItems[0].Amount.Currency)
+	if !model.ValidCurrency(total.Currency) {
+		return errors.New(errs.InvalidCurrency)
+	}
+	for _, item := range quote.LineItems {
+		switch item.Kind {
+		case model.LineLabour, model.LineParts, model.LineVisitFee, model.LineOther:
+		default:
+			return errors.New(errs.InvalidQuote)
+		}
+		if item.Amount.IsNegative() {
+			return errors.New(errs.InvalidAmount)
+		}
+		var err error
+		if total, err = total.Add(item.Amount); err != nil {
+			return err
+		}
+	}
+	if total.Amount <= 0 {
+		return errors.New(errs.InvalidQuote)
+	}
+	if !quote.ValidUntil.After(now) {
+		return errors.New(errs.InvalidQuoteValidity)
+	}
+	quote.Total = total
+	return nil
+}
@@ -0,0 +1,105 @@
+package util
+
+import (
+	"math"
+	"serviceNest/model"
+	"sort"
+	"time"
+)
+
+const (
+	SortRelevance  = "relevance"
+	SortRating     = "rating"
+	SortPrice      = "price"
+	SortPopularity = "popularity"
+)
+
+// ratingPriorWeight is how many reviews at ratingPriorMean every service starts with in the Bayesian
+// average. The prior is fixed below the usual platform average rather than taken from the services being
+// ranked, so a handful of top ratings never beats a long record of good ones.
+const ratingPriorWeight = 10.0
+const ratingPriorMean = 3.5
+
+// reviewRecencyHalfLife is the age at which a service's last review counts half as much towards relevance
+const reviewRecencyHalfLife = 90 * 24 * time.Hour
+
+// IsValidServiceSort reports whether sortBy is a supported ranking mode; empty keeps database order
+func IsValidServiceSort(sortBy string) bool {
+	switch sortBy {
+	case "", SortRelevance, SortRating, SortPrice, SortPopularity:
+		return true
+	}
+	return false
+}
+
+// BayesianRating shrinks avgRating towards the conservative prior, so services with few reviews cannot
+// outrank services with many reviews on the strength of one or two ratings
+func BayesianRating(avgRating float64, ratingCount int64) float64 {
+	count := float64(ratingCount)
+	return (ratingPriorWeight*ratingPriorMean + count*avgRating) / (ratingPriorWeight + count)
+}
+
+// RelevanceScore combines the Bayesian rating, how recently the service was reviewed and the
+// provider's reliability into a score between 0 and 1. Recency is weighted by how many reviews the
+// service has, as one fresh review says little. Reliability is the provider's completion rate, where
+// every penalty point from cancelling a booking counts as one more job not completed.
+func RelevanceScore(service model.Service, signals model.RankingSignals, now time.Time) float64 {
+	ratingScore := BayesianRating(service.AvgRating, service.RatingCount) / 5
+
+	var recencyScore float64
+	if lastReview, ok := signals.LastReviewDates[model.ServiceKey(service.ProviderID, service.ID)]; ok {
+		age := now.Sub(lastReview)
+		if age < 0 {
+			age = 0
+		}
+		confidence := float64(service.RatingCount) / (float64(service.RatingCount) + ratingPriorWeight)
+		recencyScore = confidence * math.Pow(0.5, float64(age)/float64(reviewRecencyHalfLife))
+	}
+
+	// Laplace smoothing keeps providers without history at a neutral 0.5
+	jobs := signals.ProviderJobs[service.ProviderID]
+	completionScore := (float64(jobs.CompletedJobs) + 1) / (float64(jobs.ApprovedJobs+jobs.PenaltyPoints) + 2)
+
+	return 0.7*ratingScore + 0.15*recencyScore + 0.15*completionScore
+}
+
+// RankServices orders services in place according to sortBy and returns them
+func RankServices(services []model.Service, sortBy string, signals model.RankingSignals, now time.Time) []model.Service {
+	switch sortBy {
+	case SortRelevance:
+		scores := make(map[string]float64, len(services))
+		for _, service := range services {
+			scores[model.ServiceKey(service.ProviderID, service.ID)] = RelevanceScore(service, signals, now)
+		}
+		sort.SliceStable(services, func(i, j int) bool {
+			return scores[model.ServiceKey(services[i].ProviderID, services[i].ID)] > scores[model.ServiceKey(services[j].ProviderID, services[j].ID)]
+		})
+	case SortRating:
+		sort.SliceStable(services, func(i, j int) bool {
+			ratingI := BayesianRating(services[i].AvgRating, services[i].RatingCount)
+			ratingJ := BayesianRating(services[j].AvgRating, services[j].RatingCount)
+			if ratingI != ratingJ {
+				return ratingI > ratingJ
+			}
+			return services[i].RatingCount > services[j].RatingCount
+		})
+	case SortPrice:
+		// Prices in different currencies are not comparable, so services are grouped by currency
+		sort.SliceStable(services, func(i, j int) bool {
+			if services[i].Price.Currency != services[j].Price.Currency {
+				return services[i].Price.Currency < services[j].Price.Currency
+			}
+			return services[i].Price.Amount < services[j].Price.Amount
+		})
+	case SortPopularity:
+		sort.SliceStable(services, func(i, j int) bool {
+			jobsI := signals.ProviderJobs[services[i].ProviderID].CompletedJobs
+			jobsJ := signals.ProviderJobs[services[j].ProviderID].CompletedJobs
+			if jobsI != jobsJ {
+				return jobsI > jobsJ
+			}
+			return services[i].RatingCount > services[j].RatingCount
+		})
+	}
+	return services
+}
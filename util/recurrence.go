@@ -0,0 +1,72 @@
+package util
+
+import (
+	"errors"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"time"
+)
+
+// ValidateRecurrenceRule checks the frequency and that the series ends on a date after first or after
+// a positive number of occurrences
+func ValidateRecurrenceRule(rule model.RecurrenceRule, first time.Time) error {
+	switch rule.Frequency {
+	case model.FrequencyWeekly, model.FrequencyBiweekly, model.FrequencyMonthly:
+	default:
+		return errors.New(errs.InvalidRecurrenceRule)
+	}
+	if rule.Count < 0 || (rule.Count == 0 && rule.Until == nil) {
+		return errors.New(errs.InvalidRecurrenceRule)
+	}
+	if rule.Until != nil && rule.Until.Before(first) {
+		return errors.New(errs.InvalidRecurrenceRule)
+	}
+	return nil
+}
+
+// SeriesOccurrence returns the start of occurrence n (0-based) of a series starting at first, and
+// false once n lies past the end of the series. Occurrences keep the wall-clock time of first in
+// location, so a 10:00 booking stays at 10:00 across DST changes. Monthly occurrences falling on a
+// day the month does not have are moved to its last day.
+func SeriesOccurrence(rule model.RecurrenceRule, first time.Time, location *time.Location, n int) (time.Time, bool) {
+	if n < 0 || (rule.Count > 0 && n >= rule.Count) {
+		return time.Time{}, false
+	}
+
+	local := first.In(location)
+	var occurrence time.Time
+	switch rule.Frequency {
+	case model.FrequencyWeekly:
+		occurrence = local.AddDate(0, 0, 7*n)
+	case model.FrequencyBiweekly:
+		occurrence = local.AddDate(0, 0, 14*n)
+	case model.FrequencyMonthly:
+		month := time.Date(local.Year(), local.Month()+time.Month(n), 1, local.Hour(), local.Minute(), local.Second(), 0, location)
+		lastDay := month.AddDate(0, 1, -1).Day()
+		day := local.Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		occurrence = month.AddDate(0, 0, day-1)
+	default:
+		return time.Time{}, false
+	}
+
+	if rule.Until != nil && occurrence.After(*rule.Until) {
+		return time.Time{}, false
+	}
+	return occurrence.UTC(), true
+}
+
+// SeriesOccurrenceIndex returns the number of the occurrence starting exactly at start
+func SeriesOccurrenceIndex(rule model.RecurrenceRule, first time.Time, location *time.Location, start time.Time) (int, bool) {
+	for n := 0; ; n++ {
+		occurrence, ok := SeriesOccurrence(rule, first, location, n)
+		if !ok || occurrence.After(start) {
+			return 0, false
+		}
+		if occurrence.Equal(start) {
+			return n, true
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package util
+
+import (
+	"html"
+	"serviceNest/model"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightOpen  = "<em>"
+	highlightClose = "</em>"
+	snippetRunes   = 120
+)
+
+// SearchField is one weighted piece of text a search query is matched against
+type SearchField struct {
+	Name   string
+	Text   string
+	Weight float64
+}
+
+// ServiceSearchFields returns the fields of a service covered by search, most significant first
+func ServiceSearchFields(service model.Service) []SearchField {
+	return []SearchField{
+		{Name: "name", Text: service.Name, Weight: 3},
+		{Name: "category", Text: service.Category, Weight: 2},
+		{Name: "provider_name", Text: service.ProviderName, Weight: 2},
+		{Name: "description", Text: service.Description, Weight: 1},
+	}
+}
+
+// SearchTokens lower-cases text and splits it into letter/digit words
+func SearchTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// maxTypos is the number of edits tolerated for a query word of n runes
+func maxTypos(n int) int {
+	switch {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// TokenMatch scores how well a query word matches a text word: 1 for an exact match,
+// 0.9 for a prefix and 0.6 for a match within the typo allowance, 0 otherwise
+func TokenMatch(queryWord, word string) float64 {
+	if queryWord == word {
+		return 1
+	}
+	if strings.HasPrefix(word, queryWord) {
+		return 0.9
+	}
+	typos := maxTypos(len([]rune(queryWord)))
+	if typos == 0 {
+		return 0
+	}
+
+	// Compare against the whole word and against its prefixes around the query length,
+	// so "plumbr" matches both "plumber" and "plumbing"
+	q, w := []rune(queryWord), []rune(word)
+	best := levenshtein(q, w)
+	for _, n := range []int{len(q) - 1, len(q), len(q) + 1} {
+		if n > 0 && n < len(w) {
+			if d := levenshtein(q, w[:n]); d < best {
+				best = d
+			}
+		}
+	}
+	if best <= typos {
+		return 0.6
+	}
+	return 0
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// MatchFields scores fields against a query and returns highlighted snippets for every field
+// that matched. Each query word contributes its best weighted match, and the total is scaled
+// by the share of query words that matched anywhere. A score of 0 means no match.
+func MatchFields(query string, fields []SearchField) (float64, map[string]string) {
+	queryWords := SearchTokens(query)
+	if len(queryWords) == 0 {
+		return 0, nil
+	}
+
+	score, matched := 0.0, 0
+	for _, queryWord := range queryWords {
+		best := 0.0
+		for _, field := range fields {
+			for _, word := range SearchTokens(field.Text) {
+				if s := field.Weight * TokenMatch(queryWord, word); s > best {
+					best = s
+				}
+			}
+		}
+		if best > 0 {
+			matched++
+			score += best
+		}
+	}
+	if matched == 0 {
+		return 0, nil
+	}
+	score *= float64(matched) / float64(len(queryWords))
+
+	highlights := make(map[string]string)
+	for _, field := range fields {
+		if snippet, ok := highlight(field.Text, queryWords); ok {
+			highlights[field.Name] = snippet
+		}
+	}
+	return score, highlights
+}
+
+// highlight wraps every word of text matching a query word in <em> tags and trims long text to a
+// snippet around the first match. The text is HTML-escaped, so only the tags added here are markup.
+func highlight(text string, queryWords []string) (string, bool) {
+	runes := []rune(text)
+	var builder strings.Builder
+	firstMatch, matchedAny := -1, false
+
+	for i := 0; i < len(runes); {
+		if !unicode.IsLetter(runes[i]) && !unicode.IsDigit(runes[i]) {
+			builder.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+		end := i
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+			end++
+		}
+		word := string(runes[i:end])
+		if matchesAny(strings.ToLower(word), queryWords) {
+			if !matchedAny {
+				firstMatch = i
+				matchedAny = true
+			}
+			builder.WriteString(highlightOpen + word + highlightClose)
+		} else {
+			builder.WriteString(word)
+		}
+		i = end
+	}
+	if !matchedAny {
+		return "", false
+	}
+	if len(runes) <= snippetRunes {
+		return builder.String(), true
+	}
+	return snippet(runes, queryWords, firstMatch), true
+}
+
+// snippet re-highlights a window of roughly snippetRunes runes starting shortly before the first match
+func snippet(runes []rune, queryWords []string, firstMatch int) string {
+	start := firstMatch - snippetRunes/4
+	if start < 0 {
+		start = 0
+	}
+	for start > 0 && unicode.IsLetter(runes[start-1]) {
+		start--
+	}
+	end := start + snippetRunes
+	if end > len(runes) {
+		end = len(runes)
+	}
+	for end < len(runes) && unicode.IsLetter(runes[end]) {
+		end++
+	}
+
+	window, _ := highlight(string(runes[start:end]), queryWords)
+	if start > 0 {
+		window = "…" + window
+	}
+	if end < len(runes) {
+		window += "…"
+	}
+	return window
+}
+
+func matchesAny(word string, queryWords []string) bool {
+	for _, queryWord := range queryWords {
+		if TokenMatch(queryWord, word) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ScoreServices matches every service against the query and returns the matching ones, best first
+func ScoreServices(query string, services []model.Service) []model.ServiceSearchResult {
+	var results []model.ServiceSearchResult
+	for _, service := range services {
+		score, highlights := MatchFields(query, ServiceSearchFields(service))
+		if score > 0 {
+			results = append(results, model.ServiceSearchResult{Service: service, Score: score, Highlights: highlights})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Service.AvgRating > results[j].Service.AvgRating
+	})
+	return results
+}
+
+// FullTextBooleanQuery turns free text into a boolean-mode query for the ngram FULLTEXT indexes.
+// Each word is kept whole, ranked higher, and split into its bigrams as optional terms: a word within
+// its typo allowance of a text word shares at least one bigram with it wherever the typos are, so
+// candidates are the same ones ScoreServices accepts and it does the exact ranking afterwards.
+// Single-rune words are dropped because they are shorter than an ngram token.
+func FullTextBooleanQuery(query string) string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, word := range SearchTokens(query) {
+		runes := []rune(word)
+		if len(runes) < 2 {
+			continue
+		}
+		terms = append(terms, ">"+word)
+		for i := 0; i+2 <= len(runes); i++ {
+			bigram := string(runes[i : i+2])
+			if !seen[bigram] {
+				seen[bigram] = true
+				terms = append(terms, bigram)
+			}
+		}
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,136 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"serviceNest/model"
+	"time"
+)
+
+// ScheduleLocation returns the timezone the provider's working hours are expressed in
+func ScheduleLocation(schedule *model.ProviderSchedule) *time.Location {
+	return LoadUserLocation(schedule.Timezone)
+}
+
+// ParseClock converts a "15:04" clock time into minutes after midnight
+func ParseClock(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid clock time %q: %v", clock, err)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// ValidateWorkingHours checks that every window is well formed and that windows on the same day do not overlap
+func ValidateWorkingHours(hours []model.WorkingHours) error {
+	windows := make(map[time.Weekday][][2]int)
+	for _, window := range hours {
+		if window.Weekday < time.Sunday || window.Weekday > time.Saturday {
+			return errors.New(errs.InvalidWorkingHours)
+		}
+		start, err := ParseClock(window.Start)
+		if err != nil {
+			return errors.New(errs.InvalidWorkingHours)
+		}
+		end, err := ParseClock(window.End)
+		if err != nil || end <= start {
+			return errors.New(errs.InvalidWorkingHours)
+		}
+		for _, existing := range windows[window.Weekday] {
+			if start < existing[1] && existing[0] < end {
+				return errors.New(errs.InvalidWorkingHours)
+			}
+		}
+		windows[window.Weekday] = append(windows[window.Weekday], [2]int{start, end})
+	}
+	return nil
+}
+
+// workingWindows returns the working ranges of the day containing date. A provider that has not
+// published working hours is treated as working the whole day.
+func workingWindows(schedule *model.ProviderSchedule, date time.Time) []model.TimeRange {
+	location := ScheduleLocation(schedule)
+	local := date.In(location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+
+	if len(schedule.WorkingHours) == 0 {
+		return []model.TimeRange{{Start: midnight, End: midnight.AddDate(0, 0, 1)}}
+	}
+
+	var windows []model.TimeRange
+	for _, window := range schedule.WorkingHours {
+		if window.Weekday != midnight.Weekday() {
+			continue
+		}
+		start, errStart := ParseClock(window.Start)
+		end, errEnd := ParseClock(window.End)
+		if errStart != nil || errEnd != nil {
+			continue
+		}
+		windows = append(windows, model.TimeRange{
+			Start: midnight.Add(time.Duration(start) * time.Minute),
+			End:   midnight.Add(time.Duration(end) * time.Minute),
+		})
+	}
+	return windows
+}
+
+// SlotLength returns the provider's slot length, or the default when none is set
+func SlotLength(schedule *model.ProviderSchedule) time.Duration {
+	if schedule.SlotMinutes <= 0 {
+		return time.Duration(config.DEFAULT_SLOT_MINUTES) * time.Minute
+	}
+	return time.Duration(schedule.SlotMinutes) * time.Minute
+}
+
+// isBlocked reports whether the range overlaps a time-off block or an existing booking
+func isBlocked(schedule *model.ProviderSchedule, slot model.TimeRange, booked []model.TimeRange) bool {
+	for _, timeOff := range schedule.TimeOff {
+		if slot.Overlaps(model.TimeRange{Start: timeOff.Start, End: timeOff.End}) {
+			return true
+		}
+	}
+	for _, booking := range booked {
+		if slot.Overlaps(booking) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateSlots lays the provider's slots over the working windows of date, marking those that are in
+// the past, on time off or already booked as unavailable
+func GenerateSlots(schedule *model.ProviderSchedule, date time.Time, booked []model.TimeRange, now time.Time) []model.Slot {
+	slots := []model.Slot{}
+	length := SlotLength(schedule)
+	for _, window := range workingWindows(schedule, date) {
+		for start := window.Start; !start.Add(length).After(window.End); start = start.Add(length) {
+			slot := model.TimeRange{Start: start, End: start.Add(length)}
+			slots = append(slots, model.Slot{
+				Start:     slot.Start,
+				End:       slot.End,
+				Available: schedule.Available && !start.Before(now) && !isBlocked(schedule, slot, booked),
+			})
+		}
+	}
+	return slots
+}
+
+// FitsSchedule reports whether the job lies inside the provider's working hours without touching time
+// off or another booking
+func FitsSchedule(schedule *model.ProviderSchedule, job model.TimeRange, booked []model.TimeRange) bool {
+	if !schedule.Available {
+		return false
+	}
+	if isBlocked(schedule, job, booked) {
+		return false
+	}
+	for _, window := range workingWindows(schedule, job.Start) {
+		if !job.Start.Before(window.Start) && !job.End.After(window.End) {
+			return true
+		}
+	}
+	return false
+}
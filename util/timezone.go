@@ -0,0 +1,62 @@
+package util
+
+import (
+	"errors"
+	"net/http"
+	"serviceNest/config"
+	"serviceNest/errs"
+	"time"
+)
+
+// legacyTimeLayout is the zone-less layout older clients send; it is read in the user's timezone
+const legacyTimeLayout = "2006-01-02 15:04"
+
+// DefaultLocation is the timezone used for users who have not chosen one
+func DefaultLocation() *time.Location {
+	location, err := time.LoadLocation(config.DEFAULT_TIMEZONE)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// ValidTimezone reports whether name is a loadable IANA timezone
+func ValidTimezone(name string) bool {
+	if name == "" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// LoadUserLocation loads a user's preferred timezone, falling back to the default one
+func LoadUserLocation(name string) *time.Location {
+	if name == "" {
+		return DefaultLocation()
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return DefaultLocation()
+	}
+	return location
+}
+
+// UserLocation returns the timezone the timezone middleware stored on the request
+func UserLocation(r *http.Request) *time.Location {
+	if location, ok := r.Context().Value("timezone").(*time.Location); ok && location != nil {
+		return location
+	}
+	return DefaultLocation()
+}
+
+// ParseUserTime parses an RFC 3339 timestamp, or a legacy "2006-01-02 15:04" value read in location,
+// and returns it in UTC
+func ParseUserTime(value string, location *time.Location) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.UTC(), nil
+	}
+	if parsed, err := time.ParseInLocation(legacyTimeLayout, value, location); err == nil {
+		return parsed.UTC(), nil
+	}
+	return time.Time{}, errors.New(errs.InvalidTimeFormat)
+}